@@ -0,0 +1,152 @@
+package analytics
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/shubham-tomar/feature-toggler/auth"
+	"github.com/shubham-tomar/feature-toggler/evaluation"
+)
+
+const (
+	maxBatchSize     = 1000
+	maxVariantLength = 64
+	maxContextKey    = 256
+	// Clocks of SDK hosts drift, events a little in the future are accepted as now
+	maxClockSkew = 5 * time.Minute
+)
+
+type eventsRequest struct {
+	Events []submittedEvent `json:"events"`
+}
+
+type submittedEvent struct {
+	FlagKey    string    `json:"flagKey"`
+	Variant    string    `json:"variant"`
+	Timestamp  time.Time `json:"timestamp"`
+	ContextKey string    `json:"contextKey"`
+}
+
+type eventsResponse struct {
+	Accepted int `json:"accepted"`
+	// Ignored counts events for flags that do not exist or are not visible to the SDK key
+	Ignored int `json:"ignored"`
+}
+
+type errorResponse struct {
+	Error string `json:"error"`
+}
+
+// Handler accepts evaluation events reported by SDKs that evaluate flags locally, and goal events for experiments
+type Handler struct {
+	Evaluator *evaluation.Evaluator
+	Recorder  *Recorder
+}
+
+// Register mounts the routes on the given router group, usually "/analytics/v1".
+// The group must authenticate requests with auth.RequireSdkKey, the key selects the project and environment.
+func (h *Handler) Register(r gin.IRouter) {
+	r.POST("/events", h.submitEvents)
+	r.POST("/track", h.track)
+}
+
+func (h *Handler) submitEvents(c *gin.Context) {
+	scope := evaluation.ScopeForKey(auth.SdkKey(c))
+
+	var req eventsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, errorResponse{Error: fmt.Sprintf("invalid request body: %v", err)})
+		return
+	}
+
+	if len(req.Events) > maxBatchSize {
+		c.JSON(http.StatusBadRequest, errorResponse{Error: fmt.Sprintf("a batch holds at most %d events", maxBatchSize)})
+		return
+	}
+
+	now := time.Now()
+	for i, e := range req.Events {
+		if err := validate(e, now); err != nil {
+			c.JSON(http.StatusBadRequest, errorResponse{Error: fmt.Sprintf("event %d: %v", i, err)})
+			return
+		}
+	}
+
+	experiments, err := h.Evaluator.Experiments(c.Request.Context(), scope)
+	if err != nil {
+		log.Printf("analytics: failed to get experiments: %v", err)
+		c.JSON(http.StatusInternalServerError, errorResponse{Error: "failed to record events"})
+		return
+	}
+
+	// Batches usually repeat a handful of flags, look each one up once
+	flagIDs := map[string]string{}
+	var events []Event
+	var resp eventsResponse
+	for _, e := range req.Events {
+		id, ok := flagIDs[e.FlagKey]
+		if !ok {
+			flag, err := h.Evaluator.Lookup(c.Request.Context(), scope, e.FlagKey)
+			if err != nil && !errors.Is(err, evaluation.ErrFlagNotFound) {
+				log.Printf("analytics: failed to look up flag %s: %v", e.FlagKey, err)
+				c.JSON(http.StatusInternalServerError, errorResponse{Error: "failed to record events"})
+				return
+			}
+			if flag != nil {
+				id = flag.ID
+			}
+			flagIDs[e.FlagKey] = id
+		}
+
+		if id == "" {
+			resp.Ignored++
+			continue
+		}
+
+		timestamp := e.Timestamp
+		if timestamp.IsZero() || timestamp.After(now) {
+			timestamp = now
+		}
+
+		event := Event{
+			FlagID:      id,
+			Environment: scope.Environment,
+			Variant:     e.Variant,
+			Timestamp:   timestamp,
+			ContextKey:  e.ContextKey,
+		}
+
+		// Assignment is deterministic, so the variant a local evaluation reports can be checked
+		if e.ContextKey != "" {
+			if experiment, variant, enrolled := experiments.Assign(id, e.ContextKey); enrolled && variant == e.Variant {
+				event.ExperimentID = experiment.ID
+			}
+		}
+
+		events = append(events, event)
+	}
+
+	h.Recorder.Record(events...)
+	resp.Accepted = len(events)
+	c.JSON(http.StatusAccepted, resp)
+}
+
+func validate(e submittedEvent, now time.Time) error {
+	switch {
+	case e.FlagKey == "":
+		return errors.New("flagKey is required")
+	case e.Variant == "":
+		return errors.New("variant is required")
+	case len(e.Variant) > maxVariantLength:
+		return fmt.Errorf("variant is longer than %d characters", maxVariantLength)
+	case len(e.ContextKey) > maxContextKey:
+		return fmt.Errorf("contextKey is longer than %d characters", maxContextKey)
+	case e.Timestamp.After(now.Add(maxClockSkew)):
+		return errors.New("timestamp is in the future")
+	}
+	return nil
+}
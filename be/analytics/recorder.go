@@ -0,0 +1,213 @@
+package analytics
+
+import (
+	"context"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/shubham-tomar/feature-toggler/db"
+	"github.com/shubham-tomar/feature-toggler/graphQl/model"
+)
+
+// BucketSize is the width of the time buckets evaluations are counted in
+const BucketSize = time.Hour
+
+// Flush early once this many counters, impressions and exposures are waiting, so bursts cannot grow the buffer without bound
+const maxPending = 10000
+
+// Event is a single evaluation of a flag
+type Event struct {
+	FlagID      string
+	Environment model.Environment
+	Variant     string
+	Timestamp   time.Time
+	// ContextKey identifies the subject the flag was evaluated for, events without one are only counted
+	ContextKey string
+	// ExperimentID is set when the context was enrolled in an experiment, the event then exposes it
+	ExperimentID string
+}
+
+type countKey struct {
+	flagID      string
+	environment model.Environment
+	variant     string
+	bucketStart time.Time
+}
+
+type impressionKey struct {
+	flagID      string
+	environment model.Environment
+	contextKey  string
+}
+
+type exposureKey struct {
+	experimentID string
+	contextKey   string
+}
+
+// Recorder aggregates evaluation events in memory and periodically adds them to the counters in storage.
+// Events still buffered when the process dies are lost, the counts are meant for analytics, not billing.
+type Recorder struct {
+	storage   db.Storage
+	interval  time.Duration
+	retention time.Duration
+
+	mu          sync.Mutex
+	counts      map[countKey]*db.EvaluationCount
+	impressions map[impressionKey]*db.Impression
+	exposures   map[exposureKey]*db.Exposure
+
+	full      chan struct{}
+	stop      chan struct{}
+	done      chan struct{}
+	lastPrune time.Time
+}
+
+// NewRecorder creates a recorder flushing every interval, counts older than the retention are deleted.
+// A zero retention keeps counts forever.
+func NewRecorder(storage db.Storage, interval, retention time.Duration) *Recorder {
+	return &Recorder{
+		storage:     storage,
+		interval:    interval,
+		retention:   retention,
+		counts:      map[countKey]*db.EvaluationCount{},
+		impressions: map[impressionKey]*db.Impression{},
+		exposures:   map[exposureKey]*db.Exposure{},
+		full:        make(chan struct{}, 1),
+		stop:        make(chan struct{}),
+		done:        make(chan struct{}),
+	}
+}
+
+// Record buffers events until the next flush, it is safe to call on a nil recorder
+func (r *Recorder) Record(events ...Event) {
+	if r == nil {
+		return
+	}
+
+	r.mu.Lock()
+	for _, e := range events {
+		at := e.Timestamp.UTC()
+		if e.Timestamp.IsZero() {
+			at = time.Now().UTC()
+		}
+
+		key := countKey{e.FlagID, e.Environment, e.Variant, at.Truncate(BucketSize)}
+		c, ok := r.counts[key]
+		if !ok {
+			c = &db.EvaluationCount{FlagID: e.FlagID, Environment: e.Environment, Variant: e.Variant, BucketStart: key.bucketStart}
+			r.counts[key] = c
+		}
+		c.Count++
+		if at.After(c.LastEvaluatedAt) {
+			c.LastEvaluatedAt = at
+		}
+
+		if e.ContextKey == "" {
+			continue
+		}
+		ik := impressionKey{e.FlagID, e.Environment, e.ContextKey}
+		if i, ok := r.impressions[ik]; !ok || at.After(i.SeenAt) {
+			r.impressions[ik] = &db.Impression{FlagID: e.FlagID, Environment: e.Environment, ContextKey: e.ContextKey, Variant: e.Variant, SeenAt: at}
+		}
+
+		if e.ExperimentID == "" {
+			continue
+		}
+		xk := exposureKey{e.ExperimentID, e.ContextKey}
+		if x, ok := r.exposures[xk]; !ok || at.Before(x.ExposedAt) {
+			r.exposures[xk] = &db.Exposure{ExperimentID: e.ExperimentID, ContextKey: e.ContextKey, Variant: e.Variant, ExposedAt: at}
+		}
+	}
+	pending := len(r.counts) + len(r.impressions) + len(r.exposures)
+	r.mu.Unlock()
+
+	if pending >= maxPending {
+		select {
+		case r.full <- struct{}{}:
+		default:
+		}
+	}
+}
+
+// Start flushes in the background until Stop is called
+func (r *Recorder) Start() {
+	go func() {
+		defer close(r.done)
+
+		ticker := time.NewTicker(r.interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ticker.C:
+			case <-r.full:
+			case <-r.stop:
+				r.flushAndLog()
+				return
+			}
+			r.flushAndLog()
+			r.prune()
+		}
+	}()
+}
+
+// Stop flushes the remaining events and waits for the background loop to end
+func (r *Recorder) Stop() {
+	close(r.stop)
+	<-r.done
+}
+
+// Flush writes the buffered events to storage, on failure they are dropped
+func (r *Recorder) Flush(ctx context.Context) error {
+	r.mu.Lock()
+	counts, impressions, exposures := r.counts, r.impressions, r.exposures
+	r.counts = map[countKey]*db.EvaluationCount{}
+	r.impressions = map[impressionKey]*db.Impression{}
+	r.exposures = map[exposureKey]*db.Exposure{}
+	r.mu.Unlock()
+
+	if len(counts) > 0 || len(impressions) > 0 {
+		if err := r.storage.RecordEvaluations(ctx, values(counts), values(impressions)); err != nil {
+			return err
+		}
+	}
+
+	if len(exposures) == 0 {
+		return nil
+	}
+	return r.storage.RecordExposures(ctx, values(exposures))
+}
+
+func (r *Recorder) flushAndLog() {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	if err := r.Flush(ctx); err != nil {
+		log.Printf("analytics: failed to record evaluations: %v", err)
+	}
+}
+
+// prune deletes expired counts at most once per bucket
+func (r *Recorder) prune() {
+	if r.retention <= 0 || time.Since(r.lastPrune) < BucketSize {
+		return
+	}
+	r.lastPrune = time.Now()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	if err := r.storage.DeleteEvaluationsBefore(ctx, time.Now().Add(-r.retention)); err != nil {
+		log.Printf("analytics: failed to delete expired evaluations: %v", err)
+	}
+}
+
+func values[K comparable, V any](m map[K]V) []V {
+	vs := make([]V, 0, len(m))
+	for _, v := range m {
+		vs = append(vs, v)
+	}
+	return vs
+}
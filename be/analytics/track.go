@@ -0,0 +1,109 @@
+package analytics
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/shubham-tomar/feature-toggler/auth"
+	"github.com/shubham-tomar/feature-toggler/db"
+	"github.com/shubham-tomar/feature-toggler/evaluation"
+)
+
+const maxEventKeyLength = 64
+
+type trackRequest struct {
+	Events []trackedEvent `json:"events"`
+}
+
+// trackedEvent is a goal event, such as a signup or a purchase, done by the subject of a context
+type trackedEvent struct {
+	EventKey   string    `json:"eventKey"`
+	ContextKey string    `json:"contextKey"`
+	Timestamp  time.Time `json:"timestamp"`
+}
+
+type trackResponse struct {
+	Accepted int `json:"accepted"`
+}
+
+// track records goal events, experiments count an exposed context as converted once it tracks the event of a metric
+func (h *Handler) track(c *gin.Context) {
+	scope := evaluation.ScopeForKey(auth.SdkKey(c))
+
+	var req trackRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, errorResponse{Error: fmt.Sprintf("invalid request body: %v", err)})
+		return
+	}
+
+	if len(req.Events) > maxBatchSize {
+		c.JSON(http.StatusBadRequest, errorResponse{Error: fmt.Sprintf("a batch holds at most %d events", maxBatchSize)})
+		return
+	}
+
+	now := time.Now()
+	type key struct{ eventKey, contextKey string }
+	aggregated := map[key]*db.TrackEvent{}
+	for i, e := range req.Events {
+		if err := validateTracked(e, now); err != nil {
+			c.JSON(http.StatusBadRequest, errorResponse{Error: fmt.Sprintf("event %d: %v", i, err)})
+			return
+		}
+
+		timestamp := e.Timestamp
+		if timestamp.IsZero() || timestamp.After(now) {
+			timestamp = now
+		}
+
+		k := key{e.EventKey, e.ContextKey}
+		t, ok := aggregated[k]
+		if !ok {
+			t = &db.TrackEvent{
+				ProjectID:   scope.ProjectID,
+				Environment: scope.Environment,
+				EventKey:    e.EventKey,
+				ContextKey:  e.ContextKey,
+				FirstSeenAt: timestamp,
+				LastSeenAt:  timestamp,
+			}
+			aggregated[k] = t
+		}
+		t.Count++
+		if timestamp.Before(t.FirstSeenAt) {
+			t.FirstSeenAt = timestamp
+		}
+		if timestamp.After(t.LastSeenAt) {
+			t.LastSeenAt = timestamp
+		}
+	}
+
+	if len(aggregated) > 0 {
+		if err := h.Evaluator.Storage.RecordTrackEvents(c.Request.Context(), values(aggregated)); err != nil {
+			log.Printf("analytics: failed to record track events: %v", err)
+			c.JSON(http.StatusInternalServerError, errorResponse{Error: "failed to record events"})
+			return
+		}
+	}
+
+	c.JSON(http.StatusAccepted, trackResponse{Accepted: len(req.Events)})
+}
+
+func validateTracked(e trackedEvent, now time.Time) error {
+	switch {
+	case e.EventKey == "":
+		return errors.New("eventKey is required")
+	case len(e.EventKey) > maxEventKeyLength:
+		return fmt.Errorf("eventKey is longer than %d characters", maxEventKeyLength)
+	case e.ContextKey == "":
+		return errors.New("contextKey is required")
+	case len(e.ContextKey) > maxContextKey:
+		return fmt.Errorf("contextKey is longer than %d characters", maxContextKey)
+	case e.Timestamp.After(now.Add(maxClockSkew)):
+		return errors.New("timestamp is in the future")
+	}
+	return nil
+}
@@ -0,0 +1,135 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/shubham-tomar/feature-toggler/db"
+	userctx "github.com/shubham-tomar/feature-toggler/graphQl/context"
+	"github.com/shubham-tomar/feature-toggler/graphQl/model"
+)
+
+const accessTokenPrefix = "ft_pat_"
+
+type ctxKey string
+
+const scopesKey = ctxKey("scopes")
+
+// Scopes are ordered, each one grants the operations of the ones below it
+var scopeRank = map[model.TokenScope]int{
+	model.TokenScopeRead:   1,
+	model.TokenScopeToggle: 2,
+	model.TokenScopeAdmin:  3,
+}
+
+// NewAccessToken generates a random secret for an access token and fills in its display prefix.
+// It returns the secret, which is never stored, and the hash to store instead.
+func NewAccessToken(token *model.AccessToken) (secret string, tokenHash string, err error) {
+	secret, err = newSecret(accessTokenPrefix)
+	if err != nil {
+		return "", "", err
+	}
+
+	token.Prefix = secret[:displayedPrefixLength]
+	return secret, HashSecret(secret), nil
+}
+
+// IsTokenActive reports whether an access token may still be used
+func IsTokenActive(token *model.AccessToken, now time.Time) bool {
+	return active(token.RevokedAt, token.ExpiresAt, now)
+}
+
+// Authenticate resolves the user behind a GraphQL request.
+// Requests with an access token act as the token's user, limited to its scopes, and
+// requests with a single sign-on session act as the logged in user. Requests without
+// credentials are rejected when login is required and act as the mock user otherwise.
+func Authenticate(storage db.Storage, requireLogin bool) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctx := c.Request.Context()
+
+		if secret := credential(c.Request); secret != "" {
+			authenticateToken(c, storage, secret)
+			return
+		}
+
+		if secret, err := c.Cookie(SessionCookie); err == nil && secret != "" {
+			user, err := storage.GetSessionUser(ctx, HashSecret(secret))
+			if errors.Is(err, db.ErrNotFound) {
+				c.AbortWithStatus(http.StatusUnauthorized)
+				return
+			}
+			if err != nil {
+				log.Printf("auth: failed to look up session: %v", err)
+				c.AbortWithStatus(http.StatusInternalServerError)
+				return
+			}
+
+			c.Request = c.Request.WithContext(userctx.WithUser(ctx, user))
+			c.Next()
+			return
+		}
+
+		if requireLogin {
+			c.AbortWithStatus(http.StatusUnauthorized)
+			return
+		}
+
+		c.Request = c.Request.WithContext(userctx.WithUser(ctx, userctx.MockUser))
+		c.Next()
+	}
+}
+
+func authenticateToken(c *gin.Context, storage db.Storage, secret string) {
+	ctx := c.Request.Context()
+
+	token, err := storage.GetAccessTokenByHash(ctx, HashSecret(secret))
+	if errors.Is(err, db.ErrNotFound) {
+		c.AbortWithStatus(http.StatusUnauthorized)
+		return
+	}
+	if err != nil {
+		log.Printf("auth: failed to look up access token: %v", err)
+		c.AbortWithStatus(http.StatusInternalServerError)
+		return
+	}
+
+	now := time.Now()
+	if !IsTokenActive(token, now) {
+		c.AbortWithStatus(http.StatusUnauthorized)
+		return
+	}
+
+	if err := storage.TouchAccessToken(ctx, token.ID, now); err != nil {
+		log.Printf("auth: failed to record access token usage: %v", err)
+	}
+
+	ctx = userctx.WithUser(ctx, token.User)
+	ctx = WithScopes(ctx, token.Scopes)
+	c.Request = c.Request.WithContext(ctx)
+	c.Next()
+}
+
+// WithScopes limits the operations allowed for the rest of the request
+func WithScopes(ctx context.Context, scopes []model.TokenScope) context.Context {
+	return context.WithValue(ctx, scopesKey, scopes)
+}
+
+// HasScope reports whether the request may perform operations requiring the scope.
+// Requests that were not limited with WithScopes may perform every operation.
+func HasScope(ctx context.Context, required model.TokenScope) bool {
+	scopes, limited := ctx.Value(scopesKey).([]model.TokenScope)
+	if !limited {
+		return true
+	}
+
+	for _, scope := range scopes {
+		if scopeRank[scope] >= scopeRank[required] {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,383 @@
+package auth
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/coreos/go-oidc/v3/oidc"
+	"github.com/gin-gonic/gin"
+	"github.com/shubham-tomar/feature-toggler/db"
+	"github.com/shubham-tomar/feature-toggler/graphQl/model"
+	"golang.org/x/oauth2"
+)
+
+// SessionCookie holds the session of users logged in through single sign-on
+const SessionCookie = "ft_session"
+
+const (
+	sessionPrefix   = "ft_ses_"
+	stateCookie     = "ft_oidc_state"
+	stateCookieTTL  = 10 * time.Minute
+	stateCookiePath = "/"
+)
+
+// Higher roles win when several groups grant a role in the same project
+var roleRank = map[model.Role]int{
+	model.RoleViewer:    1,
+	model.RoleDeveloper: 2,
+	model.RoleAdmin:     3,
+}
+
+// OIDCConfig configures single sign-on through an OpenID Connect provider
+type OIDCConfig struct {
+	IssuerURL    string
+	ClientID     string
+	ClientSecret string
+	// RedirectURL is the callback registered with the provider, e.g. "http://localhost:8080/auth/oidc/callback"
+	RedirectURL string
+	Scopes      []string
+	// GroupsClaim names the ID token claim listing the groups of the user
+	GroupsClaim string
+	// GroupRoles grants project roles to members of identity provider groups
+	GroupRoles []GroupRole
+	SessionTTL time.Duration
+	// PostLoginRedirect is where the browser is sent once logged in
+	PostLoginRedirect string
+}
+
+// GroupRole grants a role in a project to the members of a group
+type GroupRole struct {
+	Group     string
+	ProjectID string
+	Role      model.Role
+}
+
+// ParseGroupRoles parses comma separated "group:projectId:ROLE" mappings
+func ParseGroupRoles(value string) ([]GroupRole, error) {
+	var mappings []GroupRole
+	for _, entry := range strings.Split(value, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+
+		parts := strings.Split(entry, ":")
+		if len(parts) != 3 {
+			return nil, fmt.Errorf("invalid group role mapping %q, expected group:projectId:ROLE", entry)
+		}
+
+		role := model.Role(strings.ToUpper(parts[2]))
+		if !role.IsValid() {
+			return nil, fmt.Errorf("invalid role %q in group role mapping %q", parts[2], entry)
+		}
+
+		mappings = append(mappings, GroupRole{Group: parts[0], ProjectID: parts[1], Role: role})
+	}
+
+	return mappings, nil
+}
+
+// OIDC implements the authorization code flow with PKCE and issues login sessions
+type OIDC struct {
+	config   OIDCConfig
+	storage  db.Storage
+	verifier *oidc.IDTokenVerifier
+	oauth2   oauth2.Config
+}
+
+type loginState struct {
+	State    string `json:"state"`
+	Nonce    string `json:"nonce"`
+	Verifier string `json:"verifier"`
+}
+
+// NewOIDC discovers the provider configuration from its issuer URL
+func NewOIDC(ctx context.Context, storage db.Storage, config OIDCConfig) (*OIDC, error) {
+	provider, err := oidc.NewProvider(ctx, config.IssuerURL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to discover oidc provider: %w", err)
+	}
+
+	scopes := config.Scopes
+	if len(scopes) == 0 {
+		scopes = []string{oidc.ScopeOpenID, "profile", "email"}
+	}
+
+	if config.SessionTTL <= 0 {
+		config.SessionTTL = 24 * time.Hour
+	}
+
+	if config.PostLoginRedirect == "" {
+		config.PostLoginRedirect = "/"
+	}
+
+	return &OIDC{
+		config:   config,
+		storage:  storage,
+		verifier: provider.Verifier(&oidc.Config{ClientID: config.ClientID}),
+		oauth2: oauth2.Config{
+			ClientID:     config.ClientID,
+			ClientSecret: config.ClientSecret,
+			RedirectURL:  config.RedirectURL,
+			Endpoint:     provider.Endpoint(),
+			Scopes:       scopes,
+		},
+	}, nil
+}
+
+// Register mounts the login routes on the given router group, usually "/auth/oidc"
+func (o *OIDC) Register(r gin.IRouter) {
+	r.GET("/login", o.login)
+	r.GET("/callback", o.callback)
+	r.POST("/logout", o.logout)
+}
+
+func (o *OIDC) login(c *gin.Context) {
+	state, err := newSecret("")
+	if err != nil {
+		c.AbortWithStatus(http.StatusInternalServerError)
+		return
+	}
+	nonce, err := newSecret("")
+	if err != nil {
+		c.AbortWithStatus(http.StatusInternalServerError)
+		return
+	}
+
+	ls := loginState{State: state, Nonce: nonce, Verifier: oauth2.GenerateVerifier()}
+	encoded, err := json.Marshal(ls)
+	if err != nil {
+		c.AbortWithStatus(http.StatusInternalServerError)
+		return
+	}
+
+	// Lax so that the cookie comes back on the provider's top-level redirect
+	c.SetSameSite(http.SameSiteLaxMode)
+	c.SetCookie(stateCookie, base64.RawURLEncoding.EncodeToString(encoded), int(stateCookieTTL.Seconds()), stateCookiePath, "", o.secure(), true)
+
+	c.Redirect(http.StatusFound, o.oauth2.AuthCodeURL(ls.State, oidc.Nonce(ls.Nonce), oauth2.S256ChallengeOption(ls.Verifier)))
+}
+
+func (o *OIDC) callback(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	ls, err := o.loginState(c)
+	if err != nil {
+		c.String(http.StatusBadRequest, "login expired, please try again")
+		return
+	}
+
+	if errCode := c.Query("error"); errCode != "" {
+		c.String(http.StatusUnauthorized, "login failed: %s %s", errCode, c.Query("error_description"))
+		return
+	}
+
+	if c.Query("state") != ls.State {
+		c.String(http.StatusBadRequest, "login state does not match, please try again")
+		return
+	}
+
+	token, err := o.oauth2.Exchange(ctx, c.Query("code"), oauth2.VerifierOption(ls.Verifier))
+	if err != nil {
+		log.Printf("oidc: failed to exchange authorization code: %v", err)
+		c.String(http.StatusUnauthorized, "login failed")
+		return
+	}
+
+	rawIDToken, ok := token.Extra("id_token").(string)
+	if !ok {
+		c.String(http.StatusUnauthorized, "identity provider did not return an id token")
+		return
+	}
+
+	idToken, err := o.verifier.Verify(ctx, rawIDToken)
+	if err != nil || idToken.Nonce != ls.Nonce {
+		log.Printf("oidc: rejected id token: %v", err)
+		c.String(http.StatusUnauthorized, "login failed")
+		return
+	}
+
+	var claims map[string]any
+	if err := idToken.Claims(&claims); err != nil {
+		c.String(http.StatusUnauthorized, "login failed")
+		return
+	}
+
+	// Accounts are linked by email, so an unverified address could take over someone else's account
+	// Providers keep the case the address was typed in, users are found by the lowercase one
+	email, _ := claims["email"].(string)
+	email = strings.ToLower(strings.TrimSpace(email))
+	if email == "" {
+		c.String(http.StatusForbidden, "identity provider did not return an email address")
+		return
+	}
+	if verified, ok := claims["email_verified"].(bool); ok && !verified {
+		c.String(http.StatusForbidden, "email address is not verified")
+		return
+	}
+
+	name, _ := claims["name"].(string)
+	if name == "" {
+		name = email
+	}
+
+	user, err := o.linkUser(ctx, email, name)
+	if err != nil {
+		log.Printf("oidc: failed to link user %s: %v", email, err)
+		c.String(http.StatusInternalServerError, "login failed")
+		return
+	}
+
+	if err := o.syncGroupRoles(ctx, user, stringList(claims[o.groupsClaim()])); err != nil {
+		log.Printf("oidc: failed to sync group roles of %s: %v", email, err)
+	}
+
+	secret, err := newSecret(sessionPrefix)
+	if err != nil {
+		c.AbortWithStatus(http.StatusInternalServerError)
+		return
+	}
+
+	if err := o.storage.CreateSession(ctx, user.ID, HashSecret(secret), time.Now().Add(o.config.SessionTTL)); err != nil {
+		log.Printf("oidc: failed to create session: %v", err)
+		c.String(http.StatusInternalServerError, "login failed")
+		return
+	}
+
+	c.SetSameSite(http.SameSiteLaxMode)
+	c.SetCookie(SessionCookie, secret, int(o.config.SessionTTL.Seconds()), "/", "", o.secure(), true)
+	c.Redirect(http.StatusFound, o.config.PostLoginRedirect)
+}
+
+func (o *OIDC) logout(c *gin.Context) {
+	if secret, err := c.Cookie(SessionCookie); err == nil && secret != "" {
+		if err := o.storage.DeleteSession(c.Request.Context(), HashSecret(secret)); err != nil {
+			log.Printf("oidc: failed to delete session: %v", err)
+		}
+	}
+
+	c.SetSameSite(http.SameSiteLaxMode)
+	c.SetCookie(SessionCookie, "", -1, "/", "", o.secure(), true)
+	c.Status(http.StatusNoContent)
+}
+
+func (o *OIDC) loginState(c *gin.Context) (*loginState, error) {
+	value, err := c.Cookie(stateCookie)
+	if err != nil {
+		return nil, err
+	}
+
+	// The state is single use
+	c.SetCookie(stateCookie, "", -1, stateCookiePath, "", o.secure(), true)
+
+	decoded, err := base64.RawURLEncoding.DecodeString(value)
+	if err != nil {
+		return nil, err
+	}
+
+	var ls loginState
+	if err := json.Unmarshal(decoded, &ls); err != nil {
+		return nil, err
+	}
+	return &ls, nil
+}
+
+// linkUser returns the user with the email address, creating it on first login
+func (o *OIDC) linkUser(ctx context.Context, email, name string) (*model.User, error) {
+	user, err := o.storage.GetUserByEmail(ctx, email)
+	if err == nil {
+		return user, nil
+	}
+	if !errors.Is(err, db.ErrNotFound) {
+		return nil, err
+	}
+
+	user = &model.User{Name: name, Email: email}
+	if err := o.storage.CreateUser(ctx, user); err != nil {
+		return nil, err
+	}
+
+	log.Printf("oidc: created user %s for %s", user.ID, email)
+	return user, nil
+}
+
+// syncGroupRoles grants the roles mapped to the user's groups. Memberships of projects
+// without a matching mapping are left alone, they may have been granted by hand.
+func (o *OIDC) syncGroupRoles(ctx context.Context, user *model.User, groups []string) error {
+	wanted := map[string]model.Role{}
+	for _, mapping := range o.config.GroupRoles {
+		for _, group := range groups {
+			if group == mapping.Group && roleRank[mapping.Role] > roleRank[wanted[mapping.ProjectID]] {
+				wanted[mapping.ProjectID] = mapping.Role
+			}
+		}
+	}
+
+	for projectID, role := range wanted {
+		members, err := o.storage.GetProjectMembers(ctx, projectID)
+		if err != nil {
+			return fmt.Errorf("error getting project members: %w", err)
+		}
+
+		var membership *model.ProjectUser
+		for _, member := range members {
+			if member.User != nil && member.User.ID == user.ID {
+				membership = member
+				break
+			}
+		}
+
+		switch {
+		case membership == nil:
+			err = o.storage.AddProjectMember(ctx, &model.ProjectUser{
+				User:    user,
+				Project: &model.Project{ID: projectID},
+				Role:    role,
+			})
+		case membership.Role != role:
+			err = o.storage.UpdateProjectMemberRole(ctx, membership.ID, role)
+		}
+		if err != nil {
+			return fmt.Errorf("error granting %s in project %s: %w", role, projectID, err)
+		}
+	}
+
+	return nil
+}
+
+func (o *OIDC) groupsClaim() string {
+	if o.config.GroupsClaim == "" {
+		return "groups"
+	}
+	return o.config.GroupsClaim
+}
+
+func (o *OIDC) secure() bool {
+	u, err := url.Parse(o.config.RedirectURL)
+	return err == nil && u.Scheme == "https"
+}
+
+// stringList reads a claim that holds either a list of strings or a single string
+func stringList(claim any) []string {
+	switch v := claim.(type) {
+	case string:
+		return []string{v}
+	case []any:
+		values := make([]string, 0, len(v))
+		for _, item := range v {
+			if s, ok := item.(string); ok {
+				values = append(values, s)
+			}
+		}
+		return values
+	}
+	return nil
+}
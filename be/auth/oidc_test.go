@@ -0,0 +1,29 @@
+package auth
+
+import (
+	"context"
+	"testing"
+
+	"github.com/shubham-tomar/feature-toggler/db/sqlite/sqlitetest"
+)
+
+func TestLinkUserIgnoresEmailCase(t *testing.T) {
+	ctx := context.Background()
+	o := &OIDC{storage: sqlitetest.New(t)}
+
+	first, err := o.linkUser(ctx, "Alice@Example.com", "Alice")
+	if err != nil {
+		t.Fatal(err)
+	}
+	again, err := o.linkUser(ctx, "alice@example.COM", "Alice")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if again.ID != first.ID {
+		t.Errorf("the second login created user %s, want %s", again.ID, first.ID)
+	}
+	if first.Email != "alice@example.com" {
+		t.Errorf("stored email %q, want it in lowercase", first.Email)
+	}
+}
@@ -0,0 +1,78 @@
+package auth
+
+import (
+	"encoding/base64"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/shubham-tomar/feature-toggler/graphQl/model"
+)
+
+func TestOverrideSigner(t *testing.T) {
+	now := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	signer := NewOverrideSigner([]byte("0123456789abcdef0123456789abcdef"))
+	token := &OverrideToken{
+		ProjectID:   "project-1",
+		Environment: model.EnvironmentStaging,
+		Flags:       map[string]string{"new-checkout": "on"},
+		ExpiresAt:   now.Add(time.Hour),
+	}
+
+	secret, err := signer.Sign(token)
+	if err != nil {
+		t.Fatalf("Sign() error = %v", err)
+	}
+	encoded, signature, _ := strings.Cut(strings.TrimPrefix(secret, overrideTokenPrefix), ".")
+
+	// A payload signed with the same key that grants more than the original token
+	forged := *token
+	forged.Flags = map[string]string{"new-checkout": "on", "admin-panel": "on"}
+	forgedSecret, err := signer.Sign(&forged)
+	if err != nil {
+		t.Fatalf("Sign() error = %v", err)
+	}
+	forgedEncoded, _, _ := strings.Cut(strings.TrimPrefix(forgedSecret, overrideTokenPrefix), ".")
+
+	tests := []struct {
+		name    string
+		signer  *OverrideSigner
+		secret  string
+		now     time.Time
+		wantErr error
+	}{
+		{"valid", signer, secret, now, nil},
+		{"just before expiry", signer, secret, token.ExpiresAt.Add(-time.Nanosecond), nil},
+		{"at expiry", signer, secret, token.ExpiresAt, ErrExpiredOverrideToken},
+		{"expired", signer, secret, now.Add(2 * time.Hour), ErrExpiredOverrideToken},
+		{"other key", NewOverrideSigner([]byte("fedcba9876543210fedcba9876543210")), secret, now, ErrInvalidOverrideToken},
+		{"swapped payload", signer, overrideTokenPrefix + forgedEncoded + "." + signature, now, ErrInvalidOverrideToken},
+		{"tampered signature", signer, overrideTokenPrefix + encoded + "." + base64.RawURLEncoding.EncodeToString([]byte("not the signature")), now, ErrInvalidOverrideToken},
+		{"invalid signature encoding", signer, overrideTokenPrefix + encoded + ".!!", now, ErrInvalidOverrideToken},
+		{"missing signature", signer, overrideTokenPrefix + encoded, now, ErrInvalidOverrideToken},
+		{"missing prefix", signer, strings.TrimPrefix(secret, overrideTokenPrefix), now, ErrInvalidOverrideToken},
+		{"empty", signer, "", now, ErrInvalidOverrideToken},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.signer.Verify(tt.secret, tt.now)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Verify() error = %v, want %v", err, tt.wantErr)
+			}
+			if tt.wantErr != nil {
+				if got != nil {
+					t.Errorf("Verify() returned %+v along with an error", got)
+				}
+				return
+			}
+
+			if got.ProjectID != token.ProjectID || got.Environment != token.Environment ||
+				!reflect.DeepEqual(got.Flags, token.Flags) || !got.ExpiresAt.Equal(token.ExpiresAt) {
+				t.Errorf("Verify() = %+v, want %+v", got, token)
+			}
+		})
+	}
+}
@@ -0,0 +1,76 @@
+package auth
+
+import (
+	"errors"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/shubham-tomar/feature-toggler/db"
+	"github.com/shubham-tomar/feature-toggler/graphQl/model"
+)
+
+const (
+	serverKeyPrefix  = "ft_srv_"
+	clientKeyPrefix  = "ft_cli_"
+	sdkKeyContextKey = "sdkKey"
+)
+
+// NewSdkKey generates a random secret for an SDK key and fills in its display prefix.
+// It returns the secret, which is never stored, and the hash to store instead.
+func NewSdkKey(key *model.SdkKey) (secret string, keyHash string, err error) {
+	prefix := serverKeyPrefix
+	if key.Kind == model.SdkKeyKindClient {
+		prefix = clientKeyPrefix
+	}
+
+	secret, err = newSecret(prefix)
+	if err != nil {
+		return "", "", err
+	}
+
+	key.Prefix = secret[:displayedPrefixLength]
+	return secret, HashSecret(secret), nil
+}
+
+// IsActive reports whether an SDK key may still be used
+func IsActive(key *model.SdkKey, now time.Time) bool {
+	return active(key.RevokedAt, key.ExpiresAt, now)
+}
+
+// RequireSdkKey rejects requests without a valid SDK key and stores the key on the context
+func RequireSdkKey(storage db.Storage) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		secret := credential(c.Request)
+		if secret == "" {
+			c.AbortWithStatus(http.StatusUnauthorized)
+			return
+		}
+
+		key, err := storage.GetSdkKeyByHash(c.Request.Context(), HashSecret(secret))
+		if errors.Is(err, db.ErrNotFound) {
+			c.AbortWithStatus(http.StatusUnauthorized)
+			return
+		}
+		if err != nil {
+			log.Printf("auth: failed to look up sdk key: %v", err)
+			c.AbortWithStatus(http.StatusInternalServerError)
+			return
+		}
+
+		if !IsActive(key, time.Now()) {
+			c.AbortWithStatus(http.StatusUnauthorized)
+			return
+		}
+
+		c.Set(sdkKeyContextKey, key)
+		c.Next()
+	}
+}
+
+// SdkKey returns the key authenticated by RequireSdkKey
+func SdkKey(c *gin.Context) *model.SdkKey {
+	key, _ := c.MustGet(sdkKeyContextKey).(*model.SdkKey)
+	return key
+}
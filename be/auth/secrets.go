@@ -0,0 +1,52 @@
+package auth
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// APIKeyHeader is accepted as an alternative to a bearer token
+const APIKeyHeader = "X-API-Key"
+
+// Number of characters of a key or token kept in clear text to recognise it in listings
+const displayedPrefixLength = 12
+
+// newSecret generates a random credential, the prefix tells what kind of credential it is
+func newSecret(prefix string) (string, error) {
+	random := make([]byte, 32)
+	if _, err := rand.Read(random); err != nil {
+		return "", err
+	}
+	return prefix + base64.RawURLEncoding.EncodeToString(random), nil
+}
+
+// HashSecret returns the hash under which a key or token secret is stored.
+// Secrets are long random strings, so a plain SHA-256 is enough to protect them at rest.
+func HashSecret(secret string) string {
+	sum := sha256.Sum256([]byte(secret))
+	return hex.EncodeToString(sum[:])
+}
+
+func active(revokedAt, expiresAt *time.Time, now time.Time) bool {
+	if revokedAt != nil {
+		return false
+	}
+	return expiresAt == nil || now.Before(*expiresAt)
+}
+
+// credential extracts a bearer token or API key from the request
+func credential(r *http.Request) string {
+	if header := r.Header.Get("Authorization"); header != "" {
+		scheme, token, ok := strings.Cut(header, " ")
+		if ok && strings.EqualFold(scheme, "Bearer") {
+			return strings.TrimSpace(token)
+		}
+		return ""
+	}
+	return strings.TrimSpace(r.Header.Get(APIKeyHeader))
+}
@@ -0,0 +1,89 @@
+package changesets
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/shubham-tomar/feature-toggler/db"
+	"github.com/shubham-tomar/feature-toggler/graphQl/model"
+)
+
+// Scheduler applies scheduled change sets once their time has come
+type Scheduler struct {
+	storage  db.Storage
+	interval time.Duration
+	stop     chan struct{}
+	done     chan struct{}
+}
+
+func NewScheduler(storage db.Storage, interval time.Duration) *Scheduler {
+	return &Scheduler{
+		storage:  storage,
+		interval: interval,
+		stop:     make(chan struct{}),
+		done:     make(chan struct{}),
+	}
+}
+
+// Start polls once every interval until Stop is called
+func (s *Scheduler) Start() {
+	go func() {
+		defer close(s.done)
+
+		ticker := time.NewTicker(s.interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ticker.C:
+				if err := s.Run(context.Background()); err != nil {
+					log.Printf("change sets: %v", err)
+				}
+			case <-s.stop:
+				return
+			}
+		}
+	}()
+}
+
+func (s *Scheduler) Stop() {
+	close(s.stop)
+	<-s.done
+}
+
+// Run applies every change set that is due. One that no longer applies, say because one of its
+// flags was archived in the meantime, is marked FAILED with the reason and nothing of it is applied.
+func (s *Scheduler) Run(ctx context.Context) error {
+	due, err := s.storage.GetDueChangeSets(ctx, time.Now())
+	if err != nil {
+		return fmt.Errorf("failed to get due change sets: %w", err)
+	}
+
+	expected := []model.ChangeSetStatus{model.ChangeSetStatusScheduled}
+	for _, changeSet := range due {
+		err := s.storage.ApplyChangeSet(ctx, changeSet, expected)
+		switch {
+		case err == nil:
+			log.Printf("change sets: applied %q", changeSet.Name)
+			continue
+		case errors.Is(err, db.ErrConflict):
+			// Canceled while it was being applied
+			continue
+		case !errors.Is(err, db.ErrNotApplicable):
+			return fmt.Errorf("failed to apply change set %s: %w", changeSet.ID, err)
+		}
+
+		reason := err.Error()
+		changeSet.Status = model.ChangeSetStatusFailed
+		changeSet.Error = &reason
+		if err := s.storage.UpdateChangeSetStatus(ctx, changeSet, expected); err != nil && !errors.Is(err, db.ErrConflict) {
+			return fmt.Errorf("failed to mark change set %s as failed: %w", changeSet.ID, err)
+		}
+		log.Printf("change sets: %q failed: %s", changeSet.Name, reason)
+	}
+
+	return nil
+}
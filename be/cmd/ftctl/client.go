@@ -0,0 +1,56 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// client sends GraphQL requests to the /query endpoint of a server
+type client struct {
+	server string
+	token  string
+}
+
+type graphQLError struct {
+	Message string `json:"message"`
+}
+
+// query runs a GraphQL operation and decodes its data into out
+func (c *client) query(query string, variables map[string]any, out any) error {
+	body, err := json.Marshal(map[string]any{"query": query, "variables": variables})
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequest(http.MethodPost, strings.TrimSuffix(c.server, "/")+"/query", bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if c.token != "" {
+		req.Header.Set("Authorization", "Bearer "+c.token)
+	}
+
+	res, err := (&http.Client{Timeout: 30 * time.Second}).Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+
+	var response struct {
+		Data   json.RawMessage `json:"data"`
+		Errors []graphQLError  `json:"errors"`
+	}
+	if err := json.NewDecoder(res.Body).Decode(&response); err != nil {
+		return fmt.Errorf("unexpected response with status %s: %w", res.Status, err)
+	}
+	if len(response.Errors) > 0 {
+		return fmt.Errorf("%s", response.Errors[0].Message)
+	}
+
+	return json.Unmarshal(response.Data, out)
+}
@@ -0,0 +1,107 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+)
+
+const driftQuery = `query($projectId: ID!, $a: Environment!, $b: Environment!) {
+	compareEnvironments(projectId: $projectId, a: $a, b: $b) {
+		a
+		b
+		compared
+		drift {
+			feature_flag { key }
+			fields
+			a { configured enabled targets updated_at updated_by { name } }
+			b { configured enabled targets updated_at updated_by { name } }
+		}
+	}
+}`
+
+type environmentState struct {
+	Configured bool       `json:"configured"`
+	Enabled    *bool      `json:"enabled"`
+	Targets    int        `json:"targets"`
+	UpdatedAt  *time.Time `json:"updated_at"`
+	UpdatedBy  *struct {
+		Name string `json:"name"`
+	} `json:"updated_by"`
+}
+
+type comparison struct {
+	A        string `json:"a"`
+	B        string `json:"b"`
+	Compared int    `json:"compared"`
+	Drift    []struct {
+		FeatureFlag struct {
+			Key string `json:"key"`
+		} `json:"feature_flag"`
+		Fields []string         `json:"fields"`
+		A      environmentState `json:"a"`
+		B      environmentState `json:"b"`
+	} `json:"drift"`
+}
+
+func drift(client *client, args []string) error {
+	flags := flag.NewFlagSet("drift", flag.ExitOnError)
+	project := flags.String("project", "", "id of the project")
+	a := flags.String("a", "STAGING", "first environment")
+	b := flags.String("b", "PRODUCTION", "second environment")
+	fail := flags.Bool("fail", false, "exit with status 1 when any flag differs, for release checklists")
+	asJSON := flags.Bool("json", false, "print the comparison as JSON")
+	flags.Parse(args)
+
+	if *project == "" {
+		return fmt.Errorf("-project is required")
+	}
+
+	var data struct {
+		CompareEnvironments comparison `json:"compareEnvironments"`
+	}
+	variables := map[string]any{"projectId": *project, "a": *a, "b": *b}
+	if err := client.query(driftQuery, variables, &data); err != nil {
+		return err
+	}
+
+	result := data.CompareEnvironments
+	if *asJSON {
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		if err := encoder.Encode(result); err != nil {
+			return err
+		}
+	} else {
+		fmt.Printf("%d of %d flags differ between %s and %s\n", len(result.Drift), result.Compared, result.A, result.B)
+		for _, flag := range result.Drift {
+			fmt.Printf("\n%s: %s\n", flag.FeatureFlag.Key, strings.ToLower(strings.Join(flag.Fields, ", ")))
+			fmt.Printf("  %-11s %s\n", result.A, describeState(flag.A))
+			fmt.Printf("  %-11s %s\n", result.B, describeState(flag.B))
+		}
+	}
+
+	if *fail && len(result.Drift) > 0 {
+		return fmt.Errorf("%d flags differ between %s and %s", len(result.Drift), result.A, result.B)
+	}
+	return nil
+}
+
+func describeState(state environmentState) string {
+	if !state.Configured {
+		return "not configured"
+	}
+
+	description := "off"
+	if *state.Enabled {
+		description = "on"
+	}
+	description += fmt.Sprintf(", %d targeted keys, changed %s", state.Targets, state.UpdatedAt.Local().Format("2006-01-02 15:04"))
+	if state.UpdatedBy != nil {
+		description += " by " + state.UpdatedBy.Name
+	}
+	return description
+}
@@ -0,0 +1,79 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+)
+
+const explainQuery = `query($projectId: ID!, $flagKey: String!, $environment: Environment!, $context: Map) {
+	explainEvaluation(projectId: $projectId, flagKey: $flagKey, environment: $environment, context: $context) {
+		environment
+		value
+		variant
+		reason
+		steps { kind matched detail bucket }
+	}
+}`
+
+type explanation struct {
+	Environment string  `json:"environment"`
+	Value       *bool   `json:"value"`
+	Variant     *string `json:"variant"`
+	Reason      string  `json:"reason"`
+	Steps       []struct {
+		Kind    string `json:"kind"`
+		Matched bool   `json:"matched"`
+		Detail  string `json:"detail"`
+		Bucket  *int   `json:"bucket"`
+	} `json:"steps"`
+}
+
+func explain(client *client, args []string) error {
+	flags := flag.NewFlagSet("explain", flag.ExitOnError)
+	project := flags.String("project", "", "id of the project")
+	key := flags.String("flag", "", "key of the flag")
+	environment := flags.String("env", "PRODUCTION", "environment to evaluate in")
+	contextJSON := flags.String("context", "{}", `evaluation context as JSON, e.g. {"targetingKey":"user-123"}`)
+	asJSON := flags.Bool("json", false, "print the explanation as JSON")
+	flags.Parse(args)
+
+	if *project == "" || *key == "" {
+		return fmt.Errorf("-project and -flag are required")
+	}
+
+	var evalCtx map[string]any
+	if err := json.Unmarshal([]byte(*contextJSON), &evalCtx); err != nil {
+		return fmt.Errorf("invalid -context: %w", err)
+	}
+
+	var data struct {
+		ExplainEvaluation explanation `json:"explainEvaluation"`
+	}
+	variables := map[string]any{"projectId": *project, "flagKey": *key, "environment": *environment, "context": evalCtx}
+	if err := client.query(explainQuery, variables, &data); err != nil {
+		return err
+	}
+
+	result := data.ExplainEvaluation
+	if *asJSON {
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(result)
+	}
+
+	if result.Value == nil {
+		fmt.Printf("%s in %s: no value, clients use their default (%s)\n", *key, result.Environment, result.Reason)
+	} else {
+		fmt.Printf("%s in %s: %t, variant %s (%s)\n", *key, result.Environment, *result.Value, *result.Variant, result.Reason)
+	}
+	for i, step := range result.Steps {
+		mark := " "
+		if step.Matched {
+			mark = "*"
+		}
+		fmt.Printf("%2d. %s %-11s %s\n", i+1, mark, step.Kind, step.Detail)
+	}
+	return nil
+}
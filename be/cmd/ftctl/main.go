@@ -0,0 +1,52 @@
+// Command ftctl runs support and automation tasks against a feature-toggler server.
+// It calls the GraphQL API with the access token in FT_TOKEN, as scripts and CI pipelines do.
+package main
+
+import (
+	"fmt"
+	"os"
+	"sort"
+)
+
+type command struct {
+	summary string
+	run     func(client *client, args []string) error
+}
+
+var commands = map[string]command{
+	"drift":   {"list the flags whose state differs between two environments", drift},
+	"explain": {"evaluate a flag for a context and print why it served its value", explain},
+}
+
+func main() {
+	if len(os.Args) < 2 || commands[os.Args[1]].run == nil {
+		usage()
+		os.Exit(2)
+	}
+
+	client := &client{
+		server: os.Getenv("FT_SERVER"),
+		token:  os.Getenv("FT_TOKEN"),
+	}
+	if client.server == "" {
+		client.server = "http://localhost:8080"
+	}
+
+	if err := commands[os.Args[1]].run(client, os.Args[2:]); err != nil {
+		fmt.Fprintf(os.Stderr, "ftctl %s: %v\n", os.Args[1], err)
+		os.Exit(1)
+	}
+}
+
+func usage() {
+	fmt.Fprintln(os.Stderr, "usage: ftctl <command> [flags]\n\nCommands:")
+	names := make([]string, 0, len(commands))
+	for name := range commands {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		fmt.Fprintf(os.Stderr, "  %-10s %s\n", name, commands[name].summary)
+	}
+	fmt.Fprintln(os.Stderr, "\nThe server is read from FT_SERVER (http://localhost:8080 by default) and the access token from FT_TOKEN.")
+}
@@ -0,0 +1,173 @@
+// Command mockoidc is a minimal OpenID Connect issuer for trying out single sign-on locally.
+// It logs everyone in as the configured user without asking for credentials.
+package main
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"flag"
+	"log"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-jose/go-jose/v4"
+)
+
+type authorization struct {
+	clientID  string
+	nonce     string
+	challenge string
+}
+
+func main() {
+	addr := flag.String("addr", "localhost:9999", "address to listen on")
+	email := flag.String("email", "dev@example.com", "email of the logged in user")
+	name := flag.String("name", "Dev User", "name of the logged in user")
+	groups := flag.String("groups", "", "comma separated groups of the logged in user")
+	flag.Parse()
+
+	issuer := "http://" + *addr
+
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		log.Fatalf("Failed to generate signing key: %v", err)
+	}
+
+	signer, err := jose.NewSigner(jose.SigningKey{Algorithm: jose.RS256, Key: key},
+		(&jose.SignerOptions{}).WithType("JWT").WithHeader("kid", "mock"))
+	if err != nil {
+		log.Fatalf("Failed to create signer: %v", err)
+	}
+
+	var mu sync.Mutex
+	codes := map[string]authorization{}
+
+	r := gin.Default()
+
+	r.GET("/.well-known/openid-configuration", func(c *gin.Context) {
+		c.JSON(http.StatusOK, gin.H{
+			"issuer":                                issuer,
+			"authorization_endpoint":                issuer + "/authorize",
+			"token_endpoint":                        issuer + "/token",
+			"jwks_uri":                              issuer + "/jwks",
+			"response_types_supported":              []string{"code"},
+			"subject_types_supported":               []string{"public"},
+			"id_token_signing_alg_values_supported": []string{"RS256"},
+			"code_challenge_methods_supported":      []string{"S256"},
+		})
+	})
+
+	r.GET("/jwks", func(c *gin.Context) {
+		c.JSON(http.StatusOK, jose.JSONWebKeySet{Keys: []jose.JSONWebKey{
+			{Key: &key.PublicKey, KeyID: "mock", Algorithm: "RS256", Use: "sig"},
+		}})
+	})
+
+	// Every authorization request is approved immediately
+	r.GET("/authorize", func(c *gin.Context) {
+		redirect, err := url.Parse(c.Query("redirect_uri"))
+		if err != nil || redirect.Scheme == "" {
+			c.String(http.StatusBadRequest, "invalid redirect_uri")
+			return
+		}
+
+		code := randomString()
+		mu.Lock()
+		codes[code] = authorization{
+			clientID:  c.Query("client_id"),
+			nonce:     c.Query("nonce"),
+			challenge: c.Query("code_challenge"),
+		}
+		mu.Unlock()
+
+		query := redirect.Query()
+		query.Set("code", code)
+		query.Set("state", c.Query("state"))
+		redirect.RawQuery = query.Encode()
+		c.Redirect(http.StatusFound, redirect.String())
+	})
+
+	r.POST("/token", func(c *gin.Context) {
+		code := c.PostForm("code")
+
+		mu.Lock()
+		auth, ok := codes[code]
+		delete(codes, code)
+		mu.Unlock()
+
+		if !ok {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid_grant"})
+			return
+		}
+
+		if auth.challenge != "" {
+			sum := sha256.Sum256([]byte(c.PostForm("code_verifier")))
+			if base64.RawURLEncoding.EncodeToString(sum[:]) != auth.challenge {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "invalid_grant", "error_description": "code verifier mismatch"})
+				return
+			}
+		}
+
+		clientID := auth.clientID
+		if id, _, ok := c.Request.BasicAuth(); ok {
+			clientID = id
+		}
+
+		now := time.Now()
+		claims := map[string]any{
+			"iss":            issuer,
+			"sub":            *email,
+			"aud":            clientID,
+			"iat":            now.Unix(),
+			"exp":            now.Add(time.Hour).Unix(),
+			"nonce":          auth.nonce,
+			"email":          *email,
+			"email_verified": true,
+			"name":           *name,
+		}
+		if *groups != "" {
+			claims["groups"] = strings.Split(*groups, ",")
+		}
+
+		payload, err := json.Marshal(claims)
+		if err != nil {
+			c.AbortWithStatus(http.StatusInternalServerError)
+			return
+		}
+
+		signed, err := signer.Sign(payload)
+		if err != nil {
+			c.AbortWithStatus(http.StatusInternalServerError)
+			return
+		}
+
+		idToken, err := signed.CompactSerialize()
+		if err != nil {
+			c.AbortWithStatus(http.StatusInternalServerError)
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{
+			"access_token": randomString(),
+			"token_type":   "Bearer",
+			"expires_in":   3600,
+			"id_token":     idToken,
+		})
+	})
+
+	log.Printf("Mock OIDC issuer running at %s, logging everyone in as %s", issuer, *email)
+	r.Run(*addr)
+}
+
+func randomString() string {
+	random := make([]byte, 16)
+	rand.Read(random)
+	return base64.RawURLEncoding.EncodeToString(random)
+}
@@ -0,0 +1,447 @@
+// Package cache wraps a db.Storage with an in-memory cache of the records read on every
+// flag evaluation: flags, toggle states, projects and users.
+package cache
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/shubham-tomar/feature-toggler/db"
+	"github.com/shubham-tomar/feature-toggler/graphQl/model"
+)
+
+// Stats counts the lookups answered from the cache and the ones passed on to the storage
+type Stats struct {
+	Hits   uint64 `json:"hits"`
+	Misses uint64 `json:"misses"`
+}
+
+// Storage caches reads of the wrapped storage and invalidates them on writes made through it.
+// Writes made elsewhere, e.g. by another instance sharing the database, are only picked up
+// once the TTL runs out. Methods that are not overridden pass straight through, so any
+// new write that changes cached records must be overridden here to invalidate them.
+type Storage struct {
+	db.Storage
+
+	ttl time.Duration
+	// version changes on every invalidation, so that reads racing a write do not cache stale data
+	version atomic.Uint64
+
+	users    *store[*model.User]
+	projects *store[*model.Project]
+	flags    *store[*model.FeatureFlag]
+	flagKeys *store[string] // project id and key to flag id
+	lists    *store[[]*model.FeatureFlag]
+	states   *store[[]*model.ToggleState]
+}
+
+// New wraps the storage, a zero TTL keeps entries until they are invalidated
+func New(storage db.Storage, ttl time.Duration) *Storage {
+	return &Storage{
+		Storage:  storage,
+		ttl:      ttl,
+		users:    newStore[*model.User](),
+		projects: newStore[*model.Project](),
+		flags:    newStore[*model.FeatureFlag](),
+		flagKeys: newStore[string](),
+		lists:    newStore[[]*model.FeatureFlag](),
+		states:   newStore[[]*model.ToggleState](),
+	}
+}
+
+// Stats returns the hit and miss counts of each kind of record
+func (s *Storage) Stats() map[string]Stats {
+	return map[string]Stats{
+		"users":         s.users.stats(),
+		"projects":      s.projects.stats(),
+		"feature_flags": s.flags.stats(),
+		"flag_keys":     s.flagKeys.stats(),
+		"flag_lists":    s.lists.stats(),
+		"toggle_states": s.states.stats(),
+	}
+}
+
+// User operations
+func (s *Storage) GetUserByID(ctx context.Context, id string) (*model.User, error) {
+	if user, ok := s.users.get(id); ok {
+		return cloneUser(user), nil
+	}
+
+	version := s.version.Load()
+	user, err := s.Storage.GetUserByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	s.users.set(id, cloneUser(user), s.expiry(), &s.version, version)
+	return user, nil
+}
+
+func (s *Storage) GetUsersByIDs(ctx context.Context, ids []string) ([]*model.User, error) {
+	users, missing := lookupAll(s.users, ids, cloneUser)
+	if len(missing) == 0 {
+		return users, nil
+	}
+
+	version := s.version.Load()
+	loaded, err := s.Storage.GetUsersByIDs(ctx, missing)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, user := range loaded {
+		s.users.set(user.ID, cloneUser(user), s.expiry(), &s.version, version)
+	}
+
+	return append(users, loaded...), nil
+}
+
+func (s *Storage) CreateUser(ctx context.Context, user *model.User) error {
+	defer s.invalidate(s.users, user.ID)
+	return s.Storage.CreateUser(ctx, user)
+}
+
+func (s *Storage) UpdateUser(ctx context.Context, user *model.User) error {
+	defer s.invalidate(s.users, user.ID)
+	return s.Storage.UpdateUser(ctx, user)
+}
+
+// Project operations
+func (s *Storage) GetProjectByID(ctx context.Context, id string) (*model.Project, error) {
+	if project, ok := s.projects.get(id); ok {
+		return cloneProject(project), nil
+	}
+
+	version := s.version.Load()
+	project, err := s.Storage.GetProjectByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	s.projects.set(id, cloneProject(project), s.expiry(), &s.version, version)
+	return project, nil
+}
+
+func (s *Storage) GetProjectsByIDs(ctx context.Context, ids []string) ([]*model.Project, error) {
+	projects, missing := lookupAll(s.projects, ids, cloneProject)
+	if len(missing) == 0 {
+		return projects, nil
+	}
+
+	version := s.version.Load()
+	loaded, err := s.Storage.GetProjectsByIDs(ctx, missing)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, project := range loaded {
+		s.projects.set(project.ID, cloneProject(project), s.expiry(), &s.version, version)
+	}
+
+	return append(projects, loaded...), nil
+}
+
+func (s *Storage) UpdateProject(ctx context.Context, project *model.Project) error {
+	defer s.invalidate(s.projects, project.ID)
+	return s.Storage.UpdateProject(ctx, project)
+}
+
+func (s *Storage) UpdateProjectProtection(ctx context.Context, projectID string, change *model.ProtectionChange) error {
+	defer s.invalidate(s.projects, projectID)
+	return s.Storage.UpdateProjectProtection(ctx, projectID, change)
+}
+
+func (s *Storage) DeleteProject(ctx context.Context, id string) error {
+	defer s.invalidate(s.projects, id)
+	defer s.invalidate(s.lists, id)
+	return s.Storage.DeleteProject(ctx, id)
+}
+
+// Feature flag operations
+func (s *Storage) GetFeatureFlagByID(ctx context.Context, id string) (*model.FeatureFlag, error) {
+	if flag, ok := s.flags.get(id); ok {
+		return cloneFlag(flag), nil
+	}
+
+	version := s.version.Load()
+	flag, err := s.Storage.GetFeatureFlagByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	s.flags.set(id, cloneFlag(flag), s.expiry(), &s.version, version)
+	return flag, nil
+}
+
+func (s *Storage) GetFeatureFlagByKey(ctx context.Context, projectID, key string) (*model.FeatureFlag, error) {
+	cacheKey := projectID + "\x00" + key
+	if id, ok := s.flagKeys.get(cacheKey); ok {
+		return s.GetFeatureFlagByID(ctx, id)
+	}
+
+	version := s.version.Load()
+	flag, err := s.Storage.GetFeatureFlagByKey(ctx, projectID, key)
+	if err != nil {
+		return nil, err
+	}
+
+	s.flagKeys.set(cacheKey, flag.ID, s.expiry(), &s.version, version)
+	s.flags.set(flag.ID, cloneFlag(flag), s.expiry(), &s.version, version)
+	return flag, nil
+}
+
+func (s *Storage) GetProjectFeatureFlags(ctx context.Context, projectID string) ([]*model.FeatureFlag, error) {
+	if flags, ok := s.lists.get(projectID); ok {
+		return cloneAll(flags, cloneFlag), nil
+	}
+
+	version := s.version.Load()
+	flags, err := s.Storage.GetProjectFeatureFlags(ctx, projectID)
+	if err != nil {
+		return nil, err
+	}
+
+	s.lists.set(projectID, cloneAll(flags, cloneFlag), s.expiry(), &s.version, version)
+	return flags, nil
+}
+
+func (s *Storage) CreateFeatureFlag(ctx context.Context, flag *model.FeatureFlag, initialStates []*model.ToggleState) error {
+	if flag.Project != nil {
+		defer s.invalidate(s.lists, flag.Project.ID)
+	}
+	return s.Storage.CreateFeatureFlag(ctx, flag, initialStates)
+}
+
+func (s *Storage) UpdateFeatureFlag(ctx context.Context, flag *model.FeatureFlag) error {
+	defer s.invalidateFlag(ctx, flag.ID)
+	return s.Storage.UpdateFeatureFlag(ctx, flag)
+}
+
+func (s *Storage) ArchiveFeatureFlag(ctx context.Context, id string) error {
+	defer s.invalidateFlag(ctx, id)
+	return s.Storage.ArchiveFeatureFlag(ctx, id)
+}
+
+func (s *Storage) RestoreFeatureFlag(ctx context.Context, id string) error {
+	defer s.invalidateFlag(ctx, id)
+	return s.Storage.RestoreFeatureFlag(ctx, id)
+}
+
+func (s *Storage) DeleteFeatureFlag(ctx context.Context, id string) error {
+	// The key and project are gone after the delete, look them up first
+	flag, err := s.Storage.GetFeatureFlagByID(ctx, id)
+	if err != nil {
+		return s.Storage.DeleteFeatureFlag(ctx, id)
+	}
+
+	defer s.invalidateFlagRecords(flag)
+	return s.Storage.DeleteFeatureFlag(ctx, id)
+}
+
+// Toggle state operations
+func (s *Storage) GetFeatureFlagStates(ctx context.Context, flagID string) ([]*model.ToggleState, error) {
+	if states, ok := s.states.get(flagID); ok {
+		return cloneAll(states, cloneState), nil
+	}
+
+	version := s.version.Load()
+	states, err := s.Storage.GetFeatureFlagStates(ctx, flagID)
+	if err != nil {
+		return nil, err
+	}
+
+	s.states.set(flagID, cloneAll(states, cloneState), s.expiry(), &s.version, version)
+	return states, nil
+}
+
+func (s *Storage) GetStatesByFeatureFlagIDs(ctx context.Context, flagIDs []string) ([]*model.ToggleState, error) {
+	var states []*model.ToggleState
+	var missing []string
+	for _, id := range flagIDs {
+		if cached, ok := s.states.get(id); ok {
+			states = append(states, cloneAll(cached, cloneState)...)
+		} else {
+			missing = append(missing, id)
+		}
+	}
+
+	if len(missing) == 0 {
+		return states, nil
+	}
+
+	version := s.version.Load()
+	loaded, err := s.Storage.GetStatesByFeatureFlagIDs(ctx, missing)
+	if err != nil {
+		return nil, err
+	}
+
+	// Flags without states are cached too, as an empty list
+	byFlag := make(map[string][]*model.ToggleState, len(missing))
+	for _, id := range missing {
+		byFlag[id] = nil
+	}
+	for _, state := range loaded {
+		byFlag[state.FeatureFlag.ID] = append(byFlag[state.FeatureFlag.ID], cloneState(state))
+	}
+	for id, flagStates := range byFlag {
+		s.states.set(id, flagStates, s.expiry(), &s.version, version)
+	}
+
+	return append(states, loaded...), nil
+}
+
+func (s *Storage) UpdateFeatureFlagState(ctx context.Context, state *model.ToggleState) error {
+	if state.FeatureFlag != nil {
+		defer s.invalidateFlag(ctx, state.FeatureFlag.ID)
+	}
+	return s.Storage.UpdateFeatureFlagState(ctx, state)
+}
+
+// Target changes also update the state of the flag in the environment
+func (s *Storage) AddIndividualTargets(ctx context.Context, state *model.ToggleState, variant string, contextKeys []string) (int, error) {
+	defer s.invalidateFlag(ctx, state.FeatureFlag.ID)
+	return s.Storage.AddIndividualTargets(ctx, state, variant, contextKeys)
+}
+
+func (s *Storage) RemoveIndividualTargets(ctx context.Context, state *model.ToggleState, contextKeys []string) (int, error) {
+	defer s.invalidateFlag(ctx, state.FeatureFlag.ID)
+	return s.Storage.RemoveIndividualTargets(ctx, state, contextKeys)
+}
+
+// Promotions change the states of every flag they list
+func (s *Storage) ApplyPromotion(ctx context.Context, promotion *model.Promotion) error {
+	defer func() {
+		for _, change := range promotion.Changes {
+			s.invalidateFlag(ctx, change.FeatureFlag.ID)
+		}
+	}()
+	return s.Storage.ApplyPromotion(ctx, promotion)
+}
+
+// Change sets change the states of every flag they list, both ways
+func (s *Storage) ApplyChangeSet(ctx context.Context, changeSet *model.ChangeSet, expected []model.ChangeSetStatus) error {
+	defer s.invalidateChangeSet(ctx, changeSet)
+	return s.Storage.ApplyChangeSet(ctx, changeSet, expected)
+}
+
+func (s *Storage) RevertChangeSet(ctx context.Context, changeSet *model.ChangeSet) error {
+	defer s.invalidateChangeSet(ctx, changeSet)
+	return s.Storage.RevertChangeSet(ctx, changeSet)
+}
+
+func (s *Storage) invalidateChangeSet(ctx context.Context, changeSet *model.ChangeSet) {
+	for _, change := range changeSet.Changes {
+		s.invalidateFlag(ctx, change.FeatureFlag.ID)
+	}
+}
+
+// invalidateFlag drops everything cached about a flag that still exists
+func (s *Storage) invalidateFlag(ctx context.Context, id string) {
+	flag, err := s.Storage.GetFeatureFlagByID(ctx, id)
+	if err != nil {
+		// Without the project the lists it appears in are unknown, drop them all
+		s.version.Add(1)
+		s.flags.delete(id)
+		s.states.delete(id)
+		s.lists.clear()
+		return
+	}
+
+	s.invalidateFlagRecords(flag)
+}
+
+func (s *Storage) invalidateFlagRecords(flag *model.FeatureFlag) {
+	s.version.Add(1)
+	s.flags.delete(flag.ID)
+	s.states.delete(flag.ID)
+	if flag.Project != nil {
+		s.lists.delete(flag.Project.ID)
+		s.flagKeys.delete(flag.Project.ID + "\x00" + flag.Key)
+	}
+}
+
+func (s *Storage) invalidate(st interface{ delete(string) }, key string) {
+	s.version.Add(1)
+	st.delete(key)
+}
+
+func (s *Storage) expiry() time.Time {
+	if s.ttl <= 0 {
+		return time.Time{}
+	}
+	return time.Now().Add(s.ttl)
+}
+
+// lookupAll returns copies of the cached values and the keys that were not cached
+func lookupAll[V any](st *store[V], keys []string, clone func(V) V) ([]V, []string) {
+	var found []V
+	var missing []string
+	for _, key := range keys {
+		if value, ok := st.get(key); ok {
+			found = append(found, clone(value))
+		} else {
+			missing = append(missing, key)
+		}
+	}
+	return found, missing
+}
+
+type entry[V any] struct {
+	value   V
+	expires time.Time
+}
+
+type store[V any] struct {
+	mu      sync.RWMutex
+	entries map[string]entry[V]
+	hits    atomic.Uint64
+	misses  atomic.Uint64
+}
+
+func newStore[V any]() *store[V] {
+	return &store[V]{entries: map[string]entry[V]{}}
+}
+
+func (st *store[V]) get(key string) (V, bool) {
+	st.mu.RLock()
+	e, ok := st.entries[key]
+	st.mu.RUnlock()
+
+	if !ok || (!e.expires.IsZero() && time.Now().After(e.expires)) {
+		st.misses.Add(1)
+		var zero V
+		return zero, false
+	}
+
+	st.hits.Add(1)
+	return e.value, true
+}
+
+// set stores a value read at the given version, unless an invalidation happened since
+func (st *store[V]) set(key string, value V, expires time.Time, current *atomic.Uint64, readAt uint64) {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+
+	if current.Load() != readAt {
+		return
+	}
+	st.entries[key] = entry[V]{value: value, expires: expires}
+}
+
+func (st *store[V]) delete(key string) {
+	st.mu.Lock()
+	delete(st.entries, key)
+	st.mu.Unlock()
+}
+
+func (st *store[V]) clear() {
+	st.mu.Lock()
+	st.entries = map[string]entry[V]{}
+	st.mu.Unlock()
+}
+
+func (st *store[V]) stats() Stats {
+	return Stats{Hits: st.hits.Load(), Misses: st.misses.Load()}
+}
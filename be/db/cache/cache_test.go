@@ -0,0 +1,241 @@
+package cache
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/shubham-tomar/feature-toggler/db"
+	"github.com/shubham-tomar/feature-toggler/db/sqlite"
+	"github.com/shubham-tomar/feature-toggler/db/sqlite/sqlitetest"
+	"github.com/shubham-tomar/feature-toggler/graphQl/model"
+)
+
+// newTestCache caches a storage holding one flag, off in every environment
+func newTestCache(t *testing.T, ttl time.Duration) (*Storage, *sqlite.SQLiteStorage, *model.FeatureFlag) {
+	t.Helper()
+
+	storage := sqlitetest.New(t)
+	project := sqlitetest.Project(t, storage, sqlitetest.User(t, storage, "admin"), "checkout")
+	flag := sqlitetest.Flag(t, storage, project, "new-checkout")
+
+	return New(storage, ttl), storage, flag
+}
+
+// warm reads the flag in every way that caches it
+func warm(t *testing.T, c *Storage, flag *model.FeatureFlag) {
+	t.Helper()
+
+	ctx := context.Background()
+	if _, err := c.GetFeatureFlagByID(ctx, flag.ID); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := c.GetFeatureFlagByKey(ctx, flag.Project.ID, flag.Key); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := c.GetProjectFeatureFlags(ctx, flag.Project.ID); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := c.GetFeatureFlagStates(ctx, flag.ID); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestReadsAreCached(t *testing.T) {
+	c, _, flag := newTestCache(t, 0)
+
+	warm(t, c, flag)
+	warm(t, c, flag)
+
+	// A cached key is resolved through the cached flag
+	want := map[string]Stats{
+		"feature_flags": {Hits: 2, Misses: 1},
+		"flag_keys":     {Hits: 1, Misses: 1},
+		"flag_lists":    {Hits: 1, Misses: 1},
+		"toggle_states": {Hits: 1, Misses: 1},
+	}
+	stats := c.Stats()
+	for name, w := range want {
+		if stats[name] != w {
+			t.Errorf("%s: got %+v, want %+v", name, stats[name], w)
+		}
+	}
+}
+
+func TestWritesInvalidate(t *testing.T) {
+	ctx := context.Background()
+
+	tests := []struct {
+		name  string
+		write func(c *Storage, flag *model.FeatureFlag) error
+		check func(t *testing.T, c *Storage, flag *model.FeatureFlag)
+	}{
+		{
+			name: "toggle",
+			write: func(c *Storage, flag *model.FeatureFlag) error {
+				states, err := c.GetFeatureFlagStates(ctx, flag.ID)
+				if err != nil {
+					return err
+				}
+				for _, state := range states {
+					if state.Environment == model.EnvironmentProduction {
+						state.Enabled = true
+						return c.UpdateFeatureFlagState(ctx, state)
+					}
+				}
+				return db.ErrNotFound
+			},
+			check: func(t *testing.T, c *Storage, flag *model.FeatureFlag) {
+				states, err := c.GetFeatureFlagStates(ctx, flag.ID)
+				if err != nil {
+					t.Fatal(err)
+				}
+				for _, state := range states {
+					if state.Enabled != (state.Environment == model.EnvironmentProduction) {
+						t.Errorf("%s is enabled: %v", state.Environment, state.Enabled)
+					}
+				}
+			},
+		},
+		{
+			name: "update",
+			write: func(c *Storage, flag *model.FeatureFlag) error {
+				flag.Name = "New checkout"
+				return c.UpdateFeatureFlag(ctx, flag)
+			},
+			check: func(t *testing.T, c *Storage, flag *model.FeatureFlag) {
+				byKey, err := c.GetFeatureFlagByKey(ctx, flag.Project.ID, flag.Key)
+				if err != nil {
+					t.Fatal(err)
+				}
+				list, err := c.GetProjectFeatureFlags(ctx, flag.Project.ID)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if byKey.Name != "New checkout" || len(list) != 1 || list[0].Name != "New checkout" {
+					t.Errorf("got the names %q and %v, want the new one", byKey.Name, list)
+				}
+			},
+		},
+		{
+			name: "archive",
+			write: func(c *Storage, flag *model.FeatureFlag) error {
+				return c.ArchiveFeatureFlag(ctx, flag.ID)
+			},
+			check: func(t *testing.T, c *Storage, flag *model.FeatureFlag) {
+				got, err := c.GetFeatureFlagByID(ctx, flag.ID)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if got.Status != model.FlagStatusArchived {
+					t.Errorf("got status %s, want %s", got.Status, model.FlagStatusArchived)
+				}
+			},
+		},
+		{
+			name: "delete",
+			write: func(c *Storage, flag *model.FeatureFlag) error {
+				return c.DeleteFeatureFlag(ctx, flag.ID)
+			},
+			check: func(t *testing.T, c *Storage, flag *model.FeatureFlag) {
+				if _, err := c.GetFeatureFlagByID(ctx, flag.ID); err == nil {
+					t.Error("the deleted flag is still found by id")
+				}
+				if _, err := c.GetFeatureFlagByKey(ctx, flag.Project.ID, flag.Key); err == nil {
+					t.Error("the deleted flag is still found by key")
+				}
+				if list, err := c.GetProjectFeatureFlags(ctx, flag.Project.ID); err != nil || len(list) != 0 {
+					t.Errorf("the project still lists %v, %v", list, err)
+				}
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, _, flag := newTestCache(t, 0)
+			warm(t, c, flag)
+
+			if err := tt.write(c, flag); err != nil {
+				t.Fatal(err)
+			}
+			tt.check(t, c, flag)
+		})
+	}
+}
+
+func TestOutsideWritesShowAfterTTL(t *testing.T) {
+	ctx := context.Background()
+	c, storage, flag := newTestCache(t, 50*time.Millisecond)
+	warm(t, c, flag)
+
+	flag.Name = "New checkout"
+	if err := storage.UpdateFeatureFlag(ctx, flag); err != nil {
+		t.Fatal(err)
+	}
+
+	if got, _ := c.GetFeatureFlagByID(ctx, flag.ID); got.Name != "new-checkout" {
+		t.Errorf("got %q before the TTL ran out, want the cached name", got.Name)
+	}
+
+	time.Sleep(60 * time.Millisecond)
+	if got, _ := c.GetFeatureFlagByID(ctx, flag.ID); got.Name != "New checkout" {
+		t.Errorf("got %q after the TTL ran out, want the new name", got.Name)
+	}
+}
+
+func TestCachedRecordsAreCopies(t *testing.T) {
+	ctx := context.Background()
+	c, _, flag := newTestCache(t, 0)
+	warm(t, c, flag)
+
+	got, _ := c.GetFeatureFlagByID(ctx, flag.ID)
+	got.Name = "changed by a caller"
+	states, _ := c.GetFeatureFlagStates(ctx, flag.ID)
+	states[0].Enabled = true
+
+	if again, _ := c.GetFeatureFlagByID(ctx, flag.ID); again.Name != "new-checkout" {
+		t.Errorf("got %q, a caller changed the cached flag", again.Name)
+	}
+	if again, _ := c.GetFeatureFlagStates(ctx, flag.ID); again[0].Enabled {
+		t.Error("a caller changed the cached states")
+	}
+}
+
+// racingStorage runs a write in the middle of the next read of a flag, after the database was read
+type racingStorage struct {
+	db.Storage
+	during func()
+}
+
+func (s *racingStorage) GetFeatureFlagByID(ctx context.Context, id string) (*model.FeatureFlag, error) {
+	flag, err := s.Storage.GetFeatureFlagByID(ctx, id)
+	if during := s.during; during != nil {
+		s.during = nil
+		during()
+	}
+	return flag, err
+}
+
+func TestReadRacingWriteIsNotCached(t *testing.T) {
+	ctx := context.Background()
+	_, storage, flag := newTestCache(t, 0)
+	racing := &racingStorage{Storage: storage}
+	c := New(racing, 0)
+
+	racing.during = func() {
+		renamed := *flag
+		renamed.Name = "New checkout"
+		if err := c.UpdateFeatureFlag(ctx, &renamed); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// The read itself may return the old name, it must not be cached though
+	if _, err := c.GetFeatureFlagByID(ctx, flag.ID); err != nil {
+		t.Fatal(err)
+	}
+	if got, _ := c.GetFeatureFlagByID(ctx, flag.ID); got.Name != "New checkout" {
+		t.Errorf("got %q, the read racing the write was cached", got.Name)
+	}
+}
@@ -0,0 +1,68 @@
+package cache
+
+import (
+	"slices"
+
+	"github.com/shubham-tomar/feature-toggler/graphQl/model"
+)
+
+// Callers are free to modify the records they get, so the cache only ever hands out copies
+
+func cloneUser(u *model.User) *model.User {
+	c := *u
+	if u.CreatedBy != nil {
+		c.CreatedBy = &model.User{ID: u.CreatedBy.ID}
+	}
+	return &c
+}
+
+func cloneProject(p *model.Project) *model.Project {
+	c := *p
+	c.Members = nil
+	c.FeatureFlags = nil
+	return &c
+}
+
+func cloneFlag(f *model.FeatureFlag) *model.FeatureFlag {
+	c := *f
+	if f.Project != nil {
+		c.Project = &model.Project{ID: f.Project.ID}
+	}
+	if f.CreatedBy != nil {
+		c.CreatedBy = &model.User{ID: f.CreatedBy.ID}
+	}
+	if f.States != nil {
+		c.States = cloneAll(f.States, cloneState)
+	}
+	if f.Owner != nil {
+		c.Owner = &model.User{ID: f.Owner.ID}
+	}
+	c.Tags = slices.Clone(f.Tags)
+	c.Links = cloneAll(f.Links, func(l *model.FlagLink) *model.FlagLink {
+		link := *l
+		return &link
+	})
+	return &c
+}
+
+func cloneState(s *model.ToggleState) *model.ToggleState {
+	c := *s
+	if s.FeatureFlag != nil {
+		c.FeatureFlag = &model.FeatureFlag{ID: s.FeatureFlag.ID}
+	}
+	if s.UpdatedBy != nil {
+		c.UpdatedBy = &model.User{ID: s.UpdatedBy.ID}
+	}
+	return &c
+}
+
+func cloneAll[V any](values []V, clone func(V) V) []V {
+	if values == nil {
+		return nil
+	}
+	c := make([]V, len(values))
+	for i, v := range values {
+		c[i] = clone(v)
+	}
+	return c
+}
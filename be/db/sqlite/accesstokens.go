@@ -0,0 +1,138 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/shubham-tomar/feature-toggler/db"
+	"github.com/shubham-tomar/feature-toggler/graphQl/model"
+)
+
+const accessTokenColumns = `id, user_id, name, scopes, prefix, created_at, expires_at, last_used_at, revoked_at`
+
+// Access token operations
+func (s *SQLiteStorage) CreateAccessToken(ctx context.Context, token *model.AccessToken, tokenHash string) error {
+	if token.ID == "" {
+		token.ID = uuid.New().String()
+	}
+	token.CreatedAt = time.Now()
+
+	var userID string
+	if token.User != nil {
+		userID = token.User.ID
+	}
+
+	scopes := make([]string, len(token.Scopes))
+	for i, scope := range token.Scopes {
+		scopes[i] = scope.String()
+	}
+
+	_, err := s.db.ExecContext(ctx,
+		`INSERT INTO access_tokens (id, user_id, name, scopes, prefix, token_hash, created_at, expires_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?)`,
+		token.ID, userID, token.Name, strings.Join(scopes, ","), token.Prefix, tokenHash, token.CreatedAt, token.ExpiresAt,
+	)
+
+	return err
+}
+
+func (s *SQLiteStorage) GetAccessTokenByID(ctx context.Context, id string) (*model.AccessToken, error) {
+	row := s.db.QueryRowContext(ctx,
+		`SELECT `+accessTokenColumns+` FROM access_tokens WHERE id = ?`,
+		id,
+	)
+	return s.scanAccessToken(ctx, row)
+}
+
+func (s *SQLiteStorage) GetAccessTokenByHash(ctx context.Context, tokenHash string) (*model.AccessToken, error) {
+	row := s.db.QueryRowContext(ctx,
+		`SELECT `+accessTokenColumns+` FROM access_tokens WHERE token_hash = ?`,
+		tokenHash,
+	)
+	return s.scanAccessToken(ctx, row)
+}
+
+func (s *SQLiteStorage) GetUserAccessTokens(ctx context.Context, userID string) ([]*model.AccessToken, error) {
+	rows, err := s.db.QueryContext(ctx,
+		`SELECT `+accessTokenColumns+` FROM access_tokens WHERE user_id = ? ORDER BY created_at`,
+		userID,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var tokens []*model.AccessToken
+	for rows.Next() {
+		token, err := s.scanAccessToken(ctx, rows)
+		if err != nil {
+			return nil, err
+		}
+		tokens = append(tokens, token)
+	}
+
+	return tokens, rows.Err()
+}
+
+func (s *SQLiteStorage) TouchAccessToken(ctx context.Context, id string, usedAt time.Time) error {
+	_, err := s.db.ExecContext(ctx,
+		`UPDATE access_tokens SET last_used_at = ? WHERE id = ?`,
+		usedAt, id,
+	)
+
+	return err
+}
+
+func (s *SQLiteStorage) RevokeAccessToken(ctx context.Context, id string) error {
+	_, err := s.db.ExecContext(ctx,
+		`UPDATE access_tokens SET revoked_at = ? WHERE id = ? AND revoked_at IS NULL`,
+		time.Now(), id,
+	)
+
+	return err
+}
+
+func (s *SQLiteStorage) scanAccessToken(ctx context.Context, row scanner) (*model.AccessToken, error) {
+	var token model.AccessToken
+	var userID, scopes string
+	var expiresAt, lastUsedAt, revokedAt sql.NullTime
+
+	err := row.Scan(&token.ID, &userID, &token.Name, &scopes, &token.Prefix,
+		&token.CreatedAt, &expiresAt, &lastUsedAt, &revokedAt)
+
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("access token %w", db.ErrNotFound)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	if scopes != "" {
+		for _, scope := range strings.Split(scopes, ",") {
+			token.Scopes = append(token.Scopes, model.TokenScope(scope))
+		}
+	}
+
+	if expiresAt.Valid {
+		token.ExpiresAt = &expiresAt.Time
+	}
+	if lastUsedAt.Valid {
+		token.LastUsedAt = &lastUsedAt.Time
+	}
+	if revokedAt.Valid {
+		token.RevokedAt = &revokedAt.Time
+	}
+
+	user, err := s.GetUserByID(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("error getting user: %w", err)
+	}
+	token.User = user
+
+	return &token, nil
+}
@@ -0,0 +1,126 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+
+	"github.com/shubham-tomar/feature-toggler/graphQl/model"
+)
+
+// Batched lookups used by the GraphQL dataloaders, ids without a record are skipped
+func (s *SQLiteStorage) GetUsersByIDs(ctx context.Context, ids []string) ([]*model.User, error) {
+	if len(ids) == 0 {
+		return nil, nil
+	}
+
+	rows, err := s.db.QueryContext(ctx,
+		`SELECT `+userColumns+` FROM users WHERE id IN (`+placeholders(len(ids))+`)`,
+		stringArgs(ids)...,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var users []*model.User
+	for rows.Next() {
+		u, err := scanUser(rows)
+		if err != nil {
+			return nil, err
+		}
+		users = append(users, u)
+	}
+
+	return users, rows.Err()
+}
+
+func (s *SQLiteStorage) GetProjectsByIDs(ctx context.Context, ids []string) ([]*model.Project, error) {
+	if len(ids) == 0 {
+		return nil, nil
+	}
+
+	rows, err := s.db.QueryContext(ctx,
+		`SELECT `+projectColumns+` FROM projects WHERE id IN (`+placeholders(len(ids))+`)`,
+		stringArgs(ids)...,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var projects []*model.Project
+	for rows.Next() {
+		p, err := scanProject(rows)
+		if err != nil {
+			return nil, err
+		}
+		projects = append(projects, p)
+	}
+
+	return projects, rows.Err()
+}
+
+// GetMembersByProjectIDs returns the memberships of the projects with their users loaded
+func (s *SQLiteStorage) GetMembersByProjectIDs(ctx context.Context, projectIDs []string) ([]*model.ProjectUser, error) {
+	if len(projectIDs) == 0 {
+		return nil, nil
+	}
+
+	rows, err := s.db.QueryContext(ctx,
+		`SELECT pu.id, pu.project_id, pu.role, u.id, u.name, u.email, u.kind, u.created_by_id, u.created_at, u.updated_at
+		FROM project_users pu JOIN users u ON u.id = pu.user_id
+		WHERE pu.project_id IN (`+placeholders(len(projectIDs))+`)
+		ORDER BY pu.created_at`,
+		stringArgs(projectIDs)...,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var members []*model.ProjectUser
+	for rows.Next() {
+		var m model.ProjectUser
+		var u model.User
+		var projectID string
+		var createdByID sql.NullString
+
+		if err := rows.Scan(&m.ID, &projectID, &m.Role,
+			&u.ID, &u.Name, &u.Email, &u.Kind, &createdByID, &u.CreatedAt, &u.UpdatedAt); err != nil {
+			return nil, err
+		}
+
+		u.CreatedBy = nullableUser(createdByID)
+		m.User = &u
+		m.Project = &model.Project{ID: projectID}
+		members = append(members, &m)
+	}
+
+	return members, rows.Err()
+}
+
+// GetStatesByFeatureFlagIDs returns the toggle states of the flags, related records only carry their id
+func (s *SQLiteStorage) GetStatesByFeatureFlagIDs(ctx context.Context, flagIDs []string) ([]*model.ToggleState, error) {
+	if len(flagIDs) == 0 {
+		return nil, nil
+	}
+
+	return s.queryToggleStates(ctx,
+		`SELECT id, feature_flag_id, environment, enabled, updated_by_id, updated_at
+		FROM toggle_states WHERE feature_flag_id IN (`+placeholders(len(flagIDs))+`)`,
+		stringArgs(flagIDs)...,
+	)
+}
+
+func placeholders(n int) string {
+	return strings.TrimSuffix(strings.Repeat("?, ", n), ", ")
+}
+
+func stringArgs(values []string) []any {
+	args := make([]any, len(values))
+	for i, v := range values {
+		args[i] = v
+	}
+	return args
+}
@@ -0,0 +1,501 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/shubham-tomar/feature-toggler/db"
+	"github.com/shubham-tomar/feature-toggler/graphQl/model"
+)
+
+// storedFlagChange is a change of a change set as it is kept in the changes column. The previous
+// state of everything it touches is recorded when it is applied, so that it can be reverted.
+type storedFlagChange struct {
+	FlagID          string               `json:"flag_id"`
+	Environment     model.Environment    `json:"environment"`
+	Enabled         *bool                `json:"enabled,omitempty"`
+	AddTargets      []*model.TargetGroup `json:"add_targets"`
+	RemoveTargets   []string             `json:"remove_targets"`
+	PreviousEnabled *bool                `json:"previous_enabled,omitempty"`
+	PreviousTargets map[string]*string   `json:"previous_targets,omitempty"` // Null for keys that were not targeted
+}
+
+// Change set operations
+func (s *SQLiteStorage) CreateChangeSet(ctx context.Context, changeSet *model.ChangeSet) error {
+	changeSet.ID = uuid.New().String()
+	changeSet.Status = model.ChangeSetStatusDraft
+	changeSet.CreatedAt = time.Now()
+	changeSet.UpdatedAt = changeSet.CreatedAt
+
+	changes := make([]*storedFlagChange, 0, len(changeSet.Changes))
+	for _, change := range changeSet.Changes {
+		changes = append(changes, &storedFlagChange{
+			FlagID:        change.FeatureFlag.ID,
+			Environment:   change.Environment,
+			Enabled:       change.Enabled,
+			AddTargets:    change.AddTargets,
+			RemoveTargets: change.RemoveTargets,
+		})
+	}
+	changesJSON, err := json.Marshal(changes)
+	if err != nil {
+		return err
+	}
+
+	_, err = s.db.ExecContext(ctx,
+		`INSERT INTO change_sets (id, project_id, name, description, status, changes, created_by_id, created_at, updated_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		changeSet.ID, changeSet.Project.ID, changeSet.Name, changeSet.Description, changeSet.Status, string(changesJSON),
+		changeSet.CreatedBy.ID, changeSet.CreatedAt, changeSet.UpdatedAt,
+	)
+
+	return err
+}
+
+func (s *SQLiteStorage) GetChangeSetByID(ctx context.Context, id string) (*model.ChangeSet, error) {
+	changeSet, err := scanChangeSet(s.db.QueryRowContext(ctx,
+		`SELECT `+changeSetColumns+` FROM change_sets WHERE id = ?`,
+		id,
+	))
+
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("change set %w", db.ErrNotFound)
+	}
+
+	return changeSet, err
+}
+
+func (s *SQLiteStorage) GetProjectChangeSets(ctx context.Context, projectID string, status *model.ChangeSetStatus) ([]*model.ChangeSet, error) {
+	query := `SELECT ` + changeSetColumns + ` FROM change_sets WHERE project_id = ?`
+	args := []any{projectID}
+	if status != nil {
+		query += ` AND status = ?`
+		args = append(args, *status)
+	}
+
+	return s.queryChangeSets(ctx, query+` ORDER BY created_at DESC`, args...)
+}
+
+func (s *SQLiteStorage) GetDueChangeSets(ctx context.Context, now time.Time) ([]*model.ChangeSet, error) {
+	return s.queryChangeSets(ctx,
+		`SELECT `+changeSetColumns+` FROM change_sets WHERE status = ? AND scheduled_at <= ? ORDER BY scheduled_at`,
+		model.ChangeSetStatusScheduled, now.UTC(),
+	)
+}
+
+func (s *SQLiteStorage) queryChangeSets(ctx context.Context, query string, args ...any) ([]*model.ChangeSet, error) {
+	rows, err := s.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var changeSets []*model.ChangeSet
+	for rows.Next() {
+		changeSet, err := scanChangeSet(rows)
+		if err != nil {
+			return nil, err
+		}
+		changeSets = append(changeSets, changeSet)
+	}
+
+	return changeSets, rows.Err()
+}
+
+func (s *SQLiteStorage) UpdateChangeSetStatus(ctx context.Context, changeSet *model.ChangeSet, expected []model.ChangeSetStatus) error {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := lockChangeSet(ctx, tx, changeSet.ID, expected); err != nil {
+		return err
+	}
+
+	changeSet.UpdatedAt = time.Now()
+	_, err = tx.ExecContext(ctx,
+		`UPDATE change_sets SET status = ?, scheduled_at = ?, error = ?, submitted_by_id = ?, decided_by_id = ?, updated_at = ?
+		WHERE id = ?`,
+		changeSet.Status, utcOrNil(changeSet.ScheduledAt), changeSet.Error, userID(changeSet.SubmittedBy), userID(changeSet.DecidedBy),
+		changeSet.UpdatedAt, changeSet.ID,
+	)
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func (s *SQLiteStorage) ApplyChangeSet(ctx context.Context, changeSet *model.ChangeSet, expected []model.ChangeSetStatus) error {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	changes, err := lockChangeSet(ctx, tx, changeSet.ID, expected)
+	if err != nil {
+		return err
+	}
+
+	// The changes are attributed to whoever submitted the change set, not to its approver
+	var updatedByID string
+	if changeSet.SubmittedBy != nil {
+		updatedByID = changeSet.SubmittedBy.ID
+	}
+
+	now := time.Now()
+	for _, change := range changes {
+		if err := applyFlagChange(ctx, tx, change, updatedByID, now); err != nil {
+			return err
+		}
+	}
+
+	changesJSON, err := json.Marshal(changes)
+	if err != nil {
+		return err
+	}
+
+	changeSet.Status = model.ChangeSetStatusApplied
+	changeSet.AppliedAt = &now
+	changeSet.UpdatedAt = now
+	changeSet.Error = nil
+	changeSet.Changes = flagChanges(changes)
+
+	_, err = tx.ExecContext(ctx,
+		`UPDATE change_sets SET status = ?, changes = ?, error = NULL, submitted_by_id = ?, decided_by_id = ?, applied_at = ?, updated_at = ?
+		WHERE id = ?`,
+		changeSet.Status, string(changesJSON), userID(changeSet.SubmittedBy), userID(changeSet.DecidedBy), now, now, changeSet.ID,
+	)
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func (s *SQLiteStorage) RevertChangeSet(ctx context.Context, changeSet *model.ChangeSet) error {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	changes, err := lockChangeSet(ctx, tx, changeSet.ID, []model.ChangeSetStatus{model.ChangeSetStatusApplied})
+	if err != nil {
+		return err
+	}
+
+	var revertedByID string
+	if changeSet.RevertedBy != nil {
+		revertedByID = changeSet.RevertedBy.ID
+	}
+
+	now := time.Now()
+	for i := len(changes) - 1; i >= 0; i-- {
+		if err := revertFlagChange(ctx, tx, changes[i], revertedByID, now); err != nil {
+			return err
+		}
+	}
+
+	changeSet.Status = model.ChangeSetStatusReverted
+	changeSet.RevertedAt = &now
+	changeSet.UpdatedAt = now
+
+	_, err = tx.ExecContext(ctx,
+		`UPDATE change_sets SET status = ?, reverted_by_id = ?, reverted_at = ?, updated_at = ? WHERE id = ?`,
+		changeSet.Status, revertedByID, now, now, changeSet.ID,
+	)
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func (s *SQLiteStorage) DeleteChangeSet(ctx context.Context, id string) error {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := lockChangeSet(ctx, tx, id, []model.ChangeSetStatus{
+		model.ChangeSetStatusDraft, model.ChangeSetStatusRejected, model.ChangeSetStatusFailed,
+	}); err != nil {
+		return err
+	}
+
+	if _, err := tx.ExecContext(ctx, `DELETE FROM change_sets WHERE id = ?`, id); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// lockChangeSet reads the changes of a change set within a transaction, after checking its status
+func lockChangeSet(ctx context.Context, tx *sql.Tx, id string, expected []model.ChangeSetStatus) ([]*storedFlagChange, error) {
+	var status model.ChangeSetStatus
+	var changesJSON string
+	err := tx.QueryRowContext(ctx, `SELECT status, changes FROM change_sets WHERE id = ?`, id).Scan(&status, &changesJSON)
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("change set %w", db.ErrNotFound)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if !slices.Contains(expected, status) {
+		return nil, fmt.Errorf("change set is %s %w", status, db.ErrConflict)
+	}
+
+	var changes []*storedFlagChange
+	if err := json.Unmarshal([]byte(changesJSON), &changes); err != nil {
+		return nil, fmt.Errorf("invalid changes of change set %s: %w", id, err)
+	}
+
+	return changes, nil
+}
+
+// applyFlagChange makes one change of a change set and records what it replaced
+func applyFlagChange(ctx context.Context, tx *sql.Tx, change *storedFlagChange, updatedByID string, now time.Time) error {
+	flag, err := loadFlag(ctx, tx, change.FlagID)
+	if err != nil {
+		return err
+	}
+	if flag.Status == model.FlagStatusArchived {
+		return fmt.Errorf("feature flag %s is archived: %w", flag.Key, db.ErrNotApplicable)
+	}
+
+	var enabled bool
+	err = tx.QueryRowContext(ctx,
+		`SELECT enabled FROM toggle_states WHERE feature_flag_id = ? AND environment = ?`,
+		change.FlagID, change.Environment,
+	).Scan(&enabled)
+	if err == sql.ErrNoRows {
+		return fmt.Errorf("feature flag %s is not configured in %s: %w", flag.Key, change.Environment, db.ErrNotApplicable)
+	}
+	if err != nil {
+		return err
+	}
+
+	change.PreviousEnabled = &enabled
+	change.PreviousTargets = map[string]*string{}
+
+	var keys []string
+	for _, group := range change.AddTargets {
+		keys = append(keys, group.ContextKeys...)
+	}
+	keys = append(keys, change.RemoveTargets...)
+	for _, key := range keys {
+		var variant sql.NullString
+		err := tx.QueryRowContext(ctx,
+			`SELECT variant FROM individual_targets WHERE flag_id = ? AND environment = ? AND context_key = ?`,
+			change.FlagID, change.Environment, key,
+		).Scan(&variant)
+		if err != nil && err != sql.ErrNoRows {
+			return err
+		}
+		if variant.Valid {
+			change.PreviousTargets[key] = &variant.String
+		} else {
+			change.PreviousTargets[key] = nil
+		}
+	}
+
+	next := enabled
+	if change.Enabled != nil {
+		next = *change.Enabled
+	}
+
+	return setFlagState(ctx, tx, flag, change.Environment, enabled, next, appliedTargets(change), updatedByID, now)
+}
+
+// appliedTargets is the variant a change gives each context key it targets, nil for removed targets
+func appliedTargets(change *storedFlagChange) map[string]*string {
+	targets := map[string]*string{}
+	for _, group := range change.AddTargets {
+		for _, key := range group.ContextKeys {
+			targets[key] = &group.Variant
+		}
+	}
+	for _, key := range change.RemoveTargets {
+		targets[key] = nil
+	}
+	return targets
+}
+
+// revertFlagChange restores what a change replaced, flags deleted since then are skipped. A flag that
+// was turned on or off or retargeted since keeps the newer change and the revert fails.
+func revertFlagChange(ctx context.Context, tx *sql.Tx, change *storedFlagChange, updatedByID string, now time.Time) error {
+	flag, err := loadFlag(ctx, tx, change.FlagID)
+	if err != nil {
+		if errors.Is(err, db.ErrNotFound) {
+			return nil
+		}
+		return err
+	}
+
+	var enabled bool
+	err = tx.QueryRowContext(ctx,
+		`SELECT enabled FROM toggle_states WHERE feature_flag_id = ? AND environment = ?`,
+		change.FlagID, change.Environment,
+	).Scan(&enabled)
+	if err == sql.ErrNoRows {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	if change.Enabled != nil && enabled != *change.Enabled {
+		return fmt.Errorf("the state of feature flag %s in %s %w", flag.Key, change.Environment, db.ErrDiverged)
+	}
+	for key, applied := range appliedTargets(change) {
+		var variant sql.NullString
+		err := tx.QueryRowContext(ctx,
+			`SELECT variant FROM individual_targets WHERE flag_id = ? AND environment = ? AND context_key = ?`,
+			change.FlagID, change.Environment, key,
+		).Scan(&variant)
+		if err != nil && err != sql.ErrNoRows {
+			return err
+		}
+		if variant.Valid != (applied != nil) || (applied != nil && variant.String != *applied) {
+			return fmt.Errorf("the target of %s in feature flag %s in %s %w", key, flag.Key, change.Environment, db.ErrDiverged)
+		}
+	}
+
+	next := enabled
+	if change.Enabled != nil && change.PreviousEnabled != nil {
+		next = *change.PreviousEnabled
+	}
+
+	return setFlagState(ctx, tx, flag, change.Environment, enabled, next, change.PreviousTargets, updatedByID, now)
+}
+
+// setFlagState turns a flag on or off in an environment and sets the variant of the given context keys,
+// a nil variant removes the target. The state records who changed it and the flag queues an event.
+func setFlagState(ctx context.Context, tx *sql.Tx, flag *model.FeatureFlag, env model.Environment, previous, enabled bool, targets map[string]*string, updatedByID string, now time.Time) error {
+	if _, err := tx.ExecContext(ctx,
+		`UPDATE toggle_states SET enabled = ?, updated_by_id = ?, updated_at = ? WHERE feature_flag_id = ? AND environment = ?`,
+		enabled, updatedByID, now, flag.ID, env,
+	); err != nil {
+		return err
+	}
+
+	for key, variant := range targets {
+		var err error
+		if variant == nil {
+			_, err = tx.ExecContext(ctx,
+				`DELETE FROM individual_targets WHERE flag_id = ? AND environment = ? AND context_key = ?`,
+				flag.ID, env, key,
+			)
+		} else {
+			_, err = tx.ExecContext(ctx,
+				`INSERT INTO individual_targets (flag_id, environment, context_key, variant, created_at) VALUES (?, ?, ?, ?, ?)
+				ON CONFLICT (flag_id, environment, context_key) DO UPDATE SET
+					variant = excluded.variant,
+					created_at = excluded.created_at
+				WHERE variant != excluded.variant`,
+				flag.ID, env, key, *variant, now,
+			)
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	if previous == enabled {
+		return recordFlagEvent(ctx, tx, model.WebhookEventTypeFlagUpdated, flag, nil)
+	}
+
+	return recordFlagEvent(ctx, tx, model.WebhookEventTypeFlagToggled, flag, &db.WebhookToggle{
+		Environment:     env,
+		Enabled:         enabled,
+		PreviousEnabled: previous,
+		UpdatedByID:     updatedByID,
+	})
+}
+
+func flagChanges(changes []*storedFlagChange) []*model.FlagChange {
+	result := make([]*model.FlagChange, 0, len(changes))
+	for _, change := range changes {
+		addTargets := change.AddTargets
+		if addTargets == nil {
+			addTargets = []*model.TargetGroup{}
+		}
+		removeTargets := change.RemoveTargets
+		if removeTargets == nil {
+			removeTargets = []string{}
+		}
+
+		result = append(result, &model.FlagChange{
+			FeatureFlag:     &model.FeatureFlag{ID: change.FlagID},
+			Environment:     change.Environment,
+			Enabled:         change.Enabled,
+			AddTargets:      addTargets,
+			RemoveTargets:   removeTargets,
+			PreviousEnabled: change.PreviousEnabled,
+		})
+	}
+	return result
+}
+
+func userID(user *model.User) *string {
+	if user == nil {
+		return nil
+	}
+	return &user.ID
+}
+
+const changeSetColumns = `id, project_id, name, description, status, changes, scheduled_at, error, created_by_id, created_at, updated_at,
+	submitted_by_id, decided_by_id, applied_at, reverted_by_id, reverted_at`
+
+func scanChangeSet(row scanner) (*model.ChangeSet, error) {
+	var cs model.ChangeSet
+	var projectID, changesJSON, createdByID string
+	var description, changeError, submittedByID, decidedByID, revertedByID sql.NullString
+	var scheduledAt, appliedAt, revertedAt sql.NullTime
+
+	if err := row.Scan(&cs.ID, &projectID, &cs.Name, &description, &cs.Status, &changesJSON, &scheduledAt, &changeError,
+		&createdByID, &cs.CreatedAt, &cs.UpdatedAt, &submittedByID, &decidedByID, &appliedAt, &revertedByID, &revertedAt); err != nil {
+		return nil, err
+	}
+
+	var changes []*storedFlagChange
+	if err := json.Unmarshal([]byte(changesJSON), &changes); err != nil {
+		return nil, fmt.Errorf("invalid changes of change set %s: %w", cs.ID, err)
+	}
+	cs.Changes = flagChanges(changes)
+
+	if description.Valid {
+		cs.Description = &description.String
+	}
+	if changeError.Valid {
+		cs.Error = &changeError.String
+	}
+	cs.Project = &model.Project{ID: projectID}
+	cs.CreatedBy = &model.User{ID: createdByID}
+	cs.SubmittedBy = nullableUser(submittedByID)
+	cs.DecidedBy = nullableUser(decidedByID)
+	cs.RevertedBy = nullableUser(revertedByID)
+	cs.ScheduledAt = nullableTime(scheduledAt)
+	cs.AppliedAt = nullableTime(appliedAt)
+	cs.RevertedAt = nullableTime(revertedAt)
+
+	return &cs, nil
+}
+
+func nullableUser(id sql.NullString) *model.User {
+	if !id.Valid {
+		return nil
+	}
+	return &model.User{ID: id.String}
+}
@@ -0,0 +1,272 @@
+package sqlite_test
+
+import (
+	"context"
+	"errors"
+	"maps"
+	"testing"
+
+	"github.com/shubham-tomar/feature-toggler/db"
+	"github.com/shubham-tomar/feature-toggler/db/sqlite"
+	"github.com/shubham-tomar/feature-toggler/db/sqlite/sqlitetest"
+	"github.com/shubham-tomar/feature-toggler/graphQl/model"
+)
+
+const prod = model.EnvironmentProduction
+
+// changeSetFixture holds two flags, checkout on and search off in production with user-1 targeted
+// at "off", and a draft change set that turns checkout off and search on, targeting user-1 and user-2 at "on"
+type changeSetFixture struct {
+	storage   *sqlite.SQLiteStorage
+	user      *model.User
+	project   *model.Project
+	checkout  *model.FeatureFlag
+	search    *model.FeatureFlag
+	changeSet *model.ChangeSet
+}
+
+func newChangeSetFixture(t *testing.T) *changeSetFixture {
+	t.Helper()
+
+	f := &changeSetFixture{storage: sqlitetest.New(t)}
+	f.user = sqlitetest.User(t, f.storage, "admin")
+	f.project = sqlitetest.Project(t, f.storage, f.user, "checkout")
+	f.checkout = sqlitetest.Flag(t, f.storage, f.project, "checkout", prod)
+	f.search = sqlitetest.Flag(t, f.storage, f.project, "search")
+
+	if _, err := f.storage.AddIndividualTargets(context.Background(), f.state(t, f.search), "off", []string{"user-1"}); err != nil {
+		t.Fatal(err)
+	}
+
+	on, off := true, false
+	f.changeSet = &model.ChangeSet{
+		Project:   f.project,
+		Name:      "launch",
+		CreatedBy: f.user,
+		Changes: []*model.FlagChange{
+			{FeatureFlag: f.checkout, Environment: prod, Enabled: &off},
+			{FeatureFlag: f.search, Environment: prod, Enabled: &on,
+				AddTargets: []*model.TargetGroup{{Variant: "on", ContextKeys: []string{"user-1", "user-2"}}}},
+		},
+	}
+	if err := f.storage.CreateChangeSet(context.Background(), f.changeSet); err != nil {
+		t.Fatal(err)
+	}
+
+	return f
+}
+
+func (f *changeSetFixture) state(t *testing.T, flag *model.FeatureFlag) *model.ToggleState {
+	t.Helper()
+
+	states, err := f.storage.GetFeatureFlagStates(context.Background(), flag.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, state := range states {
+		if state.Environment == prod {
+			return state
+		}
+	}
+	t.Fatalf("feature flag %s has no state in %s", flag.Key, prod)
+	return nil
+}
+
+// assertProduction checks whether each flag is on and the variants targeting a context key, by flag id
+func (f *changeSetFixture) assertProduction(t *testing.T, checkoutOn, searchOn bool, contextKey string, variants map[string]string) {
+	t.Helper()
+
+	if got := f.state(t, f.checkout).Enabled; got != checkoutOn {
+		t.Errorf("checkout is on: %v, want %v", got, checkoutOn)
+	}
+	if got := f.state(t, f.search).Enabled; got != searchOn {
+		t.Errorf("search is on: %v, want %v", got, searchOn)
+	}
+
+	targets, err := f.storage.GetContextTargets(context.Background(), f.project.ID, prod, contextKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !maps.Equal(targets, variants) {
+		t.Errorf("targets of %s = %v, want %v", contextKey, targets, variants)
+	}
+}
+
+func (f *changeSetFixture) status(t *testing.T) model.ChangeSetStatus {
+	t.Helper()
+
+	changeSet, err := f.storage.GetChangeSetByID(context.Background(), f.changeSet.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return changeSet.Status
+}
+
+var drafts = []model.ChangeSetStatus{model.ChangeSetStatusDraft}
+
+func TestApplyChangeSet(t *testing.T) {
+	f := newChangeSetFixture(t)
+
+	if err := f.storage.ApplyChangeSet(context.Background(), f.changeSet, drafts); err != nil {
+		t.Fatal(err)
+	}
+
+	f.assertProduction(t, false, true, "user-1", map[string]string{f.search.ID: "on"})
+	f.assertProduction(t, false, true, "user-2", map[string]string{f.search.ID: "on"})
+	if status := f.status(t); status != model.ChangeSetStatusApplied {
+		t.Errorf("change set is %s, want %s", status, model.ChangeSetStatusApplied)
+	}
+
+	// The previous states are kept to revert them
+	previous := f.changeSet.Changes[0].PreviousEnabled
+	if previous == nil || !*previous {
+		t.Errorf("previous state of checkout = %v, want on", previous)
+	}
+}
+
+func TestApplyChangeSetIsAllOrNothing(t *testing.T) {
+	ctx := context.Background()
+	f := newChangeSetFixture(t)
+
+	// The second change no longer fits its flag, so the first one must not be applied either
+	if err := f.storage.ArchiveFeatureFlag(ctx, f.search.ID); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := f.storage.ApplyChangeSet(ctx, f.changeSet, drafts); !errors.Is(err, db.ErrNotApplicable) {
+		t.Fatalf("got error %v, want %v", err, db.ErrNotApplicable)
+	}
+
+	f.assertProduction(t, true, false, "user-1", map[string]string{f.search.ID: "off"})
+	if status := f.status(t); status != model.ChangeSetStatusDraft {
+		t.Errorf("change set is %s, want %s", status, model.ChangeSetStatusDraft)
+	}
+}
+
+func TestApplyChangeSetChecksStatus(t *testing.T) {
+	f := newChangeSetFixture(t)
+
+	err := f.storage.ApplyChangeSet(context.Background(), f.changeSet, []model.ChangeSetStatus{model.ChangeSetStatusScheduled})
+	if !errors.Is(err, db.ErrConflict) {
+		t.Fatalf("got error %v, want %v", err, db.ErrConflict)
+	}
+	f.assertProduction(t, true, false, "user-1", map[string]string{f.search.ID: "off"})
+}
+
+func TestRevertChangeSet(t *testing.T) {
+	ctx := context.Background()
+	f := newChangeSetFixture(t)
+
+	if err := f.storage.ApplyChangeSet(ctx, f.changeSet, drafts); err != nil {
+		t.Fatal(err)
+	}
+	f.changeSet.RevertedBy = f.user
+	if err := f.storage.RevertChangeSet(ctx, f.changeSet); err != nil {
+		t.Fatal(err)
+	}
+
+	// Targets that were moved go back to their variant, added ones are removed
+	f.assertProduction(t, true, false, "user-1", map[string]string{f.search.ID: "off"})
+	f.assertProduction(t, true, false, "user-2", nil)
+	if status := f.status(t); status != model.ChangeSetStatusReverted {
+		t.Errorf("change set is %s, want %s", status, model.ChangeSetStatusReverted)
+	}
+
+	if err := f.storage.RevertChangeSet(ctx, f.changeSet); !errors.Is(err, db.ErrConflict) {
+		t.Errorf("reverting twice: got error %v, want %v", err, db.ErrConflict)
+	}
+}
+
+func TestRevertDivergedChangeSet(t *testing.T) {
+	ctx := context.Background()
+
+	tests := []struct {
+		name    string
+		diverge func(t *testing.T, f *changeSetFixture)
+	}{
+		{"toggled since", func(t *testing.T, f *changeSetFixture) {
+			state := f.state(t, f.checkout)
+			state.Enabled = true
+			if err := f.storage.UpdateFeatureFlagState(ctx, state); err != nil {
+				t.Fatal(err)
+			}
+		}},
+		{"retargeted since", func(t *testing.T, f *changeSetFixture) {
+			if _, err := f.storage.AddIndividualTargets(ctx, f.state(t, f.search), "off", []string{"user-2"}); err != nil {
+				t.Fatal(err)
+			}
+		}},
+		{"target removed since", func(t *testing.T, f *changeSetFixture) {
+			if _, err := f.storage.RemoveIndividualTargets(ctx, f.state(t, f.search), []string{"user-1"}); err != nil {
+				t.Fatal(err)
+			}
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := newChangeSetFixture(t)
+			if err := f.storage.ApplyChangeSet(ctx, f.changeSet, drafts); err != nil {
+				t.Fatal(err)
+			}
+			tt.diverge(t, f)
+
+			checkoutOn := f.state(t, f.checkout).Enabled
+			if err := f.storage.RevertChangeSet(ctx, f.changeSet); !errors.Is(err, db.ErrDiverged) {
+				t.Fatalf("got error %v, want %v", err, db.ErrDiverged)
+			}
+
+			// Nothing was reverted, not even the changes that did not diverge
+			if f.state(t, f.search).Enabled != true || f.state(t, f.checkout).Enabled != checkoutOn {
+				t.Error("the revert changed the flags")
+			}
+			if status := f.status(t); status != model.ChangeSetStatusApplied {
+				t.Errorf("change set is %s, want %s", status, model.ChangeSetStatusApplied)
+			}
+		})
+	}
+}
+
+func TestDeleteFeatureFlagFailsPendingChangeSets(t *testing.T) {
+	ctx := context.Background()
+	f := newChangeSetFixture(t)
+
+	on := true
+	other := &model.ChangeSet{
+		Project:   f.project,
+		Name:      "checkout only",
+		CreatedBy: f.user,
+		Changes:   []*model.FlagChange{{FeatureFlag: f.checkout, Environment: prod, Enabled: &on}},
+	}
+	if err := f.storage.CreateChangeSet(ctx, other); err != nil {
+		t.Fatal(err)
+	}
+	promotion := &model.Promotion{
+		Project:     f.project,
+		FeatureFlag: f.search,
+		From:        model.EnvironmentStaging,
+		To:          prod,
+		RequestedBy: f.user,
+	}
+	if err := f.storage.CreatePromotion(ctx, promotion); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := f.storage.DeleteFeatureFlag(ctx, f.search.ID); err != nil {
+		t.Fatal(err)
+	}
+
+	changeSet, err := f.storage.GetChangeSetByID(ctx, f.changeSet.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if changeSet.Status != model.ChangeSetStatusFailed || changeSet.Error == nil || *changeSet.Error != "feature flag search was deleted" {
+		t.Errorf("change set is %s with error %v, want %s because search was deleted", changeSet.Status, changeSet.Error, model.ChangeSetStatusFailed)
+	}
+	if other, err := f.storage.GetChangeSetByID(ctx, other.ID); err != nil || other.Status != model.ChangeSetStatusDraft {
+		t.Errorf("the change set without search is %v, %v, want it left a draft", other, err)
+	}
+	if _, err := f.storage.GetPromotionByID(ctx, *promotion.ID); !errors.Is(err, db.ErrNotFound) {
+		t.Errorf("got error %v for the promotion of search, want %v", err, db.ErrNotFound)
+	}
+}
@@ -0,0 +1,132 @@
+package sqlite
+
+import (
+	"context"
+	"time"
+
+	"github.com/shubham-tomar/feature-toggler/db"
+	"github.com/shubham-tomar/feature-toggler/graphQl/model"
+)
+
+// Evaluation analytics, all timestamps are stored in UTC so buckets line up across writers
+func (s *SQLiteStorage) RecordEvaluations(ctx context.Context, counts []*db.EvaluationCount, impressions []*db.Impression) error {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	for _, c := range counts {
+		_, err := tx.ExecContext(ctx,
+			`INSERT INTO flag_evaluations (flag_id, environment, variant, bucket_start, count, last_evaluated_at)
+			VALUES (?, ?, ?, ?, ?, ?)
+			ON CONFLICT (flag_id, environment, variant, bucket_start) DO UPDATE SET
+				count = count + excluded.count,
+				last_evaluated_at = MAX(last_evaluated_at, excluded.last_evaluated_at)`,
+			c.FlagID, c.Environment, c.Variant, c.BucketStart.UTC(), c.Count, c.LastEvaluatedAt.UTC(),
+		)
+		if err != nil {
+			return err
+		}
+	}
+
+	for _, i := range impressions {
+		_, err := tx.ExecContext(ctx,
+			`INSERT INTO flag_impressions (flag_id, environment, context_key, variant, last_seen_at)
+			VALUES (?, ?, ?, ?, ?)
+			ON CONFLICT (flag_id, environment, context_key) DO UPDATE SET
+				variant = excluded.variant,
+				last_seen_at = excluded.last_seen_at
+			WHERE excluded.last_seen_at > last_seen_at`,
+			i.FlagID, i.Environment, i.ContextKey, i.Variant, i.SeenAt.UTC(),
+		)
+		if err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+// GetEvaluationCounts returns the buckets of a flag starting at or after since, oldest first
+func (s *SQLiteStorage) GetEvaluationCounts(ctx context.Context, flagID string, environment *model.Environment, since time.Time) ([]*db.EvaluationCount, error) {
+	query := `SELECT flag_id, environment, variant, bucket_start, count, last_evaluated_at
+		FROM flag_evaluations WHERE flag_id = ? AND bucket_start >= ?`
+	args := []any{flagID, since.UTC()}
+	if environment != nil {
+		query += ` AND environment = ?`
+		args = append(args, *environment)
+	}
+
+	rows, err := s.db.QueryContext(ctx, query+` ORDER BY bucket_start, environment, variant`, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var counts []*db.EvaluationCount
+	for rows.Next() {
+		var c db.EvaluationCount
+		if err := rows.Scan(&c.FlagID, &c.Environment, &c.Variant, &c.BucketStart, &c.Count, &c.LastEvaluatedAt); err != nil {
+			return nil, err
+		}
+		counts = append(counts, &c)
+	}
+
+	return counts, rows.Err()
+}
+
+// GetLastEvaluations returns when each flag was last evaluated, flags never evaluated are left out
+func (s *SQLiteStorage) GetLastEvaluations(ctx context.Context, flagIDs []string) (map[string]time.Time, error) {
+	last := map[string]time.Time{}
+	if len(flagIDs) == 0 {
+		return last, nil
+	}
+
+	// Selecting the column itself rather than MAX() keeps its declared type, so it scans as a time
+	rows, err := s.db.QueryContext(ctx,
+		`SELECT e.flag_id, e.last_evaluated_at FROM flag_evaluations e
+		WHERE e.flag_id IN (`+placeholders(len(flagIDs))+`)
+		AND e.last_evaluated_at = (SELECT MAX(last_evaluated_at) FROM flag_evaluations WHERE flag_id = e.flag_id)`,
+		stringArgs(flagIDs)...,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var flagID string
+		var at time.Time
+		if err := rows.Scan(&flagID, &at); err != nil {
+			return nil, err
+		}
+		last[flagID] = at
+	}
+
+	return last, rows.Err()
+}
+
+// CountImpressions counts the distinct contexts a flag was evaluated for since the given time
+func (s *SQLiteStorage) CountImpressions(ctx context.Context, flagID string, environment *model.Environment, since time.Time) (int, error) {
+	query := `SELECT COUNT(DISTINCT context_key) FROM flag_impressions WHERE flag_id = ? AND last_seen_at >= ?`
+	args := []any{flagID, since.UTC()}
+	if environment != nil {
+		query += ` AND environment = ?`
+		args = append(args, *environment)
+	}
+
+	var n int
+	err := s.db.QueryRowContext(ctx, query, args...).Scan(&n)
+	return n, err
+}
+
+// DeleteEvaluationsBefore drops buckets that start, and impressions last seen, before the given time
+func (s *SQLiteStorage) DeleteEvaluationsBefore(ctx context.Context, before time.Time) error {
+	if _, err := s.db.ExecContext(ctx, `DELETE FROM flag_evaluations WHERE bucket_start < ?`, before.UTC()); err != nil {
+		return err
+	}
+
+	_, err := s.db.ExecContext(ctx, `DELETE FROM flag_impressions WHERE last_seen_at < ?`, before.UTC())
+	return err
+}
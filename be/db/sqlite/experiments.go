@@ -0,0 +1,337 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/shubham-tomar/feature-toggler/db"
+	"github.com/shubham-tomar/feature-toggler/graphQl/model"
+)
+
+// Experiment operations, start and stop times are stored in UTC since exposures are compared with them
+func (s *SQLiteStorage) CreateExperiment(ctx context.Context, experiment *model.Experiment) error {
+	if experiment.ID == "" {
+		experiment.ID = uuid.New().String()
+	}
+	experiment.Status = model.ExperimentStatusDraft
+	experiment.CreatedAt = time.Now()
+	experiment.UpdatedAt = experiment.CreatedAt
+
+	allocations, metrics, err := experimentConfig(experiment)
+	if err != nil {
+		return err
+	}
+
+	// The project is copied from the flag so experiments can be listed per project
+	res, err := s.db.ExecContext(ctx,
+		`INSERT INTO experiments (id, project_id, flag_id, environment, name, hypothesis, status, traffic_percentage,
+			allocations, metrics, created_by_id, created_at, updated_at, layer_id)
+		SELECT ?, project_id, id, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ? FROM feature_flags WHERE id = ?`,
+		experiment.ID, experiment.Environment, experiment.Name, experiment.Hypothesis, experiment.Status,
+		experiment.TrafficPercentage, allocations, metrics, experiment.CreatedBy.ID,
+		experiment.CreatedAt, experiment.UpdatedAt, layerID(experiment), experiment.FeatureFlag.ID,
+	)
+	if err != nil {
+		return err
+	}
+
+	return requireRow(res, "feature flag")
+}
+
+func (s *SQLiteStorage) GetExperimentByID(ctx context.Context, id string) (*model.Experiment, error) {
+	experiment, err := scanExperiment(s.db.QueryRowContext(ctx,
+		`SELECT `+experimentColumns+` FROM experiments WHERE id = ?`,
+		id,
+	))
+
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("experiment %w", db.ErrNotFound)
+	}
+
+	return experiment, err
+}
+
+func (s *SQLiteStorage) GetProjectExperiments(ctx context.Context, projectID string, status *model.ExperimentStatus) ([]*model.Experiment, error) {
+	query := `SELECT ` + experimentColumns + ` FROM experiments WHERE project_id = ?`
+	args := []any{projectID}
+	if status != nil {
+		query += ` AND status = ?`
+		args = append(args, *status)
+	}
+
+	return s.queryExperiments(ctx, query+` ORDER BY created_at DESC`, args...)
+}
+
+func (s *SQLiteStorage) GetRunningExperiments(ctx context.Context, projectID string, environment model.Environment) ([]*model.Experiment, error) {
+	return s.queryExperiments(ctx,
+		`SELECT `+experimentColumns+` FROM experiments WHERE project_id = ? AND environment = ? AND status = ?`,
+		projectID, environment, model.ExperimentStatusRunning,
+	)
+}
+
+func (s *SQLiteStorage) queryExperiments(ctx context.Context, query string, args ...any) ([]*model.Experiment, error) {
+	rows, err := s.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var experiments []*model.Experiment
+	for rows.Next() {
+		experiment, err := scanExperiment(rows)
+		if err != nil {
+			return nil, err
+		}
+		experiments = append(experiments, experiment)
+	}
+
+	return experiments, rows.Err()
+}
+
+func (s *SQLiteStorage) UpdateExperiment(ctx context.Context, experiment *model.Experiment) error {
+	experiment.UpdatedAt = time.Now()
+
+	allocations, metrics, err := experimentConfig(experiment)
+	if err != nil {
+		return err
+	}
+
+	res, err := s.db.ExecContext(ctx,
+		`UPDATE experiments SET name = ?, hypothesis = ?, traffic_percentage = ?, allocations = ?, metrics = ?, layer_id = ?,
+			updated_at = ?
+		WHERE id = ?`,
+		experiment.Name, experiment.Hypothesis, experiment.TrafficPercentage, allocations, metrics, layerID(experiment),
+		experiment.UpdatedAt, experiment.ID,
+	)
+	if err != nil {
+		return err
+	}
+
+	return requireRow(res, "experiment")
+}
+
+// StartExperiment checks in the same statement that the layer range is not taken by another
+// running experiment of the environment, so two experiments started at once cannot overlap
+func (s *SQLiteStorage) StartExperiment(ctx context.Context, id string, layerOffset *int) error {
+	now := time.Now().UTC()
+	return s.moveExperiment(ctx, id, model.ExperimentStatusDraft,
+		`UPDATE experiments SET status = ?, started_at = ?, updated_at = ?, layer_offset = ?
+		WHERE id = ? AND status = ? AND NOT EXISTS (
+			SELECT 1 FROM experiments o
+			WHERE o.layer_id = experiments.layer_id AND o.environment = experiments.environment AND o.status = ?
+				AND o.layer_offset < ? + experiments.traffic_percentage AND ? < o.layer_offset + o.traffic_percentage
+		)`,
+		model.ExperimentStatusRunning, now, now, layerOffset, id, model.ExperimentStatusDraft,
+		model.ExperimentStatusRunning, layerOffset, layerOffset,
+	)
+}
+
+func (s *SQLiteStorage) StopExperiment(ctx context.Context, id string) error {
+	now := time.Now().UTC()
+	return s.moveExperiment(ctx, id, model.ExperimentStatusRunning,
+		`UPDATE experiments SET status = ?, stopped_at = ?, updated_at = ? WHERE id = ? AND status = ?`,
+		model.ExperimentStatusStopped, now, now, id, model.ExperimentStatusRunning,
+	)
+}
+
+// moveExperiment runs a status change that only applies in the given status
+func (s *SQLiteStorage) moveExperiment(ctx context.Context, id string, from model.ExperimentStatus, query string, args ...any) error {
+	res, err := s.db.ExecContext(ctx, query, args...)
+
+	// At most one experiment runs per flag and environment, see the experiments_running index
+	if isUniqueViolation(err) {
+		return fmt.Errorf("running experiment on the same flag and environment %w", db.ErrConflict)
+	}
+	if err != nil {
+		return err
+	}
+
+	if n, err := res.RowsAffected(); err != nil || n > 0 {
+		return err
+	}
+
+	if _, err := s.GetExperimentByID(ctx, id); err != nil {
+		return err
+	}
+	return fmt.Errorf("experiment is not %s or its layer range is taken: %w", from, db.ErrConflict)
+}
+
+// DeleteExperiment removes an experiment together with its exposures
+func (s *SQLiteStorage) DeleteExperiment(ctx context.Context, id string) error {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, `DELETE FROM experiment_exposures WHERE experiment_id = ?`, id); err != nil {
+		return err
+	}
+
+	res, err := tx.ExecContext(ctx, `DELETE FROM experiments WHERE id = ?`, id)
+	if err != nil {
+		return err
+	}
+
+	if err := requireRow(res, "experiment"); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func (s *SQLiteStorage) RecordExposures(ctx context.Context, exposures []*db.Exposure) error {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	for _, e := range exposures {
+		_, err := tx.ExecContext(ctx,
+			`INSERT INTO experiment_exposures (experiment_id, context_key, variant, exposed_at)
+			VALUES (?, ?, ?, ?)
+			ON CONFLICT (experiment_id, context_key) DO UPDATE SET
+				variant = excluded.variant,
+				exposed_at = excluded.exposed_at
+			WHERE excluded.exposed_at < exposed_at`,
+			e.ExperimentID, e.ContextKey, e.Variant, e.ExposedAt.UTC(),
+		)
+		if err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+func (s *SQLiteStorage) RecordTrackEvents(ctx context.Context, events []*db.TrackEvent) error {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	for _, e := range events {
+		_, err := tx.ExecContext(ctx,
+			`INSERT INTO track_events (project_id, environment, event_key, context_key, count, first_seen_at, last_seen_at)
+			VALUES (?, ?, ?, ?, ?, ?, ?)
+			ON CONFLICT (project_id, environment, event_key, context_key) DO UPDATE SET
+				count = count + excluded.count,
+				first_seen_at = MIN(first_seen_at, excluded.first_seen_at),
+				last_seen_at = MAX(last_seen_at, excluded.last_seen_at)`,
+			e.ProjectID, e.Environment, e.EventKey, e.ContextKey, e.Count, e.FirstSeenAt.UTC(), e.LastSeenAt.UTC(),
+		)
+		if err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+func (s *SQLiteStorage) CountExposures(ctx context.Context, experimentID string) (map[string]int, error) {
+	return s.countByVariant(ctx,
+		`SELECT variant, COUNT(*) FROM experiment_exposures WHERE experiment_id = ? GROUP BY variant`,
+		experimentID,
+	)
+}
+
+// CountConversions counts exposed contexts whose last event came at or after their exposure,
+// which holds exactly when at least one event followed the exposure
+func (s *SQLiteStorage) CountConversions(ctx context.Context, experiment *model.Experiment, eventKey string) (map[string]int, error) {
+	return s.countByVariant(ctx,
+		`SELECT x.variant, COUNT(*) FROM experiment_exposures x
+		JOIN experiments e ON e.id = x.experiment_id
+		JOIN track_events t ON t.project_id = e.project_id AND t.environment = e.environment
+			AND t.event_key = ? AND t.context_key = x.context_key AND t.last_seen_at >= x.exposed_at
+		WHERE x.experiment_id = ?
+		GROUP BY x.variant`,
+		eventKey, experiment.ID,
+	)
+}
+
+func (s *SQLiteStorage) countByVariant(ctx context.Context, query string, args ...any) (map[string]int, error) {
+	rows, err := s.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	counts := map[string]int{}
+	for rows.Next() {
+		var variant string
+		var n int
+		if err := rows.Scan(&variant, &n); err != nil {
+			return nil, err
+		}
+		counts[variant] = n
+	}
+
+	return counts, rows.Err()
+}
+
+const experimentColumns = `id, flag_id, environment, name, hypothesis, status, traffic_percentage, allocations, metrics,
+	created_by_id, created_at, updated_at, started_at, stopped_at, layer_id, layer_offset`
+
+// scanExperiment reads a row of experimentColumns, related records only carry their id
+func scanExperiment(row scanner) (*model.Experiment, error) {
+	var e model.Experiment
+	var flagID, createdByID, allocations, metrics string
+	var hypothesis, layerID sql.NullString
+	var startedAt, stoppedAt sql.NullTime
+	var layerOffset sql.NullInt64
+
+	if err := row.Scan(&e.ID, &flagID, &e.Environment, &e.Name, &hypothesis, &e.Status, &e.TrafficPercentage,
+		&allocations, &metrics, &createdByID, &e.CreatedAt, &e.UpdatedAt, &startedAt, &stoppedAt,
+		&layerID, &layerOffset); err != nil {
+		return nil, err
+	}
+
+	if err := json.Unmarshal([]byte(allocations), &e.Allocations); err != nil {
+		return nil, fmt.Errorf("invalid allocations of experiment %s: %w", e.ID, err)
+	}
+	if err := json.Unmarshal([]byte(metrics), &e.Metrics); err != nil {
+		return nil, fmt.Errorf("invalid metrics of experiment %s: %w", e.ID, err)
+	}
+
+	if hypothesis.Valid {
+		e.Hypothesis = &hypothesis.String
+	}
+	if layerID.Valid {
+		e.Layer = &model.ExperimentLayer{ID: layerID.String}
+	}
+	e.LayerOffset = nullableInt(layerOffset)
+	e.StartedAt = nullableTime(startedAt)
+	e.StoppedAt = nullableTime(stoppedAt)
+	e.FeatureFlag = &model.FeatureFlag{ID: flagID}
+	e.CreatedBy = &model.User{ID: createdByID}
+
+	return &e, nil
+}
+
+func layerID(experiment *model.Experiment) *string {
+	if experiment.Layer == nil {
+		return nil
+	}
+	return &experiment.Layer.ID
+}
+
+// experimentConfig returns the values of the allocations and metrics columns
+func experimentConfig(experiment *model.Experiment) (string, string, error) {
+	allocations, err := json.Marshal(experiment.Allocations)
+	if err != nil {
+		return "", "", err
+	}
+
+	metrics, err := json.Marshal(experiment.Metrics)
+	if err != nil {
+		return "", "", err
+	}
+
+	return string(allocations), string(metrics), nil
+}
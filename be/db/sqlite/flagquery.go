@@ -0,0 +1,185 @@
+package sqlite
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/shubham-tomar/feature-toggler/db"
+	"github.com/shubham-tomar/feature-toggler/graphQl/model"
+)
+
+// Sortable columns, never interpolate user input into ORDER BY directly
+var flagOrderColumns = map[model.FeatureFlagOrderField]string{
+	model.FeatureFlagOrderFieldKey:       "key",
+	model.FeatureFlagOrderFieldName:      "name",
+	model.FeatureFlagOrderFieldCreatedAt: "created_at",
+	model.FeatureFlagOrderFieldUpdatedAt: "updated_at",
+}
+
+// QueryFeatureFlags lists the flags of a project matching the filter, one page at a time,
+// and returns the number of matching flags across all pages
+func (s *SQLiteStorage) QueryFeatureFlags(ctx context.Context, projectID string, filter model.FeatureFlagFilter, order model.FeatureFlagOrder, page db.Page) ([]*model.FeatureFlag, int, error) {
+	where := []string{"project_id = ?"}
+	args := []any{projectID}
+
+	status := model.FlagStatusActive
+	if filter.Status != nil {
+		status = *filter.Status
+	}
+	if status == model.FlagStatusArchived {
+		where = append(where, "archived_at IS NOT NULL")
+	} else {
+		where = append(where, "archived_at IS NULL")
+	}
+
+	if filter.Search != nil && *filter.Search != "" {
+		pattern := "%" + escapeLike(strings.ToLower(*filter.Search)) + "%"
+		where = append(where, `(LOWER(key) LIKE ? ESCAPE '\' OR LOWER(name) LIKE ? ESCAPE '\')`)
+		args = append(args, pattern, pattern)
+	}
+
+	if filter.EnabledIn != nil {
+		where = append(where, `EXISTS (SELECT 1 FROM toggle_states t
+			WHERE t.feature_flag_id = feature_flags.id AND t.environment = ? AND t.enabled)`)
+		args = append(args, *filter.EnabledIn)
+	}
+
+	if filter.CreatedBy != nil {
+		where = append(where, "created_by_id = ?")
+		args = append(args, *filter.CreatedBy)
+	}
+
+	// A flag is stale when neither it nor any of its toggle states changed since the cutoff
+	if filter.Stale != nil {
+		days := db.DefaultStaleAfterDays
+		if filter.StaleAfterDays != nil {
+			days = *filter.StaleAfterDays
+		}
+		cutoff := time.Now().AddDate(0, 0, -days)
+
+		changed := `(updated_at >= ? OR EXISTS (SELECT 1 FROM toggle_states t
+			WHERE t.feature_flag_id = feature_flags.id AND t.updated_at >= ?))`
+		if *filter.Stale {
+			changed = "NOT " + changed
+		}
+		where = append(where, changed)
+		args = append(args, cutoff, cutoff)
+	}
+
+	for _, tag := range filter.Tags {
+		where = append(where, "EXISTS (SELECT 1 FROM json_each(feature_flags.tags) WHERE value = ?)")
+		args = append(args, tag)
+	}
+
+	if filter.Kind != nil {
+		where = append(where, "kind = ?")
+		args = append(args, *filter.Kind)
+	}
+
+	if filter.OwnerID != nil {
+		where = append(where, "owner_id = ?")
+		args = append(args, *filter.OwnerID)
+	}
+
+	if filter.OwnerTeam != nil {
+		where = append(where, "LOWER(owner_team) = ?")
+		args = append(args, strings.ToLower(*filter.OwnerTeam))
+	}
+
+	if filter.Expired != nil {
+		if *filter.Expired {
+			where = append(where, "removal_date IS NOT NULL AND removal_date <= ?")
+		} else {
+			where = append(where, "(removal_date IS NULL OR removal_date > ?)")
+		}
+		args = append(args, time.Now().UTC())
+	}
+
+	conditions := strings.Join(where, " AND ")
+
+	var total int
+	if err := s.db.QueryRowContext(ctx,
+		`SELECT COUNT(*) FROM feature_flags WHERE `+conditions,
+		args...,
+	).Scan(&total); err != nil {
+		return nil, 0, err
+	}
+
+	column, ok := flagOrderColumns[order.Field]
+	if !ok {
+		column = "key"
+	}
+	direction := "ASC"
+	if order.Direction == model.OrderDirectionDesc {
+		direction = "DESC"
+	}
+
+	rows, err := s.db.QueryContext(ctx,
+		fmt.Sprintf(`SELECT %s FROM feature_flags WHERE %s ORDER BY %s %s, id %s LIMIT ? OFFSET ?`,
+			featureFlagColumns, conditions, column, direction, direction),
+		append(args, sqlLimit(page), page.Offset)...,
+	)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	var flags []*model.FeatureFlag
+	for rows.Next() {
+		flag, err := scanFeatureFlag(rows)
+		if err != nil {
+			return nil, 0, err
+		}
+		flags = append(flags, flag)
+	}
+
+	return flags, total, rows.Err()
+}
+
+// sqlLimit returns the limit of a page in the form SQLite expects, where -1 means no limit
+func sqlLimit(page db.Page) int {
+	if page.Limit <= 0 {
+		return -1
+	}
+	return page.Limit
+}
+
+func escapeLike(value string) string {
+	return strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(value)
+}
+
+// CountFeatureFlags counts the flags of all projects, archived flags are never counted as enabled
+func (s *SQLiteStorage) CountFeatureFlags(ctx context.Context) (*db.FlagCounts, error) {
+	counts := &db.FlagCounts{Enabled: map[model.Environment]int{}}
+
+	err := s.db.QueryRowContext(ctx,
+		`SELECT COUNT(*) FILTER (WHERE archived_at IS NULL), COUNT(*) FILTER (WHERE archived_at IS NOT NULL) FROM feature_flags`,
+	).Scan(&counts.Active, &counts.Archived)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := s.db.QueryContext(ctx,
+		`SELECT ts.environment, COUNT(*)
+		FROM toggle_states ts JOIN feature_flags f ON f.id = ts.feature_flag_id
+		WHERE ts.enabled AND f.archived_at IS NULL
+		GROUP BY ts.environment`,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var env model.Environment
+		var n int
+		if err := rows.Scan(&env, &n); err != nil {
+			return nil, err
+		}
+		counts.Enabled[env] = n
+	}
+
+	return counts, rows.Err()
+}
@@ -0,0 +1,199 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/shubham-tomar/feature-toggler/db"
+	"github.com/shubham-tomar/feature-toggler/graphQl/model"
+)
+
+const invitationColumns = `id, project_id, email, role, status, invited_by_id, created_at, expires_at, responded_at`
+
+// Project invitation operations
+func (s *SQLiteStorage) CreateInvitation(ctx context.Context, invitation *model.ProjectInvitation) error {
+	if invitation.ID == "" {
+		invitation.ID = uuid.New().String()
+	}
+	invitation.CreatedAt = time.Now()
+	invitation.Status = model.InvitationStatusPending
+
+	var invitedByID string
+	if invitation.InvitedBy != nil {
+		invitedByID = invitation.InvitedBy.ID
+	}
+
+	_, err := s.db.ExecContext(ctx,
+		`INSERT INTO project_invitations (id, project_id, email, role, status, invited_by_id, created_at, expires_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?)`,
+		invitation.ID, invitation.Project.ID, strings.ToLower(invitation.Email), invitation.Role,
+		invitation.Status, invitedByID, invitation.CreatedAt, invitation.ExpiresAt,
+	)
+
+	return err
+}
+
+func (s *SQLiteStorage) GetInvitationByID(ctx context.Context, id string) (*model.ProjectInvitation, error) {
+	row := s.db.QueryRowContext(ctx,
+		`SELECT `+invitationColumns+` FROM project_invitations WHERE id = ?`,
+		id,
+	)
+	return s.scanInvitation(ctx, row)
+}
+
+func (s *SQLiteStorage) GetProjectInvitations(ctx context.Context, projectID string) ([]*model.ProjectInvitation, error) {
+	return s.queryInvitations(ctx,
+		`SELECT `+invitationColumns+` FROM project_invitations WHERE project_id = ? ORDER BY created_at`,
+		projectID,
+	)
+}
+
+func (s *SQLiteStorage) GetInvitationsByEmail(ctx context.Context, email string) ([]*model.ProjectInvitation, error) {
+	return s.queryInvitations(ctx,
+		`SELECT `+invitationColumns+` FROM project_invitations WHERE email = ? ORDER BY created_at`,
+		strings.ToLower(email),
+	)
+}
+
+// AcceptInvitation marks a pending invitation as accepted and makes the user a member of
+// the project. If the user already is a member, the existing membership is returned unchanged.
+func (s *SQLiteStorage) AcceptInvitation(ctx context.Context, invitationID string, user *model.User) (*model.ProjectUser, error) {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	now := time.Now()
+
+	var projectID string
+	var role model.Role
+	err = tx.QueryRowContext(ctx,
+		`SELECT project_id, role FROM project_invitations WHERE id = ?`,
+		invitationID,
+	).Scan(&projectID, &role)
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("invitation %w", db.ErrNotFound)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	// Only one of two concurrent responses to the same invitation can win
+	res, err := tx.ExecContext(ctx,
+		`UPDATE project_invitations SET status = ?, responded_at = ?
+		WHERE id = ? AND status = ? AND expires_at > ?`,
+		model.InvitationStatusAccepted, now, invitationID, model.InvitationStatusPending, now,
+	)
+	if err != nil {
+		return nil, err
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return nil, fmt.Errorf("invitation is no longer pending")
+	}
+
+	var membershipID string
+	err = tx.QueryRowContext(ctx,
+		`SELECT id FROM project_users WHERE project_id = ? AND user_id = ?`,
+		projectID, user.ID,
+	).Scan(&membershipID)
+
+	switch {
+	case err == sql.ErrNoRows:
+		membershipID = uuid.New().String()
+		if _, err := tx.ExecContext(ctx,
+			`INSERT INTO project_users (id, user_id, project_id, role, created_at, updated_at)
+			VALUES (?, ?, ?, ?, ?, ?)`,
+			membershipID, user.ID, projectID, role, now, now,
+		); err != nil {
+			return nil, err
+		}
+	case err != nil:
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	return s.GetProjectMember(ctx, membershipID)
+}
+
+func (s *SQLiteStorage) UpdateInvitationStatus(ctx context.Context, invitationID string, status model.InvitationStatus) error {
+	res, err := s.db.ExecContext(ctx,
+		`UPDATE project_invitations SET status = ?, responded_at = ? WHERE id = ? AND status = ?`,
+		status, time.Now(), invitationID, model.InvitationStatusPending,
+	)
+	if err != nil {
+		return err
+	}
+
+	if n, _ := res.RowsAffected(); n == 0 {
+		return fmt.Errorf("invitation is no longer pending")
+	}
+
+	return nil
+}
+
+func (s *SQLiteStorage) queryInvitations(ctx context.Context, query string, args ...any) ([]*model.ProjectInvitation, error) {
+	rows, err := s.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var invitations []*model.ProjectInvitation
+	for rows.Next() {
+		invitation, err := s.scanInvitation(ctx, rows)
+		if err != nil {
+			return nil, err
+		}
+		invitations = append(invitations, invitation)
+	}
+
+	return invitations, rows.Err()
+}
+
+func (s *SQLiteStorage) scanInvitation(ctx context.Context, row scanner) (*model.ProjectInvitation, error) {
+	var invitation model.ProjectInvitation
+	var projectID, invitedByID string
+	var respondedAt sql.NullTime
+
+	err := row.Scan(&invitation.ID, &projectID, &invitation.Email, &invitation.Role, &invitation.Status,
+		&invitedByID, &invitation.CreatedAt, &invitation.ExpiresAt, &respondedAt)
+
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("invitation %w", db.ErrNotFound)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	if respondedAt.Valid {
+		invitation.RespondedAt = &respondedAt.Time
+	}
+
+	// Expiry is not written back, a pending invitation simply stops being usable
+	if invitation.Status == model.InvitationStatusPending && !time.Now().Before(invitation.ExpiresAt) {
+		invitation.Status = model.InvitationStatusExpired
+	}
+
+	invitation.Project, err = s.GetProjectByID(ctx, projectID)
+	if err != nil {
+		return nil, fmt.Errorf("error getting project: %w", err)
+	}
+
+	if invitedByID != "" {
+		invitation.InvitedBy, err = s.GetUserByID(ctx, invitedByID)
+		if err != nil {
+			return nil, fmt.Errorf("error getting user: %w", err)
+		}
+	}
+
+	return &invitation, nil
+}
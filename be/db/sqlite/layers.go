@@ -0,0 +1,226 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/shubham-tomar/feature-toggler/db"
+	"github.com/shubham-tomar/feature-toggler/graphQl/model"
+)
+
+// Experiment layer operations
+func (s *SQLiteStorage) CreateExperimentLayer(ctx context.Context, layer *model.ExperimentLayer) error {
+	if layer.ID == "" {
+		layer.ID = uuid.New().String()
+	}
+	layer.CreatedAt = time.Now()
+	layer.UpdatedAt = layer.CreatedAt
+
+	_, err := s.db.ExecContext(ctx,
+		`INSERT INTO experiment_layers (id, project_id, name, description, created_at, updated_at) VALUES (?, ?, ?, ?, ?, ?)`,
+		layer.ID, layer.Project.ID, layer.Name, layer.Description, layer.CreatedAt, layer.UpdatedAt,
+	)
+
+	return err
+}
+
+func (s *SQLiteStorage) GetExperimentLayerByID(ctx context.Context, id string) (*model.ExperimentLayer, error) {
+	layer, err := scanLayer(s.db.QueryRowContext(ctx,
+		`SELECT `+layerColumns+` FROM experiment_layers WHERE id = ?`,
+		id,
+	))
+
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("experiment layer %w", db.ErrNotFound)
+	}
+
+	return layer, err
+}
+
+func (s *SQLiteStorage) GetProjectExperimentLayers(ctx context.Context, projectID string) ([]*model.ExperimentLayer, error) {
+	rows, err := s.db.QueryContext(ctx,
+		`SELECT `+layerColumns+` FROM experiment_layers WHERE project_id = ? ORDER BY name`,
+		projectID,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var layers []*model.ExperimentLayer
+	for rows.Next() {
+		layer, err := scanLayer(rows)
+		if err != nil {
+			return nil, err
+		}
+		layers = append(layers, layer)
+	}
+
+	return layers, rows.Err()
+}
+
+func (s *SQLiteStorage) GetLayerExperiments(ctx context.Context, layerID string) ([]*model.Experiment, error) {
+	return s.queryExperiments(ctx,
+		`SELECT `+experimentColumns+` FROM experiments WHERE layer_id = ? ORDER BY created_at DESC`,
+		layerID,
+	)
+}
+
+func (s *SQLiteStorage) UpdateExperimentLayer(ctx context.Context, layer *model.ExperimentLayer) error {
+	layer.UpdatedAt = time.Now()
+
+	res, err := s.db.ExecContext(ctx,
+		`UPDATE experiment_layers SET name = ?, description = ?, updated_at = ? WHERE id = ?`,
+		layer.Name, layer.Description, layer.UpdatedAt, layer.ID,
+	)
+	if err != nil {
+		return err
+	}
+
+	return requireRow(res, "experiment layer")
+}
+
+func (s *SQLiteStorage) DeleteExperimentLayer(ctx context.Context, id string) error {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	var running int
+	if err := tx.QueryRowContext(ctx,
+		`SELECT COUNT(*) FROM experiments WHERE layer_id = ? AND status = ?`,
+		id, model.ExperimentStatusRunning,
+	).Scan(&running); err != nil {
+		return err
+	}
+	if running > 0 {
+		return fmt.Errorf("running experiment in the layer %w", db.ErrConflict)
+	}
+
+	if _, err := tx.ExecContext(ctx, `UPDATE experiments SET layer_id = NULL WHERE layer_id = ?`, id); err != nil {
+		return err
+	}
+
+	res, err := tx.ExecContext(ctx, `DELETE FROM experiment_layers WHERE id = ?`, id)
+	if err != nil {
+		return err
+	}
+
+	if err := requireRow(res, "experiment layer"); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+const layerColumns = `id, project_id, name, description, created_at, updated_at`
+
+func scanLayer(row scanner) (*model.ExperimentLayer, error) {
+	var layer model.ExperimentLayer
+	var projectID string
+	var description sql.NullString
+
+	if err := row.Scan(&layer.ID, &projectID, &layer.Name, &description, &layer.CreatedAt, &layer.UpdatedAt); err != nil {
+		return nil, err
+	}
+
+	if description.Valid {
+		layer.Description = &description.String
+	}
+	layer.Project = &model.Project{ID: projectID}
+
+	return &layer, nil
+}
+
+// Holdout operations
+func (s *SQLiteStorage) CreateHoldout(ctx context.Context, holdout *model.Holdout) error {
+	if holdout.ID == "" {
+		holdout.ID = uuid.New().String()
+	}
+	holdout.CreatedAt = time.Now()
+	holdout.UpdatedAt = holdout.CreatedAt
+
+	_, err := s.db.ExecContext(ctx,
+		`INSERT INTO holdouts (id, project_id, name, percentage, created_at, updated_at) VALUES (?, ?, ?, ?, ?, ?)`,
+		holdout.ID, holdout.Project.ID, holdout.Name, holdout.Percentage, holdout.CreatedAt, holdout.UpdatedAt,
+	)
+
+	return err
+}
+
+func (s *SQLiteStorage) GetHoldoutByID(ctx context.Context, id string) (*model.Holdout, error) {
+	holdout, err := scanHoldout(s.db.QueryRowContext(ctx,
+		`SELECT `+holdoutColumns+` FROM holdouts WHERE id = ?`,
+		id,
+	))
+
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("holdout %w", db.ErrNotFound)
+	}
+
+	return holdout, err
+}
+
+func (s *SQLiteStorage) GetProjectHoldouts(ctx context.Context, projectID string) ([]*model.Holdout, error) {
+	rows, err := s.db.QueryContext(ctx,
+		`SELECT `+holdoutColumns+` FROM holdouts WHERE project_id = ? ORDER BY name`,
+		projectID,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var holdouts []*model.Holdout
+	for rows.Next() {
+		holdout, err := scanHoldout(rows)
+		if err != nil {
+			return nil, err
+		}
+		holdouts = append(holdouts, holdout)
+	}
+
+	return holdouts, rows.Err()
+}
+
+func (s *SQLiteStorage) UpdateHoldout(ctx context.Context, holdout *model.Holdout) error {
+	holdout.UpdatedAt = time.Now()
+
+	res, err := s.db.ExecContext(ctx,
+		`UPDATE holdouts SET name = ?, percentage = ?, updated_at = ? WHERE id = ?`,
+		holdout.Name, holdout.Percentage, holdout.UpdatedAt, holdout.ID,
+	)
+	if err != nil {
+		return err
+	}
+
+	return requireRow(res, "holdout")
+}
+
+func (s *SQLiteStorage) DeleteHoldout(ctx context.Context, id string) error {
+	res, err := s.db.ExecContext(ctx, `DELETE FROM holdouts WHERE id = ?`, id)
+	if err != nil {
+		return err
+	}
+
+	return requireRow(res, "holdout")
+}
+
+const holdoutColumns = `id, project_id, name, percentage, created_at, updated_at`
+
+func scanHoldout(row scanner) (*model.Holdout, error) {
+	var holdout model.Holdout
+	var projectID string
+
+	if err := row.Scan(&holdout.ID, &projectID, &holdout.Name, &holdout.Percentage, &holdout.CreatedAt, &holdout.UpdatedAt); err != nil {
+		return nil, err
+	}
+
+	holdout.Project = &model.Project{ID: projectID}
+
+	return &holdout, nil
+}
@@ -1,6 +1,9 @@
 package sqlite
 
-import "database/sql"
+import (
+	"database/sql"
+	"fmt"
+)
 
 func Migrate(db *sql.DB) error {
 	queries := []string{
@@ -25,6 +28,221 @@ func Migrate(db *sql.DB) error {
 			created_at TIMESTAMP,
 			updated_at TIMESTAMP
 		);`,
+		`CREATE TABLE IF NOT EXISTS feature_flags (
+			id TEXT PRIMARY KEY,
+			key TEXT,
+			name TEXT,
+			description TEXT,
+			project_id TEXT,
+			created_by_id TEXT,
+			created_at TIMESTAMP,
+			updated_at TIMESTAMP
+		);`,
+		`CREATE TABLE IF NOT EXISTS toggle_states (
+			id TEXT PRIMARY KEY,
+			feature_flag_id TEXT,
+			environment TEXT,
+			enabled BOOLEAN,
+			updated_by_id TEXT,
+			updated_at TIMESTAMP
+		);`,
+		`CREATE TABLE IF NOT EXISTS sdk_keys (
+			id TEXT PRIMARY KEY,
+			project_id TEXT,
+			environment TEXT,
+			kind TEXT,
+			name TEXT,
+			prefix TEXT,
+			key_hash TEXT UNIQUE,
+			created_by_id TEXT,
+			created_at TIMESTAMP,
+			expires_at TIMESTAMP,
+			revoked_at TIMESTAMP
+		);`,
+		`CREATE TABLE IF NOT EXISTS access_tokens (
+			id TEXT PRIMARY KEY,
+			user_id TEXT,
+			name TEXT,
+			scopes TEXT,
+			prefix TEXT,
+			token_hash TEXT UNIQUE,
+			created_at TIMESTAMP,
+			expires_at TIMESTAMP,
+			last_used_at TIMESTAMP,
+			revoked_at TIMESTAMP
+		);`,
+		`CREATE TABLE IF NOT EXISTS sessions (
+			token_hash TEXT PRIMARY KEY,
+			user_id TEXT,
+			created_at TIMESTAMP,
+			expires_at TIMESTAMP
+		);`,
+		`CREATE TABLE IF NOT EXISTS project_invitations (
+			id TEXT PRIMARY KEY,
+			project_id TEXT,
+			email TEXT,
+			role TEXT,
+			status TEXT,
+			invited_by_id TEXT,
+			created_at TIMESTAMP,
+			expires_at TIMESTAMP,
+			responded_at TIMESTAMP
+		);`,
+		`CREATE TABLE IF NOT EXISTS flag_evaluations (
+			flag_id TEXT,
+			environment TEXT,
+			variant TEXT,
+			bucket_start TIMESTAMP,
+			count INTEGER,
+			last_evaluated_at TIMESTAMP,
+			PRIMARY KEY (flag_id, environment, variant, bucket_start)
+		);`,
+		`CREATE TABLE IF NOT EXISTS flag_impressions (
+			flag_id TEXT,
+			environment TEXT,
+			context_key TEXT,
+			variant TEXT,
+			last_seen_at TIMESTAMP,
+			PRIMARY KEY (flag_id, environment, context_key)
+		);`,
+		`CREATE TABLE IF NOT EXISTS webhooks (
+			id TEXT PRIMARY KEY,
+			project_id TEXT,
+			url TEXT,
+			secret TEXT,
+			event_types TEXT,
+			active BOOLEAN,
+			created_by_id TEXT,
+			created_at TIMESTAMP,
+			updated_at TIMESTAMP
+		);`,
+		`CREATE TABLE IF NOT EXISTS webhook_outbox (
+			id TEXT PRIMARY KEY,
+			project_id TEXT,
+			event_type TEXT,
+			payload TEXT,
+			created_at TIMESTAMP
+		);`,
+		`CREATE TABLE IF NOT EXISTS webhook_deliveries (
+			id TEXT PRIMARY KEY,
+			webhook_id TEXT,
+			event_id TEXT,
+			event_type TEXT,
+			payload TEXT,
+			status TEXT,
+			attempts INTEGER,
+			next_attempt_at TIMESTAMP,
+			last_attempt_at TIMESTAMP,
+			response_status INTEGER,
+			last_error TEXT,
+			created_at TIMESTAMP,
+			delivered_at TIMESTAMP,
+			redelivery_of TEXT
+		);`,
+		`CREATE INDEX IF NOT EXISTS webhook_deliveries_due ON webhook_deliveries (status, next_attempt_at);`,
+		`CREATE INDEX IF NOT EXISTS webhook_deliveries_webhook ON webhook_deliveries (webhook_id, created_at);`,
+		`CREATE TABLE IF NOT EXISTS experiments (
+			id TEXT PRIMARY KEY,
+			project_id TEXT,
+			flag_id TEXT,
+			environment TEXT,
+			name TEXT,
+			hypothesis TEXT,
+			status TEXT,
+			traffic_percentage INTEGER,
+			allocations TEXT,
+			metrics TEXT,
+			created_by_id TEXT,
+			created_at TIMESTAMP,
+			updated_at TIMESTAMP,
+			started_at TIMESTAMP,
+			stopped_at TIMESTAMP
+		);`,
+		`CREATE UNIQUE INDEX IF NOT EXISTS experiments_running ON experiments (flag_id, environment) WHERE status = 'RUNNING';`,
+		`CREATE TABLE IF NOT EXISTS experiment_exposures (
+			experiment_id TEXT,
+			context_key TEXT,
+			variant TEXT,
+			exposed_at TIMESTAMP,
+			PRIMARY KEY (experiment_id, context_key)
+		);`,
+		`CREATE TABLE IF NOT EXISTS track_events (
+			project_id TEXT,
+			environment TEXT,
+			event_key TEXT,
+			context_key TEXT,
+			count INTEGER,
+			first_seen_at TIMESTAMP,
+			last_seen_at TIMESTAMP,
+			PRIMARY KEY (project_id, environment, event_key, context_key)
+		);`,
+		`CREATE TABLE IF NOT EXISTS individual_targets (
+			flag_id TEXT,
+			environment TEXT,
+			context_key TEXT,
+			variant TEXT,
+			created_at TIMESTAMP,
+			PRIMARY KEY (flag_id, environment, context_key)
+		);`,
+		`CREATE INDEX IF NOT EXISTS individual_targets_context ON individual_targets (context_key, environment);`,
+		`CREATE TABLE IF NOT EXISTS experiment_layers (
+			id TEXT PRIMARY KEY,
+			project_id TEXT,
+			name TEXT,
+			description TEXT,
+			created_at TIMESTAMP,
+			updated_at TIMESTAMP
+		);`,
+		`CREATE TABLE IF NOT EXISTS holdouts (
+			id TEXT PRIMARY KEY,
+			project_id TEXT,
+			name TEXT,
+			percentage INTEGER,
+			created_at TIMESTAMP,
+			updated_at TIMESTAMP
+		);`,
+		`CREATE TABLE IF NOT EXISTS promotions (
+			id TEXT PRIMARY KEY,
+			project_id TEXT,
+			flag_id TEXT,
+			from_environment TEXT,
+			to_environment TEXT,
+			status TEXT,
+			changes TEXT NOT NULL DEFAULT '[]',
+			requested_by_id TEXT,
+			created_at TIMESTAMP,
+			decided_by_id TEXT,
+			decided_at TIMESTAMP
+		);`,
+		`CREATE INDEX IF NOT EXISTS promotions_project ON promotions (project_id, created_at);`,
+		`CREATE TABLE IF NOT EXISTS change_sets (
+			id TEXT PRIMARY KEY,
+			project_id TEXT,
+			name TEXT,
+			description TEXT,
+			status TEXT,
+			changes TEXT NOT NULL DEFAULT '[]',
+			scheduled_at TIMESTAMP,
+			error TEXT,
+			created_by_id TEXT,
+			created_at TIMESTAMP,
+			updated_at TIMESTAMP,
+			submitted_by_id TEXT,
+			decided_by_id TEXT,
+			applied_at TIMESTAMP,
+			reverted_by_id TEXT,
+			reverted_at TIMESTAMP
+		);`,
+		`CREATE INDEX IF NOT EXISTS change_sets_project ON change_sets (project_id, created_at);`,
+		`CREATE INDEX IF NOT EXISTS change_sets_due ON change_sets (status, scheduled_at);`,
+		`CREATE TABLE IF NOT EXISTS protection_changes (
+			id TEXT PRIMARY KEY,
+			project_id TEXT,
+			environments TEXT NOT NULL DEFAULT '[]',
+			changed_by_id TEXT,
+			changed_at TIMESTAMP
+		);`,
+		`CREATE INDEX IF NOT EXISTS protection_changes_project ON protection_changes (project_id, changed_at);`,
 	}
 
 	for _, q := range queries {
@@ -33,5 +251,67 @@ func Migrate(db *sql.DB) error {
 		}
 	}
 
+	// Columns added after their table was first created
+	columns := []struct {
+		table, name, definition string
+	}{
+		{"feature_flags", "client_side", "BOOLEAN NOT NULL DEFAULT FALSE"},
+		{"users", "kind", "TEXT NOT NULL DEFAULT 'HUMAN'"},
+		{"feature_flags", "archived_at", "TIMESTAMP"},
+		{"projects", "flag_key_pattern", "TEXT"},
+		{"projects", "flag_key_max_length", "INTEGER"},
+		{"projects", "new_flag_days", "INTEGER"},
+		{"projects", "stale_after_days", "INTEGER"},
+		{"projects", "unused_after_days", "INTEGER"},
+		{"feature_flags", "kind", "TEXT NOT NULL DEFAULT 'RELEASE'"},
+		{"feature_flags", "tags", "TEXT NOT NULL DEFAULT '[]'"},
+		{"feature_flags", "owner_id", "TEXT"},
+		{"feature_flags", "owner_team", "TEXT"},
+		{"feature_flags", "removal_date", "TIMESTAMP"},
+		{"feature_flags", "links", "TEXT NOT NULL DEFAULT '[]'"},
+		{"experiments", "layer_id", "TEXT"},
+		{"experiments", "layer_offset", "INTEGER"},
+		{"projects", "protected_environments", "TEXT NOT NULL DEFAULT '[]'"},
+		{"users", "created_by_id", "TEXT"},
+	}
+
+	for _, c := range columns {
+		if err := addColumn(db, c.table, c.name, c.definition); err != nil {
+			return fmt.Errorf("failed to add column %s.%s: %w", c.table, c.name, err)
+		}
+	}
+
+	// Keys used to be global, databases from that time may hold the same key twice in a project
+	if _, err := db.Exec(`CREATE UNIQUE INDEX IF NOT EXISTS feature_flags_project_key ON feature_flags (project_id, key)`); err != nil {
+		return fmt.Errorf("failed to make flag keys unique per project, rename duplicate keys first: %w", err)
+	}
+
 	return nil
 }
+
+// addColumn adds a column unless it already exists, since SQLite has no ADD COLUMN IF NOT EXISTS
+func addColumn(db *sql.DB, table, column, definition string) error {
+	rows, err := db.Query(fmt.Sprintf(`PRAGMA table_info(%s)`, table))
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var cid, notNull, pk int
+		var name, columnType string
+		var defaultValue sql.NullString
+		if err := rows.Scan(&cid, &name, &columnType, &notNull, &defaultValue, &pk); err != nil {
+			return err
+		}
+		if name == column {
+			return nil
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	_, err = db.Exec(fmt.Sprintf(`ALTER TABLE %s ADD COLUMN %s %s`, table, column, definition))
+	return err
+}
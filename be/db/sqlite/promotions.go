@@ -0,0 +1,297 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/shubham-tomar/feature-toggler/db"
+	"github.com/shubham-tomar/feature-toggler/graphQl/model"
+)
+
+// storedChange is a flag state change as it is kept in the changes column of a promotion
+type storedChange struct {
+	FlagID         string `json:"flag_id"`
+	FlagKey        string `json:"flag_key"`
+	EnabledBefore  *bool  `json:"enabled_before"`
+	EnabledAfter   bool   `json:"enabled_after"`
+	TargetsAdded   int    `json:"targets_added"`
+	TargetsRemoved int    `json:"targets_removed"`
+	TargetsMoved   int    `json:"targets_moved"`
+}
+
+// Promotion operations
+func (s *SQLiteStorage) CreatePromotion(ctx context.Context, promotion *model.Promotion) error {
+	id := uuid.New().String()
+	promotion.ID = &id
+	promotion.Status = model.PromotionStatusPendingApproval
+	promotion.CreatedAt = time.Now()
+
+	// The changes of a pending promotion are computed when it is read
+	_, err := s.db.ExecContext(ctx,
+		`INSERT INTO promotions (id, project_id, flag_id, from_environment, to_environment, status, changes, requested_by_id, created_at)
+		VALUES (?, ?, ?, ?, ?, ?, '[]', ?, ?)`,
+		id, promotion.Project.ID, promotionFlagID(promotion), promotion.From, promotion.To, promotion.Status,
+		promotion.RequestedBy.ID, promotion.CreatedAt,
+	)
+
+	return err
+}
+
+func (s *SQLiteStorage) GetPromotionByID(ctx context.Context, id string) (*model.Promotion, error) {
+	promotion, err := scanPromotion(s.db.QueryRowContext(ctx,
+		`SELECT `+promotionColumns+` FROM promotions WHERE id = ?`,
+		id,
+	))
+
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("promotion %w", db.ErrNotFound)
+	}
+
+	return promotion, err
+}
+
+func (s *SQLiteStorage) GetProjectPromotions(ctx context.Context, projectID string, status *model.PromotionStatus) ([]*model.Promotion, error) {
+	query := `SELECT ` + promotionColumns + ` FROM promotions WHERE project_id = ?`
+	args := []any{projectID}
+	if status != nil {
+		query += ` AND status = ?`
+		args = append(args, *status)
+	}
+
+	rows, err := s.db.QueryContext(ctx, query+` ORDER BY created_at DESC`, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var promotions []*model.Promotion
+	for rows.Next() {
+		promotion, err := scanPromotion(rows)
+		if err != nil {
+			return nil, err
+		}
+		promotions = append(promotions, promotion)
+	}
+
+	return promotions, rows.Err()
+}
+
+func (s *SQLiteStorage) ApplyPromotion(ctx context.Context, promotion *model.Promotion) error {
+	changes := make([]storedChange, 0, len(promotion.Changes))
+	for _, change := range promotion.Changes {
+		changes = append(changes, storedChange{
+			FlagID:         change.FeatureFlag.ID,
+			FlagKey:        change.FlagKey,
+			EnabledBefore:  change.EnabledBefore,
+			EnabledAfter:   change.EnabledAfter,
+			TargetsAdded:   change.TargetsAdded,
+			TargetsRemoved: change.TargetsRemoved,
+			TargetsMoved:   change.TargetsMoved,
+		})
+	}
+	changesJSON, err := json.Marshal(changes)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	promotion.Status = model.PromotionStatusApplied
+	promotion.DecidedAt = &now
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if promotion.ID == nil {
+		id := uuid.New().String()
+		promotion.ID = &id
+		promotion.CreatedAt = now
+
+		_, err = tx.ExecContext(ctx,
+			`INSERT INTO promotions (id, project_id, flag_id, from_environment, to_environment, status, changes, requested_by_id, created_at, decided_by_id, decided_at)
+			VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+			id, promotion.Project.ID, promotionFlagID(promotion), promotion.From, promotion.To, promotion.Status,
+			string(changesJSON), promotion.RequestedBy.ID, promotion.CreatedAt, promotion.DecidedBy.ID, now,
+		)
+		if err != nil {
+			return err
+		}
+	} else {
+		// Approving twice must not apply twice, only the first decision finds the promotion pending
+		res, err := tx.ExecContext(ctx,
+			`UPDATE promotions SET status = ?, changes = ?, decided_by_id = ?, decided_at = ? WHERE id = ? AND status = ?`,
+			promotion.Status, string(changesJSON), promotion.DecidedBy.ID, now, *promotion.ID, model.PromotionStatusPendingApproval,
+		)
+		if err != nil {
+			return err
+		}
+		if n, err := res.RowsAffected(); err != nil {
+			return err
+		} else if n == 0 {
+			return fmt.Errorf("promotion is no longer pending %w", db.ErrConflict)
+		}
+	}
+
+	for _, change := range changes {
+		if err := copyFlagState(ctx, tx, change.FlagID, promotion.From, promotion.To, promotion.DecidedBy.ID, now); err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+func (s *SQLiteStorage) RejectPromotion(ctx context.Context, promotion *model.Promotion) error {
+	now := time.Now()
+
+	res, err := s.db.ExecContext(ctx,
+		`UPDATE promotions SET status = ?, decided_by_id = ?, decided_at = ? WHERE id = ? AND status = ?`,
+		model.PromotionStatusRejected, promotion.DecidedBy.ID, now, *promotion.ID, model.PromotionStatusPendingApproval,
+	)
+	if err != nil {
+		return err
+	}
+	if n, err := res.RowsAffected(); err != nil {
+		return err
+	} else if n == 0 {
+		return fmt.Errorf("promotion is no longer pending %w", db.ErrConflict)
+	}
+
+	promotion.Status = model.PromotionStatusRejected
+	promotion.DecidedAt = &now
+	return nil
+}
+
+// copyFlagState makes the state of a flag in one environment, on/off and individual targets, the same
+// as in another. Targets that already match keep their creation time.
+func copyFlagState(ctx context.Context, tx *sql.Tx, flagID string, from, to model.Environment, updatedByID string, now time.Time) error {
+	var enabled bool
+	err := tx.QueryRowContext(ctx,
+		`SELECT enabled FROM toggle_states WHERE feature_flag_id = ? AND environment = ?`,
+		flagID, from,
+	).Scan(&enabled)
+	if err == sql.ErrNoRows {
+		return fmt.Errorf("toggle state %w", db.ErrNotFound)
+	}
+	if err != nil {
+		return err
+	}
+
+	var previous sql.NullBool
+	err = tx.QueryRowContext(ctx,
+		`SELECT enabled FROM toggle_states WHERE feature_flag_id = ? AND environment = ?`,
+		flagID, to,
+	).Scan(&previous)
+	if err != nil && err != sql.ErrNoRows {
+		return err
+	}
+
+	if previous.Valid {
+		_, err = tx.ExecContext(ctx,
+			`UPDATE toggle_states SET enabled = ?, updated_by_id = ?, updated_at = ? WHERE feature_flag_id = ? AND environment = ?`,
+			enabled, updatedByID, now, flagID, to,
+		)
+	} else {
+		_, err = tx.ExecContext(ctx,
+			`INSERT INTO toggle_states (id, feature_flag_id, environment, enabled, updated_by_id, updated_at) VALUES (?, ?, ?, ?, ?, ?)`,
+			uuid.New().String(), flagID, to, enabled, updatedByID, now,
+		)
+	}
+	if err != nil {
+		return err
+	}
+
+	if _, err := tx.ExecContext(ctx,
+		`DELETE FROM individual_targets WHERE flag_id = ? AND environment = ?
+			AND context_key NOT IN (SELECT context_key FROM individual_targets WHERE flag_id = ? AND environment = ?)`,
+		flagID, to, flagID, from,
+	); err != nil {
+		return err
+	}
+
+	// SQLite needs the WHERE clause to tell the upsert apart from a join constraint
+	if _, err := tx.ExecContext(ctx,
+		`INSERT INTO individual_targets (flag_id, environment, context_key, variant, created_at)
+		SELECT flag_id, ?, context_key, variant, ? FROM individual_targets WHERE flag_id = ? AND environment = ?
+		ON CONFLICT (flag_id, environment, context_key) DO UPDATE SET
+			variant = excluded.variant,
+			created_at = excluded.created_at
+		WHERE variant != excluded.variant`,
+		to, now, flagID, from,
+	); err != nil {
+		return err
+	}
+
+	if previous.Valid && previous.Bool == enabled {
+		return recordFlagChange(ctx, tx, model.WebhookEventTypeFlagUpdated, flagID)
+	}
+
+	flag, err := loadFlag(ctx, tx, flagID)
+	if err != nil {
+		return err
+	}
+
+	return recordFlagEvent(ctx, tx, model.WebhookEventTypeFlagToggled, flag, &db.WebhookToggle{
+		Environment:     to,
+		Enabled:         enabled,
+		PreviousEnabled: previous.Bool,
+		UpdatedByID:     updatedByID,
+	})
+}
+
+func promotionFlagID(promotion *model.Promotion) *string {
+	if promotion.FeatureFlag == nil {
+		return nil
+	}
+	return &promotion.FeatureFlag.ID
+}
+
+const promotionColumns = `id, project_id, flag_id, from_environment, to_environment, status, changes, requested_by_id, created_at, decided_by_id, decided_at`
+
+func scanPromotion(row scanner) (*model.Promotion, error) {
+	var p model.Promotion
+	var id, projectID, requestedByID, changes string
+	var flagID, decidedByID sql.NullString
+	var decidedAt sql.NullTime
+
+	if err := row.Scan(&id, &projectID, &flagID, &p.From, &p.To, &p.Status, &changes, &requestedByID, &p.CreatedAt,
+		&decidedByID, &decidedAt); err != nil {
+		return nil, err
+	}
+
+	var stored []storedChange
+	if err := json.Unmarshal([]byte(changes), &stored); err != nil {
+		return nil, fmt.Errorf("invalid changes of promotion %s: %w", id, err)
+	}
+	p.Changes = make([]*model.FlagStateChange, 0, len(stored))
+	for _, change := range stored {
+		p.Changes = append(p.Changes, &model.FlagStateChange{
+			FeatureFlag:    &model.FeatureFlag{ID: change.FlagID},
+			FlagKey:        change.FlagKey,
+			EnabledBefore:  change.EnabledBefore,
+			EnabledAfter:   change.EnabledAfter,
+			TargetsAdded:   change.TargetsAdded,
+			TargetsRemoved: change.TargetsRemoved,
+			TargetsMoved:   change.TargetsMoved,
+		})
+	}
+
+	p.ID = &id
+	p.Project = &model.Project{ID: projectID}
+	p.RequestedBy = &model.User{ID: requestedByID}
+	if flagID.Valid {
+		p.FeatureFlag = &model.FeatureFlag{ID: flagID.String}
+	}
+	if decidedByID.Valid {
+		p.DecidedBy = &model.User{ID: decidedByID.String}
+	}
+	p.DecidedAt = nullableTime(decidedAt)
+
+	return &p, nil
+}
@@ -0,0 +1,78 @@
+package sqlite
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/shubham-tomar/feature-toggler/graphQl/model"
+)
+
+// UpdateProjectProtection sets the protected environments of a project and records who changed them, in one transaction
+func (s *SQLiteStorage) UpdateProjectProtection(ctx context.Context, projectID string, change *model.ProtectionChange) error {
+	if change.Environments == nil {
+		change.Environments = []model.Environment{}
+	}
+	environmentsJSON, err := json.Marshal(change.Environments)
+	if err != nil {
+		return err
+	}
+
+	change.ChangedAt = time.Now()
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	res, err := tx.ExecContext(ctx,
+		`UPDATE projects SET protected_environments = ?, updated_at = ? WHERE id = ?`,
+		string(environmentsJSON), change.ChangedAt, projectID,
+	)
+	if err != nil {
+		return err
+	}
+	if err := requireRow(res, "project"); err != nil {
+		return err
+	}
+
+	_, err = tx.ExecContext(ctx,
+		`INSERT INTO protection_changes (id, project_id, environments, changed_by_id, changed_at) VALUES (?, ?, ?, ?, ?)`,
+		uuid.New().String(), projectID, string(environmentsJSON), change.ChangedBy.ID, change.ChangedAt,
+	)
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func (s *SQLiteStorage) GetProtectionChanges(ctx context.Context, projectID string) ([]*model.ProtectionChange, error) {
+	rows, err := s.db.QueryContext(ctx,
+		`SELECT environments, changed_by_id, changed_at FROM protection_changes WHERE project_id = ? ORDER BY changed_at DESC`,
+		projectID,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	changes := []*model.ProtectionChange{}
+	for rows.Next() {
+		var environments, changedByID string
+		change := &model.ProtectionChange{}
+		if err := rows.Scan(&environments, &changedByID, &change.ChangedAt); err != nil {
+			return nil, err
+		}
+		if err := json.Unmarshal([]byte(environments), &change.Environments); err != nil {
+			return nil, fmt.Errorf("invalid environments of a protection change of project %s: %w", projectID, err)
+		}
+		change.ChangedBy = &model.User{ID: changedByID}
+		changes = append(changes, change)
+	}
+
+	return changes, rows.Err()
+}
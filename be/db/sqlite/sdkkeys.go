@@ -0,0 +1,166 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/shubham-tomar/feature-toggler/db"
+	"github.com/shubham-tomar/feature-toggler/graphQl/model"
+)
+
+const sdkKeyColumns = `id, project_id, environment, kind, name, prefix, created_by_id, created_at, expires_at, revoked_at`
+
+// SDK key operations
+func (s *SQLiteStorage) CreateSdkKey(ctx context.Context, key *model.SdkKey, keyHash string) error {
+	return insertSdkKey(ctx, s.db, key, keyHash)
+}
+
+func (s *SQLiteStorage) GetSdkKeyByID(ctx context.Context, id string) (*model.SdkKey, error) {
+	row := s.db.QueryRowContext(ctx,
+		`SELECT `+sdkKeyColumns+` FROM sdk_keys WHERE id = ?`,
+		id,
+	)
+	return s.scanSdkKey(ctx, row)
+}
+
+func (s *SQLiteStorage) GetSdkKeyByHash(ctx context.Context, keyHash string) (*model.SdkKey, error) {
+	row := s.db.QueryRowContext(ctx,
+		`SELECT `+sdkKeyColumns+` FROM sdk_keys WHERE key_hash = ?`,
+		keyHash,
+	)
+	return s.scanSdkKey(ctx, row)
+}
+
+func (s *SQLiteStorage) GetProjectSdkKeys(ctx context.Context, projectID string, environment *model.Environment) ([]*model.SdkKey, error) {
+	query := `SELECT ` + sdkKeyColumns + ` FROM sdk_keys WHERE project_id = ?`
+	args := []any{projectID}
+	if environment != nil {
+		query += ` AND environment = ?`
+		args = append(args, *environment)
+	}
+	query += ` ORDER BY created_at`
+
+	rows, err := s.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var keys []*model.SdkKey
+	for rows.Next() {
+		key, err := s.scanSdkKey(ctx, rows)
+		if err != nil {
+			return nil, err
+		}
+		keys = append(keys, key)
+	}
+
+	return keys, rows.Err()
+}
+
+func (s *SQLiteStorage) RotateSdkKey(ctx context.Context, oldID string, oldExpiresAt time.Time, key *model.SdkKey, keyHash string) error {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+
+	res, err := tx.ExecContext(ctx,
+		`UPDATE sdk_keys SET expires_at = ? WHERE id = ? AND revoked_at IS NULL`,
+		oldExpiresAt, oldID,
+	)
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	if n, _ := res.RowsAffected(); n == 0 {
+		tx.Rollback()
+		return fmt.Errorf("active sdk key %w", db.ErrNotFound)
+	}
+
+	if err := insertSdkKey(ctx, tx, key, keyHash); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func (s *SQLiteStorage) RevokeSdkKey(ctx context.Context, id string) error {
+	_, err := s.db.ExecContext(ctx,
+		`UPDATE sdk_keys SET revoked_at = ? WHERE id = ? AND revoked_at IS NULL`,
+		time.Now(), id,
+	)
+
+	return err
+}
+
+type execer interface {
+	ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+}
+
+func insertSdkKey(ctx context.Context, e execer, key *model.SdkKey, keyHash string) error {
+	if key.ID == "" {
+		key.ID = uuid.New().String()
+	}
+	key.CreatedAt = time.Now()
+
+	var projectID, createdByID string
+	if key.Project != nil {
+		projectID = key.Project.ID
+	}
+	if key.CreatedBy != nil {
+		createdByID = key.CreatedBy.ID
+	}
+
+	_, err := e.ExecContext(ctx,
+		`INSERT INTO sdk_keys (id, project_id, environment, kind, name, prefix, key_hash, created_by_id, created_at, expires_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		key.ID, projectID, key.Environment, key.Kind, key.Name, key.Prefix, keyHash, createdByID, key.CreatedAt, key.ExpiresAt,
+	)
+
+	return err
+}
+
+type scanner interface {
+	Scan(dest ...any) error
+}
+
+func (s *SQLiteStorage) scanSdkKey(ctx context.Context, row scanner) (*model.SdkKey, error) {
+	var key model.SdkKey
+	var projectID, createdByID string
+	var expiresAt, revokedAt sql.NullTime
+
+	err := row.Scan(&key.ID, &projectID, &key.Environment, &key.Kind, &key.Name, &key.Prefix,
+		&createdByID, &key.CreatedAt, &expiresAt, &revokedAt)
+
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("sdk key %w", db.ErrNotFound)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	if expiresAt.Valid {
+		key.ExpiresAt = &expiresAt.Time
+	}
+	if revokedAt.Valid {
+		key.RevokedAt = &revokedAt.Time
+	}
+
+	key.Project = &model.Project{ID: projectID}
+
+	// Get created by user data if available
+	key.CreatedBy = &model.User{ID: createdByID}
+	if createdByID != "" {
+		if user, err := s.GetUserByID(ctx, createdByID); err == nil {
+			key.CreatedBy = user
+		}
+	}
+
+	return &key, nil
+}
@@ -0,0 +1,53 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/shubham-tomar/feature-toggler/db"
+	"github.com/shubham-tomar/feature-toggler/graphQl/model"
+)
+
+// Login session operations
+func (s *SQLiteStorage) CreateSession(ctx context.Context, userID string, tokenHash string, expiresAt time.Time) error {
+	_, err := s.db.ExecContext(ctx,
+		`INSERT INTO sessions (token_hash, user_id, created_at, expires_at) VALUES (?, ?, ?, ?)`,
+		tokenHash, userID, time.Now(), expiresAt,
+	)
+
+	return err
+}
+
+func (s *SQLiteStorage) GetSessionUser(ctx context.Context, tokenHash string) (*model.User, error) {
+	var userID string
+	var expiresAt time.Time
+	err := s.db.QueryRowContext(ctx,
+		`SELECT user_id, expires_at FROM sessions WHERE token_hash = ?`,
+		tokenHash,
+	).Scan(&userID, &expiresAt)
+
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("session %w", db.ErrNotFound)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	if !time.Now().Before(expiresAt) {
+		// Expired sessions are cleaned up lazily when they are presented
+		if err := s.DeleteSession(ctx, tokenHash); err != nil {
+			return nil, err
+		}
+		return nil, fmt.Errorf("session %w", db.ErrNotFound)
+	}
+
+	return s.GetUserByID(ctx, userID)
+}
+
+func (s *SQLiteStorage) DeleteSession(ctx context.Context, tokenHash string) error {
+	_, err := s.db.ExecContext(ctx, `DELETE FROM sessions WHERE token_hash = ?`, tokenHash)
+	return err
+}
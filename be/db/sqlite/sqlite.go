@@ -3,15 +3,16 @@ package sqlite
 import (
 	"context"
 	"database/sql"
+	"encoding/json"
 	"errors"
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/mattn/go-sqlite3"
 	"github.com/shubham-tomar/feature-toggler/db"
 	"github.com/shubham-tomar/feature-toggler/graphQl/model"
-
-	_ "github.com/mattn/go-sqlite3"
 )
 
 type SQLiteStorage struct {
@@ -34,8 +35,29 @@ func (f *SQLiteFactory) NewStorage() (db.Storage, error) {
 	}, nil
 }
 
+// connectionOptions let the background writers, analytics, webhooks, scheduled change sets and
+// health checks, wait for each other instead of failing with "database is locked", and let reads
+// go on while they write
+var connectionOptions = []string{"_busy_timeout=5000", "_journal_mode=WAL"}
+
+// dsn adds the connection options the path does not set itself
+func dsn(path string) string {
+	sep := "?"
+	if strings.Contains(path, "?") {
+		sep = "&"
+	}
+	for _, option := range connectionOptions {
+		name, _, _ := strings.Cut(option, "=")
+		if !strings.Contains(path, name+"=") {
+			path += sep + option
+			sep = "&"
+		}
+	}
+	return path
+}
+
 func (s *SQLiteStorage) Connect() error {
-	database, err := sql.Open("sqlite3", s.dbPath)
+	database, err := sql.Open("sqlite3", dsn(s.dbPath))
 	if err != nil {
 		return fmt.Errorf("failed to open database: %w", err)
 	}
@@ -60,56 +82,68 @@ func (s *SQLiteStorage) CreateUser(ctx context.Context, user *model.User) error
 		user.ID = uuid.New().String()
 	}
 
+	if user.Kind == "" {
+		user.Kind = model.UserKindHuman
+	}
+
+	// Email addresses are compared without case, they are stored in lowercase
+	user.Email = strings.ToLower(user.Email)
+
 	now := time.Now()
 	user.CreatedAt = now
 	user.UpdatedAt = now
 
+	var createdByID *string
+	if user.CreatedBy != nil {
+		createdByID = &user.CreatedBy.ID
+	}
+
 	_, err := s.db.ExecContext(ctx,
-		`INSERT INTO users (id, name, email, created_at, updated_at) 
-		VALUES (?, ?, ?, ?, ?)`,
-		user.ID, user.Name, user.Email, user.CreatedAt, user.UpdatedAt,
+		`INSERT INTO users (id, name, email, kind, created_by_id, created_at, updated_at) 
+		VALUES (?, ?, ?, ?, ?, ?, ?)`,
+		user.ID, user.Name, user.Email, user.Kind, createdByID, user.CreatedAt, user.UpdatedAt,
 	)
 
 	return err
 }
 
 func (s *SQLiteStorage) GetUserByID(ctx context.Context, id string) (*model.User, error) {
-	var user model.User
-	err := s.db.QueryRowContext(ctx,
-		`SELECT id, name, email, created_at, updated_at FROM users WHERE id = ?`,
+	user, err := scanUser(s.db.QueryRowContext(ctx,
+		`SELECT `+userColumns+` FROM users WHERE id = ?`,
 		id,
-	).Scan(&user.ID, &user.Name, &user.Email, &user.CreatedAt, &user.UpdatedAt)
+	))
 
 	if err == sql.ErrNoRows {
-		return nil, errors.New("user not found")
+		return nil, fmt.Errorf("user %w", db.ErrNotFound)
 	}
 
 	if err != nil {
 		return nil, err
 	}
 
-	return &user, nil
+	return user, nil
 }
 
 func (s *SQLiteStorage) GetUserByEmail(ctx context.Context, email string) (*model.User, error) {
-	var user model.User
-	err := s.db.QueryRowContext(ctx,
-		`SELECT id, name, email, created_at, updated_at FROM users WHERE email = ?`,
-		email,
-	).Scan(&user.ID, &user.Name, &user.Email, &user.CreatedAt, &user.UpdatedAt)
+	// Users created before emails were stored in lowercase may still have capitals
+	user, err := scanUser(s.db.QueryRowContext(ctx,
+		`SELECT `+userColumns+` FROM users WHERE lower(email) = ?`,
+		strings.ToLower(email),
+	))
 
 	if err == sql.ErrNoRows {
-		return nil, errors.New("user not found")
+		return nil, fmt.Errorf("user %w", db.ErrNotFound)
 	}
 
 	if err != nil {
 		return nil, err
 	}
 
-	return &user, nil
+	return user, nil
 }
 
 func (s *SQLiteStorage) UpdateUser(ctx context.Context, user *model.User) error {
+	user.Email = strings.ToLower(user.Email)
 	user.UpdatedAt = time.Now()
 
 	_, err := s.db.ExecContext(ctx,
@@ -120,34 +154,71 @@ func (s *SQLiteStorage) UpdateUser(ctx context.Context, user *model.User) error
 	return err
 }
 
-// Project operations
-
-func (s *SQLiteStorage) GetProjects(ctx context.Context) ([]*model.Project, error) {
+func (s *SQLiteStorage) GetServiceAccounts(ctx context.Context) ([]*model.User, error) {
 	rows, err := s.db.QueryContext(ctx,
-		`SELECT id, name, created_at, updated_at FROM projects`,
+		`SELECT `+userColumns+` FROM users WHERE kind = ? ORDER BY name`,
+		model.UserKindServiceAccount,
 	)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
 
-	var projects []*model.Project
+	var users []*model.User
 	for rows.Next() {
-		var p model.Project
-		if err := rows.Scan(&p.ID, &p.Name, &p.CreatedAt, &p.UpdatedAt); err != nil {
+		u, err := scanUser(rows)
+		if err != nil {
 			return nil, err
 		}
+		users = append(users, u)
+	}
+
+	return users, rows.Err()
+}
+
+const userColumns = `id, name, email, kind, created_by_id, created_at, updated_at`
 
-		// Get project members
-		p.Members, err = s.GetProjectMembers(ctx, p.ID)
+// scanUser reads a row of userColumns, the creator only carries its id
+func scanUser(row scanner) (*model.User, error) {
+	var u model.User
+	var createdByID sql.NullString
+
+	if err := row.Scan(&u.ID, &u.Name, &u.Email, &u.Kind, &createdByID, &u.CreatedAt, &u.UpdatedAt); err != nil {
+		return nil, err
+	}
+
+	u.CreatedBy = nullableUser(createdByID)
+	return &u, nil
+}
+
+// Project operations
+
+func (s *SQLiteStorage) GetProjects(ctx context.Context, page db.Page) ([]*model.Project, int, error) {
+	var total int
+	if err := s.db.QueryRowContext(ctx, `SELECT COUNT(*) FROM projects`).Scan(&total); err != nil {
+		return nil, 0, err
+	}
+
+	rows, err := s.db.QueryContext(ctx,
+		`SELECT `+projectColumns+` FROM projects ORDER BY created_at, id LIMIT ? OFFSET ?`,
+		sqlLimit(page), page.Offset,
+	)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	var projects []*model.Project
+	for rows.Next() {
+		p, err := scanProject(rows)
 		if err != nil {
-			return nil, fmt.Errorf("error getting project members: %w", err)
+			return nil, 0, err
 		}
 
-		projects = append(projects, &p)
+		projects = append(projects, p)
 	}
 
-	return projects, nil
+	return projects, total, rows.Err()
 }
 
 func (s *SQLiteStorage) CreateProject(ctx context.Context, user *model.User, name string) (*model.Project, error) {
@@ -196,35 +267,26 @@ func (s *SQLiteStorage) CreateProject(ctx context.Context, user *model.User, nam
 }
 
 func (s *SQLiteStorage) GetProjectByID(ctx context.Context, id string) (*model.Project, error) {
-	var project model.Project
-	err := s.db.QueryRowContext(ctx,
-		`SELECT id, name, created_at, updated_at FROM projects WHERE id = ?`,
+	project, err := scanProject(s.db.QueryRowContext(ctx,
+		`SELECT `+projectColumns+` FROM projects WHERE id = ?`,
 		id,
-	).Scan(&project.ID, &project.Name, &project.CreatedAt, &project.UpdatedAt)
+	))
 
 	if err == sql.ErrNoRows {
-		return nil, errors.New("project not found")
+		return nil, fmt.Errorf("project %w", db.ErrNotFound)
 	}
 
 	if err != nil {
 		return nil, err
 	}
 
-	// Get project members
-	project.Members, err = s.GetProjectMembers(ctx, project.ID)
-	if err != nil {
-		return nil, fmt.Errorf("error getting project members: %w", err)
-	}
-
-	return &project, nil
+	return project, nil
 }
 
 func (s *SQLiteStorage) GetUserProjects(ctx context.Context, user *model.User) ([]*model.Project, error) {
 	rows, err := s.db.QueryContext(ctx,
-		`SELECT p.id, p.name, p.created_at, p.updated_at 
-		FROM projects p
-		JOIN project_users pu ON p.id = pu.project_id
-		WHERE pu.user_id = ?`,
+		`SELECT `+projectColumns+` FROM projects
+		WHERE id IN (SELECT project_id FROM project_users WHERE user_id = ?)`,
 		user.ID,
 	)
 
@@ -235,32 +297,69 @@ func (s *SQLiteStorage) GetUserProjects(ctx context.Context, user *model.User) (
 
 	var projects []*model.Project
 	for rows.Next() {
-		var p model.Project
-		if err := rows.Scan(&p.ID, &p.Name, &p.CreatedAt, &p.UpdatedAt); err != nil {
-			return nil, err
-		}
-
-		// Get project members
-		p.Members, err = s.GetProjectMembers(ctx, p.ID)
+		p, err := scanProject(rows)
 		if err != nil {
-			return nil, fmt.Errorf("error getting project members: %w", err)
+			return nil, err
 		}
 
-		projects = append(projects, &p)
+		projects = append(projects, p)
 	}
 
 	return projects, nil
 }
 
+const projectColumns = `id, name, flag_key_pattern, flag_key_max_length, new_flag_days, stale_after_days, unused_after_days, protected_environments, created_at, updated_at`
+
+func scanProject(row scanner) (*model.Project, error) {
+	var p model.Project
+	var pattern sql.NullString
+	var maxLength, newDays, staleDays, unusedDays sql.NullInt64
+	var protected string
+
+	if err := row.Scan(&p.ID, &p.Name, &pattern, &maxLength, &newDays, &staleDays, &unusedDays, &protected, &p.CreatedAt, &p.UpdatedAt); err != nil {
+		return nil, err
+	}
+
+	if err := json.Unmarshal([]byte(protected), &p.ProtectedEnvironments); err != nil {
+		return nil, fmt.Errorf("invalid protected environments of project %s: %w", p.ID, err)
+	}
+
+	if pattern.Valid {
+		p.FlagKeyPattern = &pattern.String
+	}
+	p.FlagKeyMaxLength = nullableInt(maxLength)
+	p.NewFlagDays = nullableInt(newDays)
+	p.StaleAfterDays = nullableInt(staleDays)
+	p.UnusedAfterDays = nullableInt(unusedDays)
+
+	return &p, nil
+}
+
+func nullableInt(n sql.NullInt64) *int {
+	if !n.Valid {
+		return nil
+	}
+	v := int(n.Int64)
+	return &v
+}
+
+// UpdateProject leaves the protected environments alone, they only change through UpdateProjectProtection
 func (s *SQLiteStorage) UpdateProject(ctx context.Context, project *model.Project) error {
 	project.UpdatedAt = time.Now()
 
-	_, err := s.db.ExecContext(ctx,
-		`UPDATE projects SET name = ?, updated_at = ? WHERE id = ?`,
-		project.Name, project.UpdatedAt, project.ID,
+	res, err := s.db.ExecContext(ctx,
+		`UPDATE projects SET name = ?, flag_key_pattern = ?, flag_key_max_length = ?,
+			new_flag_days = ?, stale_after_days = ?, unused_after_days = ?, updated_at = ?
+		WHERE id = ?`,
+		project.Name, project.FlagKeyPattern, project.FlagKeyMaxLength,
+		project.NewFlagDays, project.StaleAfterDays, project.UnusedAfterDays, project.UpdatedAt,
+		project.ID,
 	)
+	if err != nil {
+		return err
+	}
 
-	return err
+	return requireRow(res, "project")
 }
 
 func (s *SQLiteStorage) DeleteProject(ctx context.Context, id string) error {
@@ -285,57 +384,191 @@ func (s *SQLiteStorage) AddProjectMember(ctx context.Context, membership *model.
 }
 
 func (s *SQLiteStorage) UpdateProjectMemberRole(ctx context.Context, membershipID string, role model.Role) error {
-	_, err := s.db.ExecContext(ctx,
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+
+	res, err := tx.ExecContext(ctx,
 		`UPDATE project_users SET role = ?, updated_at = ? WHERE id = ?`,
 		role, time.Now(), membershipID,
 	)
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
 
-	return err
+	if n, _ := res.RowsAffected(); n == 0 {
+		tx.Rollback()
+		return fmt.Errorf("project member %w", db.ErrNotFound)
+	}
+
+	if err := ensureAdminRemains(ctx, tx, membershipID); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	return tx.Commit()
 }
 
 func (s *SQLiteStorage) RemoveProjectMember(ctx context.Context, membershipID string) error {
-	_, err := s.db.ExecContext(ctx,
-		`DELETE FROM project_users WHERE id = ?`,
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+
+	var projectID string
+	err = tx.QueryRowContext(ctx,
+		`SELECT project_id FROM project_users WHERE id = ?`,
 		membershipID,
-	)
+	).Scan(&projectID)
+	if err == sql.ErrNoRows {
+		tx.Rollback()
+		return fmt.Errorf("project member %w", db.ErrNotFound)
+	}
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
 
-	return err
+	if _, err := tx.ExecContext(ctx, `DELETE FROM project_users WHERE id = ?`, membershipID); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	if err := ensureProjectHasAdmin(ctx, tx, projectID); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	return tx.Commit()
 }
 
-func (s *SQLiteStorage) GetProjectMembers(ctx context.Context, projectID string) ([]*model.ProjectUser, error) {
+// ensureAdminRemains checks, after a change to a membership, that its project still has an admin
+func ensureAdminRemains(ctx context.Context, tx *sql.Tx, membershipID string) error {
+	var projectID string
+	if err := tx.QueryRowContext(ctx,
+		`SELECT project_id FROM project_users WHERE id = ?`,
+		membershipID,
+	).Scan(&projectID); err != nil {
+		return err
+	}
+
+	return ensureProjectHasAdmin(ctx, tx, projectID)
+}
+
+// The check runs after the write inside the same transaction, so concurrent changes cannot both pass it
+func ensureProjectHasAdmin(ctx context.Context, tx *sql.Tx, projectID string) error {
+	var admins int
+	if err := tx.QueryRowContext(ctx,
+		`SELECT COUNT(*) FROM project_users WHERE project_id = ? AND role = ?`,
+		projectID, model.RoleAdmin,
+	).Scan(&admins); err != nil {
+		return err
+	}
+
+	if admins == 0 {
+		return db.ErrLastAdmin
+	}
+
+	return nil
+}
+
+func (s *SQLiteStorage) GetProjectMember(ctx context.Context, membershipID string) (*model.ProjectUser, error) {
+	var m model.ProjectUser
+	var userID, projectID string
+
+	err := s.db.QueryRowContext(ctx,
+		`SELECT id, user_id, project_id, role FROM project_users WHERE id = ?`,
+		membershipID,
+	).Scan(&m.ID, &userID, &projectID, &m.Role)
+
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("project member %w", db.ErrNotFound)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	m.User, err = s.GetUserByID(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("error getting user: %w", err)
+	}
+
+	m.Project, err = s.GetProjectByID(ctx, projectID)
+	if err != nil {
+		return nil, fmt.Errorf("error getting project: %w", err)
+	}
+
+	return &m, nil
+}
+
+func (s *SQLiteStorage) GetUserMemberships(ctx context.Context, userID string) ([]*model.ProjectUser, error) {
 	rows, err := s.db.QueryContext(ctx,
-		`SELECT pu.id, pu.user_id, pu.project_id, pu.role 
-		FROM project_users pu WHERE pu.project_id = ?`,
-		projectID,
+		`SELECT id, project_id, role FROM project_users WHERE user_id = ?`,
+		userID,
 	)
-
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
 
-	var members []*model.ProjectUser
+	type membershipRow struct {
+		membership *model.ProjectUser
+		projectID  string
+	}
+
+	var found []membershipRow
 	for rows.Next() {
 		var m model.ProjectUser
-		var userID, projectID string
-
-		if err := rows.Scan(&m.ID, &userID, &projectID, &m.Role); err != nil {
+		var projectID string
+		if err := rows.Scan(&m.ID, &projectID, &m.Role); err != nil {
 			return nil, err
 		}
+		found = append(found, membershipRow{membership: &m, projectID: projectID})
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	rows.Close()
 
-		m.Project = &model.Project{ID: projectID}
+	user, err := s.GetUserByID(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("error getting user: %w", err)
+	}
 
-		user, err := s.GetUserByID(ctx, userID)
-		if err != nil {
-			m.User = &model.User{ID: userID}
-		} else {
-			m.User = user
-		}
+	projectIDs := make([]string, len(found))
+	for i, row := range found {
+		projectIDs[i] = row.projectID
+	}
 
-		members = append(members, &m)
+	projects, err := s.GetProjectsByIDs(ctx, projectIDs)
+	if err != nil {
+		return nil, fmt.Errorf("error getting projects: %w", err)
 	}
 
-	return members, nil
+	byID := make(map[string]*model.Project, len(projects))
+	for _, project := range projects {
+		byID[project.ID] = project
+	}
+
+	memberships := make([]*model.ProjectUser, 0, len(found))
+	for _, row := range found {
+		project, ok := byID[row.projectID]
+		if !ok {
+			continue
+		}
+		row.membership.User = user
+		row.membership.Project = project
+		memberships = append(memberships, row.membership)
+	}
+
+	return memberships, nil
+}
+
+func (s *SQLiteStorage) GetProjectMembers(ctx context.Context, projectID string) ([]*model.ProjectUser, error) {
+	return s.GetMembersByProjectIDs(ctx, []string{projectID})
 }
 
 // Feature flag operations
@@ -347,6 +580,7 @@ func (s *SQLiteStorage) CreateFeatureFlag(ctx context.Context, flag *model.Featu
 	now := time.Now()
 	flag.CreatedAt = now
 	flag.UpdatedAt = now
+	flag.Status = model.FlagStatusActive
 
 	tx, err := s.db.BeginTx(ctx, nil)
 	if err != nil {
@@ -368,12 +602,25 @@ func (s *SQLiteStorage) CreateFeatureFlag(ctx context.Context, flag *model.Featu
 		description = *flag.Description
 	}
 
+	meta, err := flagMetadata(flag)
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+
 	_, err = tx.ExecContext(ctx,
-		`INSERT INTO feature_flags (id, key, name, description, project_id, created_by_id, created_at, updated_at) 
-		VALUES (?, ?, ?, ?, ?, ?, ?, ?)`,
-		flag.ID, flag.Key, flag.Name, description, projectID, createdByID, flag.CreatedAt, flag.UpdatedAt,
+		`INSERT INTO feature_flags (id, key, name, description, project_id, created_by_id, client_side, created_at, updated_at,
+			kind, tags, owner_id, owner_team, removal_date, links) 
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		append([]any{flag.ID, flag.Key, flag.Name, description, projectID, createdByID, flag.ClientSide, flag.CreatedAt, flag.UpdatedAt},
+			meta...)...,
 	)
 
+	if isUniqueViolation(err) {
+		tx.Rollback()
+		return fmt.Errorf("feature flag key %q %w", flag.Key, db.ErrConflict)
+	}
+
 	if err != nil {
 		tx.Rollback()
 		return err
@@ -407,85 +654,59 @@ func (s *SQLiteStorage) CreateFeatureFlag(ctx context.Context, flag *model.Featu
 		}
 	}
 
+	if err := recordFlagChange(ctx, tx, model.WebhookEventTypeFlagCreated, flag.ID); err != nil {
+		tx.Rollback()
+		return err
+	}
+
 	return tx.Commit()
 }
 
 func (s *SQLiteStorage) GetFeatureFlagByID(ctx context.Context, id string) (*model.FeatureFlag, error) {
-	var flag model.FeatureFlag
-	var projectID, createdByID string
-	var description sql.NullString
-
-	err := s.db.QueryRowContext(ctx,
-		`SELECT id, key, name, description, project_id, created_by_id, created_at, updated_at 
-		FROM feature_flags WHERE id = ?`,
+	// Related records are resolved lazily by the GraphQL layer
+	flag, err := scanFeatureFlag(s.db.QueryRowContext(ctx,
+		`SELECT `+featureFlagColumns+` FROM feature_flags WHERE id = ?`,
 		id,
-	).Scan(&flag.ID, &flag.Key, &flag.Name, &description, &projectID, &createdByID, &flag.CreatedAt, &flag.UpdatedAt)
+	))
 
 	if err == sql.ErrNoRows {
-		return nil, errors.New("feature flag not found")
+		return nil, fmt.Errorf("feature flag %w", db.ErrNotFound)
 	}
 
 	if err != nil {
 		return nil, err
 	}
 
-	// Handle description
-	if description.Valid {
-		desc := description.String
-		flag.Description = &desc
-	}
-
-	// Get project data
-	if projectID != "" {
-		project, err := s.GetProjectByID(ctx, projectID)
-		if err != nil {
-			return nil, fmt.Errorf("error getting project: %w", err)
-		}
-		flag.Project = project
-	}
-
-	// Get created by user data
-	if createdByID != "" {
-		user, err := s.GetUserByID(ctx, createdByID)
-		if err != nil {
-			return nil, fmt.Errorf("error getting user: %w", err)
-		}
-		flag.CreatedBy = user
-	}
-
 	// Get toggle states
 	flag.States, err = s.GetFeatureFlagStates(ctx, flag.ID)
 	if err != nil {
 		return nil, fmt.Errorf("error getting toggle states: %w", err)
 	}
 
-	return &flag, nil
+	return flag, nil
 }
 
-func (s *SQLiteStorage) GetFeatureFlagByKey(ctx context.Context, key string) (*model.FeatureFlag, error) {
-	var flag model.FeatureFlag
+func (s *SQLiteStorage) GetFeatureFlagByKey(ctx context.Context, projectID, key string) (*model.FeatureFlag, error) {
+	var id string
 	err := s.db.QueryRowContext(ctx,
-		`SELECT id, key, name, description, project_id, created_by_id, created_at, updated_at 
-		FROM feature_flags WHERE key = ?`,
-		key,
-	).Scan(&flag.ID, &flag.Key, &flag.Name, &flag.Description, &flag.Project.ID,
-		&flag.CreatedBy.ID, &flag.CreatedAt, &flag.UpdatedAt)
+		`SELECT id FROM feature_flags WHERE project_id = ? AND key = ?`,
+		projectID, key,
+	).Scan(&id)
 
 	if err == sql.ErrNoRows {
-		return nil, errors.New("feature flag not found")
+		return nil, fmt.Errorf("feature flag %w", db.ErrNotFound)
 	}
 
 	if err != nil {
 		return nil, err
 	}
 
-	return &flag, nil
+	return s.GetFeatureFlagByID(ctx, id)
 }
 
 func (s *SQLiteStorage) GetProjectFeatureFlags(ctx context.Context, projectID string) ([]*model.FeatureFlag, error) {
 	rows, err := s.db.QueryContext(ctx,
-		`SELECT id, key, name, description, project_id, created_by_id, created_at, updated_at 
-		FROM feature_flags WHERE project_id = ?`,
+		`SELECT `+featureFlagColumns+` FROM feature_flags WHERE project_id = ?`,
 		projectID,
 	)
 
@@ -496,41 +717,258 @@ func (s *SQLiteStorage) GetProjectFeatureFlags(ctx context.Context, projectID st
 
 	var flags []*model.FeatureFlag
 	for rows.Next() {
-		var f model.FeatureFlag
-		if err := rows.Scan(&f.ID, &f.Key, &f.Name, &f.Description, &f.Project.ID,
-			&f.CreatedBy.ID, &f.CreatedAt, &f.UpdatedAt); err != nil {
+		f, err := scanFeatureFlag(rows)
+		if err != nil {
 			return nil, err
 		}
-		flags = append(flags, &f)
+		flags = append(flags, f)
 	}
 
-	return flags, nil
+	return flags, rows.Err()
+}
+
+const featureFlagColumns = `id, key, name, description, project_id, created_by_id, client_side, created_at, updated_at, archived_at,
+	kind, tags, owner_id, owner_team, removal_date, links`
+
+// scanFeatureFlag reads a row of featureFlagColumns, related records only carry their id
+func scanFeatureFlag(row scanner) (*model.FeatureFlag, error) {
+	var f model.FeatureFlag
+	var flagProjectID, createdByID string
+	var description, ownerID, ownerTeam sql.NullString
+	var archivedAt, removalDate sql.NullTime
+	var tags, links string
+
+	if err := row.Scan(&f.ID, &f.Key, &f.Name, &description, &flagProjectID,
+		&createdByID, &f.ClientSide, &f.CreatedAt, &f.UpdatedAt, &archivedAt,
+		&f.Kind, &tags, &ownerID, &ownerTeam, &removalDate, &links); err != nil {
+		return nil, err
+	}
+
+	if err := json.Unmarshal([]byte(tags), &f.Tags); err != nil {
+		return nil, fmt.Errorf("invalid tags of feature flag %s: %w", f.ID, err)
+	}
+	if err := json.Unmarshal([]byte(links), &f.Links); err != nil {
+		return nil, fmt.Errorf("invalid links of feature flag %s: %w", f.ID, err)
+	}
+
+	if ownerID.Valid {
+		f.Owner = &model.User{ID: ownerID.String}
+	}
+	if ownerTeam.Valid {
+		f.OwnerTeam = &ownerTeam.String
+	}
+	if removalDate.Valid {
+		f.RemovalDate = &removalDate.Time
+	}
+
+	if description.Valid {
+		desc := description.String
+		f.Description = &desc
+	}
+
+	setFlagStatus(&f, archivedAt)
+
+	f.Project = &model.Project{ID: flagProjectID}
+	f.CreatedBy = &model.User{ID: createdByID}
+
+	return &f, nil
 }
 
 func (s *SQLiteStorage) UpdateFeatureFlag(ctx context.Context, flag *model.FeatureFlag) error {
 	flag.UpdatedAt = time.Now()
 
-	_, err := s.db.ExecContext(ctx,
-		`UPDATE feature_flags SET name = ?, description = ?, updated_at = ? WHERE id = ?`,
-		flag.Name, flag.Description, flag.UpdatedAt, flag.ID,
+	meta, err := flagMetadata(flag)
+	if err != nil {
+		return err
+	}
+
+	return s.changeFeatureFlag(ctx, model.WebhookEventTypeFlagUpdated, flag.ID,
+		`UPDATE feature_flags SET name = ?, description = ?, updated_at = ?,
+			kind = ?, tags = ?, owner_id = ?, owner_team = ?, removal_date = ?, links = ?
+		WHERE id = ?`,
+		append(append([]any{flag.Name, flag.Description, flag.UpdatedAt}, meta...), flag.ID)...,
 	)
+}
 
-	return err
+func (s *SQLiteStorage) ArchiveFeatureFlag(ctx context.Context, id string) error {
+	now := time.Now()
+	return s.changeFeatureFlag(ctx, model.WebhookEventTypeFlagArchived, id,
+		`UPDATE feature_flags SET archived_at = ?, updated_at = ? WHERE id = ?`,
+		now, now, id,
+	)
+}
+
+func (s *SQLiteStorage) RestoreFeatureFlag(ctx context.Context, id string) error {
+	return s.changeFeatureFlag(ctx, model.WebhookEventTypeFlagRestored, id,
+		`UPDATE feature_flags SET archived_at = NULL, updated_at = ? WHERE id = ?`,
+		time.Now(), id,
+	)
+}
+
+// changeFeatureFlag runs an update of a single flag and queues the matching webhook event in the same transaction
+func (s *SQLiteStorage) changeFeatureFlag(ctx context.Context, eventType model.WebhookEventType, id, query string, args ...any) error {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	res, err := tx.ExecContext(ctx, query, args...)
+	if err != nil {
+		return err
+	}
+
+	if err := requireRow(res, "feature flag"); err != nil {
+		return err
+	}
+
+	if err := recordFlagChange(ctx, tx, eventType, id); err != nil {
+		return err
+	}
+
+	return tx.Commit()
 }
 
+// DeleteFeatureFlag permanently removes a flag together with its toggle states
 func (s *SQLiteStorage) DeleteFeatureFlag(ctx context.Context, id string) error {
-	_, err := s.db.ExecContext(ctx, `DELETE FROM feature_flags WHERE id = ?`, id)
-	return err
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	flag, err := loadFlag(ctx, tx, id)
+	if err != nil {
+		return err
+	}
+
+	// The event describes the flag as it was, so it is queued before the rows go
+	if err := recordFlagEvent(ctx, tx, model.WebhookEventTypeFlagDeleted, flag, nil); err != nil {
+		return err
+	}
+
+	// Change sets waiting to be applied could no longer be, they fail now and can be edited and
+	// submitted again without the flag. Pending promotions of the flag have nothing left to promote.
+	now := time.Now()
+	if _, err := tx.ExecContext(ctx,
+		`UPDATE change_sets SET status = ?, error = ?, updated_at = ?
+		WHERE status IN (?, ?, ?) AND EXISTS (SELECT 1 FROM json_each(change_sets.changes) WHERE json_extract(value, '$.flag_id') = ?)`,
+		model.ChangeSetStatusFailed, fmt.Sprintf("feature flag %s was deleted", flag.Key), now,
+		model.ChangeSetStatusDraft, model.ChangeSetStatusPendingApproval, model.ChangeSetStatusScheduled, id,
+	); err != nil {
+		return err
+	}
+	if _, err := tx.ExecContext(ctx,
+		`DELETE FROM promotions WHERE flag_id = ? AND status = ?`,
+		id, model.PromotionStatusPendingApproval,
+	); err != nil {
+		return err
+	}
+
+	for _, q := range []string{
+		`DELETE FROM toggle_states WHERE feature_flag_id = ?`,
+		`DELETE FROM individual_targets WHERE flag_id = ?`,
+		`DELETE FROM flag_evaluations WHERE flag_id = ?`,
+		`DELETE FROM flag_impressions WHERE flag_id = ?`,
+		`DELETE FROM experiment_exposures WHERE experiment_id IN (SELECT id FROM experiments WHERE flag_id = ?)`,
+		`DELETE FROM experiments WHERE flag_id = ?`,
+	} {
+		if _, err := tx.ExecContext(ctx, q, id); err != nil {
+			return err
+		}
+	}
+
+	res, err := tx.ExecContext(ctx, `DELETE FROM feature_flags WHERE id = ?`, id)
+	if err != nil {
+		return err
+	}
+
+	if err := requireRow(res, "feature flag"); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// flagMetadata returns the values of the kind, tags, owner_id, owner_team, removal_date and links columns
+func flagMetadata(flag *model.FeatureFlag) ([]any, error) {
+	kind := flag.Kind
+	if kind == "" {
+		kind = model.FlagKindRelease
+	}
+
+	tags := flag.Tags
+	if tags == nil {
+		tags = []string{}
+	}
+	tagsJSON, err := json.Marshal(tags)
+	if err != nil {
+		return nil, err
+	}
+
+	links := flag.Links
+	if links == nil {
+		links = []*model.FlagLink{}
+	}
+	linksJSON, err := json.Marshal(links)
+	if err != nil {
+		return nil, err
+	}
+
+	var ownerID *string
+	if flag.Owner != nil {
+		ownerID = &flag.Owner.ID
+	}
+
+	// Stored in UTC so the expiry filter can compare it with the current time
+	var removalDate *time.Time
+	if flag.RemovalDate != nil {
+		utc := flag.RemovalDate.UTC()
+		removalDate = &utc
+	}
+
+	return []any{kind, string(tagsJSON), ownerID, flag.OwnerTeam, removalDate, string(linksJSON)}, nil
+}
+
+func setFlagStatus(flag *model.FeatureFlag, archivedAt sql.NullTime) {
+	flag.Status = model.FlagStatusActive
+	if archivedAt.Valid {
+		flag.Status = model.FlagStatusArchived
+		flag.ArchivedAt = &archivedAt.Time
+	}
+}
+
+func isUniqueViolation(err error) bool {
+	var sqliteErr sqlite3.Error
+	return errors.As(err, &sqliteErr) && sqliteErr.ExtendedCode == sqlite3.ErrConstraintUnique
+}
+
+// requireRow turns an update or delete that matched nothing into a not found error
+func requireRow(res sql.Result, thing string) error {
+	n, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if n == 0 {
+		return fmt.Errorf("%s %w", thing, db.ErrNotFound)
+	}
+
+	return nil
 }
 
 // Toggle state operations
 func (s *SQLiteStorage) GetFeatureFlagStates(ctx context.Context, flagID string) ([]*model.ToggleState, error) {
-	rows, err := s.db.QueryContext(ctx,
+	return s.queryToggleStates(ctx,
 		`SELECT id, feature_flag_id, environment, enabled, updated_by_id, updated_at 
 		FROM toggle_states WHERE feature_flag_id = ?`,
 		flagID,
 	)
+}
 
+// queryToggleStates reads toggle states, related records only carry their id
+func (s *SQLiteStorage) queryToggleStates(ctx context.Context, query string, args ...any) ([]*model.ToggleState, error) {
+	rows, err := s.db.QueryContext(ctx, query, args...)
 	if err != nil {
 		return nil, err
 	}
@@ -545,22 +983,13 @@ func (s *SQLiteStorage) GetFeatureFlagStates(ctx context.Context, flagID string)
 			return nil, err
 		}
 
-		// Initialize nested objects
 		ts.FeatureFlag = &model.FeatureFlag{ID: featureFlagID}
-
-		// Get user data if available
-		if updatedByID != "" {
-			user, err := s.GetUserByID(ctx, updatedByID)
-			if err != nil {
-				return nil, fmt.Errorf("error getting user: %w", err)
-			}
-			ts.UpdatedBy = user
-		}
+		ts.UpdatedBy = &model.User{ID: updatedByID}
 
 		states = append(states, &ts)
 	}
 
-	return states, nil
+	return states, rows.Err()
 }
 
 func (s *SQLiteStorage) UpdateFeatureFlagState(ctx context.Context, state *model.ToggleState) error {
@@ -576,13 +1005,53 @@ func (s *SQLiteStorage) UpdateFeatureFlagState(ctx context.Context, state *model
 		featureFlagID = state.FeatureFlag.ID
 	}
 
-	_, err := s.db.ExecContext(ctx,
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	var previous bool
+	err = tx.QueryRowContext(ctx,
+		`SELECT enabled FROM toggle_states WHERE id = ? AND feature_flag_id = ? AND environment = ?`,
+		state.ID, featureFlagID, state.Environment,
+	).Scan(&previous)
+	if err == sql.ErrNoRows {
+		// Nothing to update
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.ExecContext(ctx,
 		`UPDATE toggle_states 
 		SET enabled = ?, updated_by_id = ?, updated_at = ? 
 		WHERE id = ? AND feature_flag_id = ? AND environment = ?`,
 		state.Enabled, updatedByID, state.UpdatedAt,
 		state.ID, featureFlagID, state.Environment,
 	)
+	if err != nil {
+		return err
+	}
 
-	return err
+	// Saving a state without changing it is not a toggle
+	if previous != state.Enabled {
+		flag, err := loadFlag(ctx, tx, featureFlagID)
+		if err != nil {
+			return err
+		}
+
+		err = recordFlagEvent(ctx, tx, model.WebhookEventTypeFlagToggled, flag, &db.WebhookToggle{
+			Environment:     state.Environment,
+			Enabled:         state.Enabled,
+			PreviousEnabled: previous,
+			UpdatedByID:     updatedByID,
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
 }
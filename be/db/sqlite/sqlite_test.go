@@ -0,0 +1,39 @@
+package sqlite
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func TestConnectionOptions(t *testing.T) {
+	tests := []struct {
+		path, want string
+	}{
+		{"./feature-toggler.db", "./feature-toggler.db?_busy_timeout=5000&_journal_mode=WAL"},
+		{"file:flags.db?cache=shared", "file:flags.db?cache=shared&_busy_timeout=5000&_journal_mode=WAL"},
+		{"flags.db?_journal_mode=DELETE", "flags.db?_journal_mode=DELETE&_busy_timeout=5000"},
+	}
+	for _, tt := range tests {
+		if got := dsn(tt.path); got != tt.want {
+			t.Errorf("dsn(%q) = %q, want %q", tt.path, got, tt.want)
+		}
+	}
+
+	s := &SQLiteStorage{dbPath: filepath.Join(t.TempDir(), "test.db")}
+	if err := s.Connect(); err != nil {
+		t.Fatal(err)
+	}
+	defer s.Close()
+
+	var journalMode string
+	var busyTimeout int
+	if err := s.db.QueryRow(`PRAGMA journal_mode`).Scan(&journalMode); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.db.QueryRow(`PRAGMA busy_timeout`).Scan(&busyTimeout); err != nil {
+		t.Fatal(err)
+	}
+	if journalMode != "wal" || busyTimeout != 5000 {
+		t.Errorf("got journal mode %s and busy timeout %d, want wal and 5000", journalMode, busyTimeout)
+	}
+}
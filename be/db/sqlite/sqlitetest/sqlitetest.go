@@ -0,0 +1,73 @@
+// Package sqlitetest sets up SQLite storages holding a few records, for the tests of the packages
+// built on top of the storage
+package sqlitetest
+
+import (
+	"context"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"github.com/shubham-tomar/feature-toggler/db"
+	"github.com/shubham-tomar/feature-toggler/db/sqlite"
+	"github.com/shubham-tomar/feature-toggler/graphQl/model"
+)
+
+// New returns a migrated storage over a fresh database, closed when the test ends
+func New(t testing.TB) *sqlite.SQLiteStorage {
+	t.Helper()
+
+	storage, err := (&sqlite.SQLiteFactory{DBPath: filepath.Join(t.TempDir(), "test.db")}).NewStorage()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := storage.Connect(); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { storage.Close() })
+
+	s := storage.(*sqlite.SQLiteStorage)
+	if err := sqlite.Migrate(s.GetDB()); err != nil {
+		t.Fatal(err)
+	}
+
+	return s
+}
+
+// User stores a person
+func User(t testing.TB, s db.Storage, name string) *model.User {
+	t.Helper()
+
+	user := &model.User{Name: name, Email: name + "@example.com"}
+	if err := s.CreateUser(context.Background(), user); err != nil {
+		t.Fatal(err)
+	}
+	return user
+}
+
+// Project stores a project with the user as its admin
+func Project(t testing.TB, s db.Storage, admin *model.User, name string) *model.Project {
+	t.Helper()
+
+	project, err := s.CreateProject(context.Background(), admin, name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return project
+}
+
+// Flag stores a flag with a state in every environment, enabled in the given ones
+func Flag(t testing.TB, s db.Storage, project *model.Project, key string, enabled ...model.Environment) *model.FeatureFlag {
+	t.Helper()
+
+	var states []*model.ToggleState
+	for _, env := range model.AllEnvironment {
+		states = append(states, &model.ToggleState{Environment: env, Enabled: slices.Contains(enabled, env)})
+	}
+
+	flag := &model.FeatureFlag{Key: key, Name: key, Project: &model.Project{ID: project.ID}}
+	if err := s.CreateFeatureFlag(context.Background(), flag, states); err != nil {
+		t.Fatal(err)
+	}
+	return flag
+}
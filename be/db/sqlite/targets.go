@@ -0,0 +1,146 @@
+package sqlite
+
+import (
+	"context"
+	"time"
+
+	"github.com/shubham-tomar/feature-toggler/db"
+	"github.com/shubham-tomar/feature-toggler/graphQl/model"
+)
+
+// Individual target operations
+func (s *SQLiteStorage) AddIndividualTargets(ctx context.Context, state *model.ToggleState, variant string, contextKeys []string) (int, error) {
+	now := time.Now()
+	return s.changeIndividualTargets(ctx, state,
+		`INSERT INTO individual_targets (flag_id, environment, context_key, variant, created_at) VALUES (?, ?, ?, ?, ?)
+		ON CONFLICT (flag_id, environment, context_key) DO UPDATE SET
+			variant = excluded.variant,
+			created_at = excluded.created_at
+		WHERE variant != excluded.variant`,
+		contextKeys, func(key string) []any {
+			return []any{state.FeatureFlag.ID, state.Environment, key, variant, now}
+		},
+	)
+}
+
+func (s *SQLiteStorage) RemoveIndividualTargets(ctx context.Context, state *model.ToggleState, contextKeys []string) (int, error) {
+	return s.changeIndividualTargets(ctx, state,
+		`DELETE FROM individual_targets WHERE flag_id = ? AND environment = ? AND context_key = ?`,
+		contextKeys, func(key string) []any {
+			return []any{state.FeatureFlag.ID, state.Environment, key}
+		},
+	)
+}
+
+// changeIndividualTargets runs a statement for every key in one transaction. When any row changed,
+// the state records who changed it and the flag queues an update event.
+func (s *SQLiteStorage) changeIndividualTargets(ctx context.Context, state *model.ToggleState, query string, contextKeys []string, args func(key string) []any) (int, error) {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	stmt, err := tx.PrepareContext(ctx, query)
+	if err != nil {
+		return 0, err
+	}
+	defer stmt.Close()
+
+	changed := 0
+	for _, key := range contextKeys {
+		res, err := stmt.ExecContext(ctx, args(key)...)
+		if err != nil {
+			return 0, err
+		}
+		n, err := res.RowsAffected()
+		if err != nil {
+			return 0, err
+		}
+		changed += int(n)
+	}
+
+	if changed == 0 {
+		return 0, nil
+	}
+
+	var updatedByID string
+	if state.UpdatedBy != nil {
+		updatedByID = state.UpdatedBy.ID
+	}
+	state.UpdatedAt = time.Now()
+
+	res, err := tx.ExecContext(ctx,
+		`UPDATE toggle_states SET updated_by_id = ?, updated_at = ? WHERE feature_flag_id = ? AND environment = ?`,
+		updatedByID, state.UpdatedAt, state.FeatureFlag.ID, state.Environment,
+	)
+	if err != nil {
+		return 0, err
+	}
+	if err := requireRow(res, "toggle state"); err != nil {
+		return 0, err
+	}
+
+	if err := recordFlagChange(ctx, tx, model.WebhookEventTypeFlagUpdated, state.FeatureFlag.ID); err != nil {
+		return 0, err
+	}
+
+	return changed, tx.Commit()
+}
+
+func (s *SQLiteStorage) GetIndividualTargets(ctx context.Context, flagID string, environment model.Environment, variant *string, page db.Page) ([]*model.IndividualTarget, int, error) {
+	where := `flag_id = ? AND environment = ?`
+	args := []any{flagID, environment}
+	if variant != nil {
+		where += ` AND variant = ?`
+		args = append(args, *variant)
+	}
+
+	var total int
+	if err := s.db.QueryRowContext(ctx, `SELECT COUNT(*) FROM individual_targets WHERE `+where, args...).Scan(&total); err != nil {
+		return nil, 0, err
+	}
+
+	rows, err := s.db.QueryContext(ctx,
+		`SELECT context_key, variant, created_at FROM individual_targets WHERE `+where+` ORDER BY context_key LIMIT ? OFFSET ?`,
+		append(args, sqlLimit(page), page.Offset)...,
+	)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	var targets []*model.IndividualTarget
+	for rows.Next() {
+		var t model.IndividualTarget
+		if err := rows.Scan(&t.ContextKey, &t.Variant, &t.CreatedAt); err != nil {
+			return nil, 0, err
+		}
+		targets = append(targets, &t)
+	}
+
+	return targets, total, rows.Err()
+}
+
+func (s *SQLiteStorage) GetContextTargets(ctx context.Context, projectID string, environment model.Environment, contextKey string) (map[string]string, error) {
+	rows, err := s.db.QueryContext(ctx,
+		`SELECT t.flag_id, t.variant FROM individual_targets t JOIN feature_flags f ON f.id = t.flag_id
+		WHERE t.context_key = ? AND t.environment = ? AND f.project_id = ?`,
+		contextKey, environment, projectID,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	variants := map[string]string{}
+	for rows.Next() {
+		var flagID, variant string
+		if err := rows.Scan(&flagID, &variant); err != nil {
+			return nil, err
+		}
+		variants[flagID] = variant
+	}
+
+	return variants, rows.Err()
+}
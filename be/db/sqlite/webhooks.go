@@ -0,0 +1,506 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/shubham-tomar/feature-toggler/db"
+	"github.com/shubham-tomar/feature-toggler/graphQl/model"
+)
+
+// Webhook operations
+func (s *SQLiteStorage) CreateWebhook(ctx context.Context, hook *model.Webhook, secret string) error {
+	if hook.ID == "" {
+		hook.ID = uuid.New().String()
+	}
+	hook.CreatedAt = time.Now()
+	hook.UpdatedAt = hook.CreatedAt
+
+	_, err := s.db.ExecContext(ctx,
+		`INSERT INTO webhooks (id, project_id, url, secret, event_types, active, created_by_id, created_at, updated_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		hook.ID, hook.Project.ID, hook.URL, secret, joinEventTypes(hook.EventTypes), hook.Active,
+		hook.CreatedBy.ID, hook.CreatedAt, hook.UpdatedAt,
+	)
+
+	return err
+}
+
+func (s *SQLiteStorage) GetWebhookByID(ctx context.Context, id string) (*model.Webhook, error) {
+	hook, err := scanWebhook(s.db.QueryRowContext(ctx,
+		`SELECT `+webhookColumns+` FROM webhooks WHERE id = ?`,
+		id,
+	))
+
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("webhook %w", db.ErrNotFound)
+	}
+
+	return hook, err
+}
+
+func (s *SQLiteStorage) GetProjectWebhooks(ctx context.Context, projectID string) ([]*model.Webhook, error) {
+	rows, err := s.db.QueryContext(ctx,
+		`SELECT `+webhookColumns+` FROM webhooks WHERE project_id = ? ORDER BY created_at`,
+		projectID,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var hooks []*model.Webhook
+	for rows.Next() {
+		hook, err := scanWebhook(rows)
+		if err != nil {
+			return nil, err
+		}
+		hooks = append(hooks, hook)
+	}
+
+	return hooks, rows.Err()
+}
+
+func (s *SQLiteStorage) UpdateWebhook(ctx context.Context, hook *model.Webhook, secret *string) error {
+	hook.UpdatedAt = time.Now()
+
+	res, err := s.db.ExecContext(ctx,
+		`UPDATE webhooks SET url = ?, event_types = ?, active = ?, secret = COALESCE(?, secret), updated_at = ? WHERE id = ?`,
+		hook.URL, joinEventTypes(hook.EventTypes), hook.Active, secret, hook.UpdatedAt, hook.ID,
+	)
+	if err != nil {
+		return err
+	}
+
+	return requireRow(res, "webhook")
+}
+
+// DeleteWebhook removes a webhook together with its delivery log
+func (s *SQLiteStorage) DeleteWebhook(ctx context.Context, id string) error {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, `DELETE FROM webhook_deliveries WHERE webhook_id = ?`, id); err != nil {
+		return err
+	}
+
+	res, err := tx.ExecContext(ctx, `DELETE FROM webhooks WHERE id = ?`, id)
+	if err != nil {
+		return err
+	}
+
+	if err := requireRow(res, "webhook"); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// GetWebhookDeliveries lists the deliveries of a webhook newest first, and returns how many match in total
+func (s *SQLiteStorage) GetWebhookDeliveries(ctx context.Context, webhookID string, status *model.WebhookDeliveryStatus, page db.Page) ([]*model.WebhookDelivery, int, error) {
+	where := `webhook_id = ?`
+	args := []any{webhookID}
+	if status != nil {
+		where += ` AND status = ?`
+		args = append(args, *status)
+	}
+
+	var total int
+	if err := s.db.QueryRowContext(ctx, `SELECT COUNT(*) FROM webhook_deliveries WHERE `+where, args...).Scan(&total); err != nil {
+		return nil, 0, err
+	}
+
+	rows, err := s.db.QueryContext(ctx,
+		`SELECT `+deliveryColumns+` FROM webhook_deliveries WHERE `+where+` ORDER BY created_at DESC, id DESC LIMIT ? OFFSET ?`,
+		append(args, sqlLimit(page), page.Offset)...,
+	)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	var deliveries []*model.WebhookDelivery
+	for rows.Next() {
+		d, err := scanDelivery(rows)
+		if err != nil {
+			return nil, 0, err
+		}
+		deliveries = append(deliveries, d)
+	}
+
+	return deliveries, total, rows.Err()
+}
+
+func (s *SQLiteStorage) GetWebhookDelivery(ctx context.Context, id string) (*model.WebhookDelivery, error) {
+	d, err := scanDelivery(s.db.QueryRowContext(ctx,
+		`SELECT `+deliveryColumns+` FROM webhook_deliveries WHERE id = ?`,
+		id,
+	))
+
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("webhook delivery %w", db.ErrNotFound)
+	}
+
+	return d, err
+}
+
+// RedeliverWebhookDelivery queues a fresh delivery of the same event, the original stays in the log
+func (s *SQLiteStorage) RedeliverWebhookDelivery(ctx context.Context, id string) (*model.WebhookDelivery, error) {
+	original, err := s.GetWebhookDelivery(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now().UTC()
+	d := &model.WebhookDelivery{
+		ID:            uuid.New().String(),
+		Webhook:       original.Webhook,
+		EventID:       original.EventID,
+		EventType:     original.EventType,
+		Payload:       original.Payload,
+		Status:        model.WebhookDeliveryStatusPending,
+		NextAttemptAt: &now,
+		CreatedAt:     now,
+		RedeliveryOf:  &original.ID,
+	}
+
+	if err := insertDelivery(ctx, s.db, d); err != nil {
+		return nil, err
+	}
+
+	return d, nil
+}
+
+// DispatchWebhookEvents moves events from the outbox to one delivery per matching active webhook.
+// An event and its deliveries change hands in one transaction, so no event is lost or sent twice.
+func (s *SQLiteStorage) DispatchWebhookEvents(ctx context.Context, limit int) (int, error) {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	rows, err := tx.QueryContext(ctx,
+		`SELECT id, project_id, event_type, payload FROM webhook_outbox ORDER BY created_at, id LIMIT ?`,
+		limit,
+	)
+	if err != nil {
+		return 0, err
+	}
+
+	type event struct {
+		id, projectID, payload string
+		eventType              model.WebhookEventType
+	}
+	var events []event
+	for rows.Next() {
+		var e event
+		if err := rows.Scan(&e.id, &e.projectID, &e.eventType, &e.payload); err != nil {
+			rows.Close()
+			return 0, err
+		}
+		events = append(events, e)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return 0, err
+	}
+
+	now := time.Now().UTC()
+	subscribers := map[string][]*model.Webhook{}
+	for _, e := range events {
+		hooks, ok := subscribers[e.projectID]
+		if !ok {
+			if hooks, err = activeWebhooks(ctx, tx, e.projectID); err != nil {
+				return 0, err
+			}
+			subscribers[e.projectID] = hooks
+		}
+
+		for _, hook := range hooks {
+			if !slices.Contains(hook.EventTypes, e.eventType) {
+				continue
+			}
+
+			err := insertDelivery(ctx, tx, &model.WebhookDelivery{
+				ID:            uuid.New().String(),
+				Webhook:       hook,
+				EventID:       e.id,
+				EventType:     e.eventType,
+				Payload:       e.payload,
+				Status:        model.WebhookDeliveryStatusPending,
+				NextAttemptAt: &now,
+				CreatedAt:     now,
+			})
+			if err != nil {
+				return 0, err
+			}
+		}
+
+		if _, err := tx.ExecContext(ctx, `DELETE FROM webhook_outbox WHERE id = ?`, e.id); err != nil {
+			return 0, err
+		}
+	}
+
+	return len(events), tx.Commit()
+}
+
+// ClaimWebhookDeliveries returns the pending deliveries that are due and pushes their next attempt
+// back by the lease, so that a worker that dies mid-delivery only delays them
+func (s *SQLiteStorage) ClaimWebhookDeliveries(ctx context.Context, now time.Time, lease time.Duration, limit int, skipWebhookIDs []string) ([]*db.PendingDelivery, error) {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	args := []any{model.WebhookDeliveryStatusPending, now.UTC()}
+	skip := ""
+	if len(skipWebhookIDs) > 0 {
+		skip = ` AND d.webhook_id NOT IN (` + placeholders(len(skipWebhookIDs)) + `)`
+		args = append(args, stringArgs(skipWebhookIDs)...)
+	}
+
+	rows, err := tx.QueryContext(ctx,
+		`SELECT `+prefixColumns("d", deliveryColumns)+`, w.url, w.secret
+		FROM webhook_deliveries d JOIN webhooks w ON w.id = d.webhook_id
+		WHERE d.status = ? AND d.next_attempt_at <= ? AND w.active`+skip+`
+		ORDER BY d.next_attempt_at LIMIT ?`,
+		append(args, limit)...,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	var claimed []*db.PendingDelivery
+	for rows.Next() {
+		var p db.PendingDelivery
+		d, err := scanDelivery(rows, &p.URL, &p.Secret)
+		if err != nil {
+			rows.Close()
+			return nil, err
+		}
+		p.Delivery = d
+		claimed = append(claimed, &p)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	leaseEnd := now.Add(lease).UTC()
+	for _, p := range claimed {
+		if _, err := tx.ExecContext(ctx,
+			`UPDATE webhook_deliveries SET next_attempt_at = ? WHERE id = ?`,
+			leaseEnd, p.Delivery.ID,
+		); err != nil {
+			return nil, err
+		}
+	}
+
+	return claimed, tx.Commit()
+}
+
+// CompleteWebhookDelivery saves the outcome of an attempt
+func (s *SQLiteStorage) CompleteWebhookDelivery(ctx context.Context, d *model.WebhookDelivery) error {
+	res, err := s.db.ExecContext(ctx,
+		`UPDATE webhook_deliveries SET status = ?, attempts = ?, next_attempt_at = ?, last_attempt_at = ?,
+			response_status = ?, last_error = ?, delivered_at = ?
+		WHERE id = ?`,
+		d.Status, d.Attempts, utcOrNil(d.NextAttemptAt), utcOrNil(d.LastAttemptAt),
+		d.ResponseStatus, d.LastError, utcOrNil(d.DeliveredAt), d.ID,
+	)
+	if err != nil {
+		return err
+	}
+
+	return requireRow(res, "webhook delivery")
+}
+
+// recordFlagEvent queues an event about a flag in the outbox, within the transaction of the change
+func recordFlagEvent(ctx context.Context, tx *sql.Tx, eventType model.WebhookEventType, flag *model.FeatureFlag, toggle *db.WebhookToggle) error {
+	payload := db.WebhookPayload{
+		ID:         uuid.New().String(),
+		Type:       eventType,
+		ProjectID:  flag.Project.ID,
+		OccurredAt: time.Now().UTC(),
+		Flag: db.WebhookFlag{
+			ID:     flag.ID,
+			Key:    flag.Key,
+			Name:   flag.Name,
+			Status: flag.Status,
+			Kind:   flag.Kind,
+			Tags:   flag.Tags,
+		},
+		Toggle: toggle,
+	}
+
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.ExecContext(ctx,
+		`INSERT INTO webhook_outbox (id, project_id, event_type, payload, created_at) VALUES (?, ?, ?, ?, ?)`,
+		payload.ID, payload.ProjectID, eventType, string(body), payload.OccurredAt,
+	)
+
+	return err
+}
+
+// recordFlagChange queues an event describing the flag as it is within the transaction
+func recordFlagChange(ctx context.Context, tx *sql.Tx, eventType model.WebhookEventType, id string) error {
+	flag, err := loadFlag(ctx, tx, id)
+	if err != nil {
+		return err
+	}
+
+	return recordFlagEvent(ctx, tx, eventType, flag, nil)
+}
+
+// loadFlag reads a flag within a transaction, without its states
+func loadFlag(ctx context.Context, tx *sql.Tx, id string) (*model.FeatureFlag, error) {
+	flag, err := scanFeatureFlag(tx.QueryRowContext(ctx,
+		`SELECT `+featureFlagColumns+` FROM feature_flags WHERE id = ?`,
+		id,
+	))
+
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("feature flag %w", db.ErrNotFound)
+	}
+
+	return flag, err
+}
+
+func activeWebhooks(ctx context.Context, tx *sql.Tx, projectID string) ([]*model.Webhook, error) {
+	rows, err := tx.QueryContext(ctx,
+		`SELECT `+webhookColumns+` FROM webhooks WHERE project_id = ? AND active`,
+		projectID,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var hooks []*model.Webhook
+	for rows.Next() {
+		hook, err := scanWebhook(rows)
+		if err != nil {
+			return nil, err
+		}
+		hooks = append(hooks, hook)
+	}
+
+	return hooks, rows.Err()
+}
+
+const webhookColumns = `id, project_id, url, event_types, active, created_by_id, created_at, updated_at`
+
+// scanWebhook reads a row of webhookColumns, related records only carry their id
+func scanWebhook(row scanner) (*model.Webhook, error) {
+	var hook model.Webhook
+	var projectID, createdByID, eventTypes string
+
+	if err := row.Scan(&hook.ID, &projectID, &hook.URL, &eventTypes, &hook.Active,
+		&createdByID, &hook.CreatedAt, &hook.UpdatedAt); err != nil {
+		return nil, err
+	}
+
+	hook.EventTypes = []model.WebhookEventType{}
+	if eventTypes != "" {
+		for _, t := range strings.Split(eventTypes, ",") {
+			hook.EventTypes = append(hook.EventTypes, model.WebhookEventType(t))
+		}
+	}
+
+	hook.Project = &model.Project{ID: projectID}
+	hook.CreatedBy = &model.User{ID: createdByID}
+
+	return &hook, nil
+}
+
+func joinEventTypes(types []model.WebhookEventType) string {
+	names := make([]string, len(types))
+	for i, t := range types {
+		names[i] = string(t)
+	}
+	return strings.Join(names, ",")
+}
+
+const deliveryColumns = `id, webhook_id, event_id, event_type, payload, status, attempts, next_attempt_at,
+	last_attempt_at, response_status, last_error, created_at, delivered_at, redelivery_of`
+
+// scanDelivery reads a row of deliveryColumns followed by any extra columns
+func scanDelivery(row scanner, extra ...any) (*model.WebhookDelivery, error) {
+	var d model.WebhookDelivery
+	var webhookID string
+	var nextAttemptAt, lastAttemptAt, deliveredAt sql.NullTime
+	var responseStatus sql.NullInt64
+	var lastError, redeliveryOf sql.NullString
+
+	dest := []any{&d.ID, &webhookID, &d.EventID, &d.EventType, &d.Payload, &d.Status, &d.Attempts, &nextAttemptAt,
+		&lastAttemptAt, &responseStatus, &lastError, &d.CreatedAt, &deliveredAt, &redeliveryOf}
+	if err := row.Scan(append(dest, extra...)...); err != nil {
+		return nil, err
+	}
+
+	d.Webhook = &model.Webhook{ID: webhookID}
+	d.NextAttemptAt = nullableTime(nextAttemptAt)
+	d.LastAttemptAt = nullableTime(lastAttemptAt)
+	d.DeliveredAt = nullableTime(deliveredAt)
+	d.ResponseStatus = nullableInt(responseStatus)
+	if lastError.Valid {
+		d.LastError = &lastError.String
+	}
+	if redeliveryOf.Valid {
+		d.RedeliveryOf = &redeliveryOf.String
+	}
+
+	return &d, nil
+}
+
+func insertDelivery(ctx context.Context, e execer, d *model.WebhookDelivery) error {
+	_, err := e.ExecContext(ctx,
+		`INSERT INTO webhook_deliveries (id, webhook_id, event_id, event_type, payload, status, attempts,
+			next_attempt_at, created_at, redelivery_of)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		d.ID, d.Webhook.ID, d.EventID, d.EventType, d.Payload, d.Status, d.Attempts,
+		utcOrNil(d.NextAttemptAt), d.CreatedAt.UTC(), d.RedeliveryOf,
+	)
+
+	return err
+}
+
+// prefixColumns qualifies a column list with a table alias
+func prefixColumns(alias, columns string) string {
+	names := strings.Split(columns, ",")
+	for i, name := range names {
+		names[i] = alias + "." + strings.TrimSpace(name)
+	}
+	return strings.Join(names, ", ")
+}
+
+func nullableTime(t sql.NullTime) *time.Time {
+	if !t.Valid {
+		return nil
+	}
+	return &t.Time
+}
+
+// Delivery times are compared in SQL, so they are always stored in UTC
+func utcOrNil(t *time.Time) *time.Time {
+	if t == nil {
+		return nil
+	}
+	utc := t.UTC()
+	return &utc
+}
@@ -2,9 +2,81 @@ package db
 
 import (
 	"context"
+	"errors"
+	"time"
+
 	"github.com/shubham-tomar/feature-toggler/graphQl/model"
 )
 
+// ErrNotFound is wrapped by storage implementations when a requested record does not exist
+var ErrNotFound = errors.New("not found")
+
+// ErrConflict is wrapped when a record would duplicate one that must be unique
+var ErrConflict = errors.New("already exists")
+
+// ErrNotApplicable is wrapped when a change set no longer fits the flags it changes, e.g. one of them was archived
+var ErrNotApplicable = errors.New("the change set cannot be applied")
+
+// ErrDiverged is wrapped when reverting a change set would undo changes made to its flags after it was applied
+var ErrDiverged = errors.New("changed after the change set was applied")
+
+// ErrLastAdmin is returned when a change would leave a project without an admin
+var ErrLastAdmin = errors.New("a project needs at least one admin")
+
+// DefaultStaleAfterDays is how long a flag may go without changes before it counts as stale
+const DefaultStaleAfterDays = 30
+
+// Page selects a window of a list, a zero Limit means no limit
+type Page struct {
+	Limit  int
+	Offset int
+}
+
+// FlagCounts summarises the flags of all projects
+type FlagCounts struct {
+	Active   int
+	Archived int
+	Enabled  map[model.Environment]int // Active flags enabled in each environment
+}
+
+// EvaluationCount is the number of times a flag served a variant within one time bucket
+type EvaluationCount struct {
+	FlagID          string
+	Environment     model.Environment
+	Variant         string
+	BucketStart     time.Time
+	Count           int
+	LastEvaluatedAt time.Time
+}
+
+// Impression is the latest variant a flag served to one evaluation context
+type Impression struct {
+	FlagID      string
+	Environment model.Environment
+	ContextKey  string
+	Variant     string
+	SeenAt      time.Time
+}
+
+// Exposure is the first time a context was enrolled in an experiment, and the variant it was assigned
+type Exposure struct {
+	ExperimentID string
+	ContextKey   string
+	Variant      string
+	ExposedAt    time.Time
+}
+
+// TrackEvent counts how often, and between which times, a context sent a goal event
+type TrackEvent struct {
+	ProjectID   string
+	Environment model.Environment
+	EventKey    string
+	ContextKey  string
+	Count       int
+	FirstSeenAt time.Time
+	LastSeenAt  time.Time
+}
+
 // Storage defines the interface for database operations
 type Storage interface {
 	// Connection management
@@ -17,33 +89,152 @@ type Storage interface {
 	GetUserByID(ctx context.Context, id string) (*model.User, error)
 	GetUserByEmail(ctx context.Context, email string) (*model.User, error)
 	UpdateUser(ctx context.Context, user *model.User) error
+	GetServiceAccounts(ctx context.Context) ([]*model.User, error)
 	// DeleteUser(ctx context.Context, id string) error
 	
-	// Project operations
+	// Project operations, projects are returned without their members
 	CreateProject(ctx context.Context, user *model.User, name string) (*model.Project, error)
 	GetProjectByID(ctx context.Context, id string) (*model.Project, error)
 	GetUserProjects(ctx context.Context, user *model.User) ([]*model.Project, error)
-	GetProjects(ctx context.Context) ([]*model.Project, error)
-	UpdateProject(ctx context.Context, project *model.Project) error
+	GetProjects(ctx context.Context, page Page) ([]*model.Project, int, error) // Also returns the total number of projects
+	UpdateProject(ctx context.Context, project *model.Project) error // Leaves the protected environments as they are
+	UpdateProjectProtection(ctx context.Context, projectID string, change *model.ProtectionChange) error // Sets the protected environments and records the change
+	GetProtectionChanges(ctx context.Context, projectID string) ([]*model.ProtectionChange, error) // Newest first, the users only carry their id
 	DeleteProject(ctx context.Context, id string) error
 	
-	// Project membership operations
+	// Project membership operations, changes that would leave a project without an admin fail with ErrLastAdmin
 	AddProjectMember(ctx context.Context, membership *model.ProjectUser) error
 	UpdateProjectMemberRole(ctx context.Context, membershipID string, role model.Role) error
 	RemoveProjectMember(ctx context.Context, membershipID string) error
+	GetProjectMember(ctx context.Context, membershipID string) (*model.ProjectUser, error)
 	GetProjectMembers(ctx context.Context, projectID string) ([]*model.ProjectUser, error)
+	GetUserMemberships(ctx context.Context, userID string) ([]*model.ProjectUser, error)
+
+	// Project invitation operations
+	CreateInvitation(ctx context.Context, invitation *model.ProjectInvitation) error
+	GetInvitationByID(ctx context.Context, id string) (*model.ProjectInvitation, error)
+	GetProjectInvitations(ctx context.Context, projectID string) ([]*model.ProjectInvitation, error)
+	GetInvitationsByEmail(ctx context.Context, email string) ([]*model.ProjectInvitation, error)
+	AcceptInvitation(ctx context.Context, invitationID string, user *model.User) (*model.ProjectUser, error)
+	UpdateInvitationStatus(ctx context.Context, invitationID string, status model.InvitationStatus) error
 	
-	// Feature flag operations
-	CreateFeatureFlag(ctx context.Context, flag *model.FeatureFlag, initialStates []*model.ToggleState) error
+	// Feature flag operations, the project and creator of a flag only carry their id
+	CreateFeatureFlag(ctx context.Context, flag *model.FeatureFlag, initialStates []*model.ToggleState) error // Fails with ErrConflict if the key is taken in the project
 	GetFeatureFlagByID(ctx context.Context, id string) (*model.FeatureFlag, error)
-	GetFeatureFlagByKey(ctx context.Context, key string) (*model.FeatureFlag, error)
-	GetProjectFeatureFlags(ctx context.Context, projectID string) ([]*model.FeatureFlag, error)
+	GetFeatureFlagByKey(ctx context.Context, projectID, key string) (*model.FeatureFlag, error)
+	GetProjectFeatureFlags(ctx context.Context, projectID string) ([]*model.FeatureFlag, error) // Includes archived flags
+	QueryFeatureFlags(ctx context.Context, projectID string, filter model.FeatureFlagFilter, order model.FeatureFlagOrder, page Page) ([]*model.FeatureFlag, int, error)
 	UpdateFeatureFlag(ctx context.Context, flag *model.FeatureFlag) error
-	DeleteFeatureFlag(ctx context.Context, id string) error
+	ArchiveFeatureFlag(ctx context.Context, id string) error
+	RestoreFeatureFlag(ctx context.Context, id string) error
+	DeleteFeatureFlag(ctx context.Context, id string) error // Also deletes the toggle states, individual targets, evaluation counts and experiments of the flag
+	CountFeatureFlags(ctx context.Context) (*FlagCounts, error)
 	
-	// Toggle state operations
+	// Toggle state operations, the flag and updater of a state only carry their id
 	GetFeatureFlagStates(ctx context.Context, flagID string) ([]*model.ToggleState, error)
 	UpdateFeatureFlagState(ctx context.Context, state *model.ToggleState) error
+
+	// Individual target operations, changes that affect any key update the state of the flag in the environment
+	AddIndividualTargets(ctx context.Context, state *model.ToggleState, variant string, contextKeys []string) (int, error) // Returns how many keys were added or moved to the variant
+	RemoveIndividualTargets(ctx context.Context, state *model.ToggleState, contextKeys []string) (int, error)
+	GetIndividualTargets(ctx context.Context, flagID string, environment model.Environment, variant *string, page Page) ([]*model.IndividualTarget, int, error)
+	GetContextTargets(ctx context.Context, projectID string, environment model.Environment, contextKey string) (map[string]string, error) // Variants by flag id
+
+	// Promotion operations, the project, flag and users of a promotion only carry their id
+	CreatePromotion(ctx context.Context, promotion *model.Promotion) error // Records a promotion waiting for approval
+	GetPromotionByID(ctx context.Context, id string) (*model.Promotion, error)
+	GetProjectPromotions(ctx context.Context, projectID string, status *model.PromotionStatus) ([]*model.Promotion, error)
+	ApplyPromotion(ctx context.Context, promotion *model.Promotion) error // Copies the state of every changed flag in one transaction and records the promotion, fails with ErrConflict if it is no longer pending
+	RejectPromotion(ctx context.Context, promotion *model.Promotion) error // Fails with ErrConflict unless the promotion is pending
+
+	// Change set operations, the project, flags and users of a change set only carry their id.
+	// Status changes fail with ErrConflict unless the stored status is one of the expected ones.
+	CreateChangeSet(ctx context.Context, changeSet *model.ChangeSet) error
+	GetChangeSetByID(ctx context.Context, id string) (*model.ChangeSet, error)
+	GetProjectChangeSets(ctx context.Context, projectID string, status *model.ChangeSetStatus) ([]*model.ChangeSet, error)
+	GetDueChangeSets(ctx context.Context, now time.Time) ([]*model.ChangeSet, error) // Scheduled change sets whose time has come
+	UpdateChangeSetStatus(ctx context.Context, changeSet *model.ChangeSet, expected []model.ChangeSetStatus) error // Saves the status, schedule, error, submitter and decider
+	ApplyChangeSet(ctx context.Context, changeSet *model.ChangeSet, expected []model.ChangeSetStatus) error // Applies every change in one transaction and keeps the previous states, fails with ErrNotApplicable if a change no longer fits its flag
+	RevertChangeSet(ctx context.Context, changeSet *model.ChangeSet) error // Restores the previous states in one transaction, the change set must be applied
+	DeleteChangeSet(ctx context.Context, id string) error // Only drafts, rejected and failed change sets
+
+	// Evaluation analytics, counts are added to the ones already stored for the same bucket
+	RecordEvaluations(ctx context.Context, counts []*EvaluationCount, impressions []*Impression) error
+	GetEvaluationCounts(ctx context.Context, flagID string, environment *model.Environment, since time.Time) ([]*EvaluationCount, error)
+	GetLastEvaluations(ctx context.Context, flagIDs []string) (map[string]time.Time, error)
+	CountImpressions(ctx context.Context, flagID string, environment *model.Environment, since time.Time) (int, error)
+	DeleteEvaluationsBefore(ctx context.Context, before time.Time) error
+
+	// Webhook operations, flag writes queue their events in an outbox within the same transaction
+	CreateWebhook(ctx context.Context, hook *model.Webhook, secret string) error
+	GetWebhookByID(ctx context.Context, id string) (*model.Webhook, error)
+	GetProjectWebhooks(ctx context.Context, projectID string) ([]*model.Webhook, error)
+	UpdateWebhook(ctx context.Context, hook *model.Webhook, secret *string) error // A nil secret keeps the current one
+	DeleteWebhook(ctx context.Context, id string) error
+	GetWebhookDeliveries(ctx context.Context, webhookID string, status *model.WebhookDeliveryStatus, page Page) ([]*model.WebhookDelivery, int, error)
+	GetWebhookDelivery(ctx context.Context, id string) (*model.WebhookDelivery, error)
+	RedeliverWebhookDelivery(ctx context.Context, id string) (*model.WebhookDelivery, error)
+
+	// Webhook delivery worker
+	DispatchWebhookEvents(ctx context.Context, limit int) (int, error) // Turns outbox events into deliveries of the matching webhooks
+	ClaimWebhookDeliveries(ctx context.Context, now time.Time, lease time.Duration, limit int, skipWebhookIDs []string) ([]*PendingDelivery, error) // Leaves the deliveries to the skipped webhooks alone
+	CompleteWebhookDelivery(ctx context.Context, delivery *model.WebhookDelivery) error
+
+	// Experiment operations, the flag and creator of an experiment only carry their id
+	CreateExperiment(ctx context.Context, experiment *model.Experiment) error
+	GetExperimentByID(ctx context.Context, id string) (*model.Experiment, error)
+	GetProjectExperiments(ctx context.Context, projectID string, status *model.ExperimentStatus) ([]*model.Experiment, error)
+	GetRunningExperiments(ctx context.Context, projectID string, environment model.Environment) ([]*model.Experiment, error)
+	UpdateExperiment(ctx context.Context, experiment *model.Experiment) error
+	StartExperiment(ctx context.Context, id string, layerOffset *int) error // Fails with ErrConflict unless the experiment is a draft, no other one runs on its flag and environment, and its layer range is free
+	StopExperiment(ctx context.Context, id string) error  // Fails with ErrConflict unless the experiment is running
+	DeleteExperiment(ctx context.Context, id string) error
+
+	// Experiment layers and holdouts
+	CreateExperimentLayer(ctx context.Context, layer *model.ExperimentLayer) error
+	GetExperimentLayerByID(ctx context.Context, id string) (*model.ExperimentLayer, error)
+	GetProjectExperimentLayers(ctx context.Context, projectID string) ([]*model.ExperimentLayer, error)
+	GetLayerExperiments(ctx context.Context, layerID string) ([]*model.Experiment, error)
+	UpdateExperimentLayer(ctx context.Context, layer *model.ExperimentLayer) error
+	DeleteExperimentLayer(ctx context.Context, id string) error // Experiments leave the layer, fails with ErrConflict while one of them runs
+	CreateHoldout(ctx context.Context, holdout *model.Holdout) error
+	GetHoldoutByID(ctx context.Context, id string) (*model.Holdout, error)
+	GetProjectHoldouts(ctx context.Context, projectID string) ([]*model.Holdout, error)
+	UpdateHoldout(ctx context.Context, holdout *model.Holdout) error
+	DeleteHoldout(ctx context.Context, id string) error
+
+	// Experiment data, exposures keep the first time a context was seen and track events are added up
+	RecordExposures(ctx context.Context, exposures []*Exposure) error
+	RecordTrackEvents(ctx context.Context, events []*TrackEvent) error
+	CountExposures(ctx context.Context, experimentID string) (map[string]int, error)                             // By variant
+	CountConversions(ctx context.Context, experiment *model.Experiment, eventKey string) (map[string]int, error) // By variant, only events after the exposure count
+
+	// Batched lookups for dataloaders, records that do not exist are left out of the result
+	GetUsersByIDs(ctx context.Context, ids []string) ([]*model.User, error)
+	GetProjectsByIDs(ctx context.Context, ids []string) ([]*model.Project, error)
+	GetMembersByProjectIDs(ctx context.Context, projectIDs []string) ([]*model.ProjectUser, error)
+	GetStatesByFeatureFlagIDs(ctx context.Context, flagIDs []string) ([]*model.ToggleState, error)
+
+	// SDK key operations, keys are looked up by the SHA-256 hash of their secret
+	CreateSdkKey(ctx context.Context, key *model.SdkKey, keyHash string) error
+	GetSdkKeyByID(ctx context.Context, id string) (*model.SdkKey, error)
+	GetSdkKeyByHash(ctx context.Context, keyHash string) (*model.SdkKey, error)
+	GetProjectSdkKeys(ctx context.Context, projectID string, environment *model.Environment) ([]*model.SdkKey, error)
+	RotateSdkKey(ctx context.Context, oldID string, oldExpiresAt time.Time, key *model.SdkKey, keyHash string) error
+	RevokeSdkKey(ctx context.Context, id string) error
+
+	// Access token operations, tokens are looked up by the SHA-256 hash of their secret
+	CreateAccessToken(ctx context.Context, token *model.AccessToken, tokenHash string) error
+	GetAccessTokenByID(ctx context.Context, id string) (*model.AccessToken, error)
+	GetAccessTokenByHash(ctx context.Context, tokenHash string) (*model.AccessToken, error)
+	GetUserAccessTokens(ctx context.Context, userID string) ([]*model.AccessToken, error)
+	TouchAccessToken(ctx context.Context, id string, usedAt time.Time) error
+	RevokeAccessToken(ctx context.Context, id string) error
+
+	// Login session operations, sessions are looked up by the SHA-256 hash of their cookie
+	CreateSession(ctx context.Context, userID string, tokenHash string, expiresAt time.Time) error
+	GetSessionUser(ctx context.Context, tokenHash string) (*model.User, error)
+	DeleteSession(ctx context.Context, tokenHash string) error
 }
 
 // StorageFactory creates new storage instances
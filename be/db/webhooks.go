@@ -0,0 +1,41 @@
+package db
+
+import (
+	"time"
+
+	"github.com/shubham-tomar/feature-toggler/graphQl/model"
+)
+
+// WebhookPayload is the JSON body posted to webhooks for every event
+type WebhookPayload struct {
+	ID         string                 `json:"id"`
+	Type       model.WebhookEventType `json:"type"`
+	ProjectID  string                 `json:"project_id"`
+	OccurredAt time.Time              `json:"occurred_at"`
+	Flag       WebhookFlag            `json:"flag"`
+	Toggle     *WebhookToggle         `json:"toggle,omitempty"` // Only set for FLAG_TOGGLED
+}
+
+// WebhookFlag describes the flag an event is about, as it was after the change
+type WebhookFlag struct {
+	ID     string           `json:"id"`
+	Key    string           `json:"key"`
+	Name   string           `json:"name"`
+	Status model.FlagStatus `json:"status"`
+	Kind   model.FlagKind   `json:"kind"`
+	Tags   []string         `json:"tags"`
+}
+
+type WebhookToggle struct {
+	Environment     model.Environment `json:"environment"`
+	Enabled         bool              `json:"enabled"`
+	PreviousEnabled bool              `json:"previous_enabled"`
+	UpdatedByID     string            `json:"updated_by_id,omitempty"`
+}
+
+// PendingDelivery is a delivery claimed by the worker, with what it needs to send it
+type PendingDelivery struct {
+	Delivery *model.WebhookDelivery
+	URL      string
+	Secret   string
+}
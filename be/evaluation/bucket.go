@@ -0,0 +1,82 @@
+package evaluation
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+
+	"github.com/shubham-tomar/feature-toggler/graphQl/model"
+)
+
+// Buckets is the resolution of traffic splits, a percentage covers Buckets/100 of them
+const Buckets = 10000
+
+// Bucket maps a context key to a number in [0, Buckets). The salt gives every split its own
+// independent mapping, and the same inputs land in the same bucket on every server.
+func Bucket(salt, key string) int {
+	sum := sha256.Sum256([]byte(salt + "." + key))
+	return int(binary.BigEndian.Uint64(sum[:8]) % Buckets)
+}
+
+// Assign returns the variant an experiment serves to a context key, or false if the key is not
+// enrolled. Enrollment and variant use separate buckets, so raising the traffic percentage of a
+// draft only adds contexts without moving the ones already enrolled between variants.
+func Assign(experiment *model.Experiment, key string) (string, bool) {
+	return assign(experiment, key, nil)
+}
+
+func assign(experiment *model.Experiment, key string, t *trace) (string, bool) {
+	if !enrolled(experiment, key, t) {
+		return "", false
+	}
+
+	bucket := Bucket(experiment.ID+".variant", key)
+	upper := 0
+	for _, allocation := range experiment.Allocations {
+		upper += allocation.Weight * Buckets / 100
+		if bucket < upper {
+			t.add(&model.EvaluationStep{Kind: model.EvaluationStepKindVariant, Matched: true, Bucket: &bucket, Experiment: experiment},
+				"bucket %d is below %d, the upper bound of variant %s (weight %d)", bucket, upper, allocation.Variant, allocation.Weight)
+			return allocation.Variant, true
+		}
+	}
+
+	// Weights add up to 100, this is only reached if they do not
+	t.add(&model.EvaluationStep{Kind: model.EvaluationStepKindVariant, Bucket: &bucket, Experiment: experiment},
+		"bucket %d is above every variant, the allocation weights do not add up to 100", bucket)
+	return "", false
+}
+
+// enrolled reports whether a context key falls in the traffic of an experiment. Experiments in a
+// layer share its buckets and each one holds its own range, so a key is in at most one of them.
+func enrolled(experiment *model.Experiment, key string, t *trace) bool {
+	step := &model.EvaluationStep{Kind: model.EvaluationStepKindExperiment, Experiment: experiment}
+
+	if experiment.Layer == nil || experiment.LayerOffset == nil {
+		bucket := Bucket(experiment.ID, key)
+		step.Bucket, step.Matched = &bucket, bucket < experiment.TrafficPercentage*Buckets/100
+		t.add(step, "bucket %d of experiment %q, which enrolls buckets below %d (%d%% of traffic)",
+			bucket, experiment.Name, experiment.TrafficPercentage*Buckets/100, experiment.TrafficPercentage)
+		return step.Matched
+	}
+
+	bucket := Bucket(experiment.Layer.ID, key)
+	start := *experiment.LayerOffset * Buckets / 100
+	end := start + experiment.TrafficPercentage*Buckets/100
+	step.Bucket, step.Matched = &bucket, bucket >= start && bucket < end
+	t.add(step, "bucket %d of the layer of experiment %q, which enrolls buckets %d to %d",
+		bucket, experiment.Name, start, end-1)
+	return step.Matched
+}
+
+// HeldOut reports whether a context key belongs to a holdout
+func HeldOut(holdout *model.Holdout, key string) bool {
+	return heldOut(holdout, key, nil)
+}
+
+func heldOut(holdout *model.Holdout, key string, t *trace) bool {
+	bucket := Bucket(holdout.ID, key)
+	step := &model.EvaluationStep{Kind: model.EvaluationStepKindHoldout, Matched: bucket < holdout.Percentage*Buckets/100, Bucket: &bucket, Holdout: holdout}
+	t.add(step, "bucket %d of holdout %q, which holds buckets below %d (%d%% of contexts)",
+		bucket, holdout.Name, holdout.Percentage*Buckets/100, holdout.Percentage)
+	return step.Matched
+}
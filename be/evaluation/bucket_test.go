@@ -0,0 +1,93 @@
+package evaluation
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/shubham-tomar/feature-toggler/graphQl/model"
+)
+
+func TestBucket(t *testing.T) {
+	// Buckets must never change between releases or servers, or contexts would move between variants
+	tests := []struct {
+		salt, key string
+		want      int
+	}{
+		{"salt", "user-1", 2125},
+		{"salt", "user-2", 1132},
+		{"exp-1", "user-1", 9179},
+		{"exp-1.variant", "user-1", 5995},
+	}
+
+	for _, tt := range tests {
+		if got := Bucket(tt.salt, tt.key); got != tt.want {
+			t.Errorf("Bucket(%q, %q) = %d, want %d", tt.salt, tt.key, got, tt.want)
+		}
+	}
+}
+
+func TestBucketDistribution(t *testing.T) {
+	const keys = 20000
+	below, same := 0, 0
+	for i := range keys {
+		key := fmt.Sprintf("user-%d", i)
+		bucket := Bucket("salt", key)
+		if bucket < 0 || bucket >= Buckets {
+			t.Fatalf("Bucket(%q) = %d, outside [0, %d)", key, bucket, Buckets)
+		}
+		if bucket < Buckets/4 {
+			below++
+		}
+		if bucket == Bucket("other", key) {
+			same++
+		}
+	}
+
+	if below < keys/4-500 || below > keys/4+500 {
+		t.Errorf("%d of %d keys fell in the first quarter of the buckets", below, keys)
+	}
+	if same > 10 {
+		t.Errorf("%d of %d keys share their bucket across salts", same, keys)
+	}
+}
+
+func TestAssign(t *testing.T) {
+	experiment := &model.Experiment{
+		ID:                "exp-1",
+		TrafficPercentage: 50,
+		Allocations: []*model.VariantAllocation{
+			{Variant: "control", Weight: 50},
+			{Variant: "treatment", Weight: 50},
+		},
+	}
+
+	assigned := map[string]string{}
+	counts := map[string]int{}
+	for i := range 10000 {
+		key := fmt.Sprintf("user-%d", i)
+		variant, ok := Assign(experiment, key)
+		if !ok {
+			continue
+		}
+		if again, _ := Assign(experiment, key); again != variant {
+			t.Fatalf("%s was assigned %s, then %s", key, variant, again)
+		}
+		assigned[key] = variant
+		counts[variant]++
+	}
+
+	if n := len(assigned); n < 4700 || n > 5300 {
+		t.Errorf("%d of 10000 keys enrolled at 50%% traffic", n)
+	}
+	if counts["control"] < 2300 || counts["treatment"] < 2300 {
+		t.Errorf("variants are unbalanced: %v", counts)
+	}
+
+	// Raising the traffic adds contexts without moving the enrolled ones
+	experiment.TrafficPercentage = 100
+	for key, variant := range assigned {
+		if got, ok := Assign(experiment, key); !ok || got != variant {
+			t.Fatalf("%s moved from %s to %s, %v when traffic was raised", key, variant, got, ok)
+		}
+	}
+}
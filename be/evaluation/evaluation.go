@@ -0,0 +1,324 @@
+package evaluation
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/shubham-tomar/feature-toggler/db"
+	"github.com/shubham-tomar/feature-toggler/graphQl/model"
+)
+
+// Reason explains why a value was served, using the OpenFeature reason vocabulary
+type Reason string
+
+const (
+	ReasonStatic         Reason = "STATIC"
+	ReasonDefault        Reason = "DEFAULT"
+	ReasonTargetingMatch Reason = "TARGETING_MATCH"
+	ReasonSplit          Reason = "SPLIT"
+	ReasonDisabled       Reason = "DISABLED"
+	ReasonError          Reason = "ERROR"
+)
+
+// Boolean flags expose their two states as variants so that clients can report them
+const (
+	VariantOn  = "on"
+	VariantOff = "off"
+)
+
+// ErrFlagNotFound is returned when no flag with the requested key exists in the project
+var ErrFlagNotFound = errors.New("flag not found")
+
+// Context is the evaluation context sent by a client
+type Context map[string]any
+
+// TargetingKey returns the identifier of the subject the flag is evaluated for
+func (c Context) TargetingKey() (string, bool) {
+	key, ok := c["targetingKey"].(string)
+	return key, ok && key != ""
+}
+
+// Scope selects the flags a client may evaluate
+type Scope struct {
+	ProjectID   string
+	Environment model.Environment
+	// ClientSideOnly hides flags that are not marked as available to client-side SDKs
+	ClientSideOnly bool
+}
+
+// ScopeForKey returns the scope an SDK key grants access to
+func ScopeForKey(key *model.SdkKey) Scope {
+	return Scope{
+		ProjectID:      key.Project.ID,
+		Environment:    key.Environment,
+		ClientSideOnly: key.Kind == model.SdkKeyKindClient,
+	}
+}
+
+func (s Scope) visible(flag *model.FeatureFlag) bool {
+	if flag.Project == nil || flag.Project.ID != s.ProjectID {
+		return false
+	}
+	// Archived flags behave as if they did not exist, so clients fall back to their defaults
+	if flag.Status == model.FlagStatusArchived {
+		return false
+	}
+	return flag.ClientSide || !s.ClientSideOnly
+}
+
+// Result is the outcome of evaluating a single flag
+type Result struct {
+	FlagID   string
+	FlagKey  string
+	Value    bool
+	Variant  string
+	Reason   Reason
+	Metadata map[string]any
+	// ExperimentID is set when the context is enrolled in a running experiment
+	ExperimentID string
+}
+
+// Evaluator resolves flags of a project in a given environment
+type Evaluator struct {
+	Storage db.Storage
+}
+
+// Evaluate resolves a single flag by key
+func (e *Evaluator) Evaluate(ctx context.Context, scope Scope, key string, evalCtx Context) (*Result, error) {
+	flag, err := e.Lookup(ctx, scope, key)
+	if err != nil {
+		return nil, err
+	}
+
+	targets, err := e.Targets(ctx, scope, evalCtx)
+	if err != nil {
+		return nil, err
+	}
+
+	experiments, err := e.Experiments(ctx, scope)
+	if err != nil {
+		return nil, err
+	}
+
+	return Evaluate(flag, scope.Environment, evalCtx, targets, experiments), nil
+}
+
+// Lookup returns a flag by key, or ErrFlagNotFound if it is not visible in the scope
+func (e *Evaluator) Lookup(ctx context.Context, scope Scope, key string) (*model.FeatureFlag, error) {
+	flag, err := e.Storage.GetFeatureFlagByKey(ctx, scope.ProjectID, key)
+	if errors.Is(err, db.ErrNotFound) {
+		return nil, ErrFlagNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error getting feature flag: %w", err)
+	}
+
+	if !scope.visible(flag) {
+		return nil, ErrFlagNotFound
+	}
+
+	return flag, nil
+}
+
+// Targets are the variants served to individually targeted context keys, by flag id and context key
+type Targets map[string]map[string]string
+
+func (t Targets) variant(flagID, key string) (string, bool) {
+	variant, ok := t[flagID][key]
+	return variant, ok
+}
+
+// Overrides are the variants forced by an override token, by flag key
+type Overrides map[string]string
+
+// Apply serves the forced variant of the flag, if any
+func (o Overrides) Apply(result *Result) {
+	variant, ok := o[result.FlagKey]
+	if !ok {
+		return
+	}
+
+	result.Value = variant == VariantOn
+	result.Variant = variant
+	result.Reason = ReasonTargetingMatch
+	result.Metadata = map[string]any{"override": true}
+	result.ExperimentID = ""
+}
+
+// Targets loads the individual targets of the context's targeting key in a scope
+func (e *Evaluator) Targets(ctx context.Context, scope Scope, evalCtx Context) (Targets, error) {
+	key, ok := evalCtx.TargetingKey()
+	if !ok {
+		return nil, nil
+	}
+
+	variants, err := e.Storage.GetContextTargets(ctx, scope.ProjectID, scope.Environment, key)
+	if err != nil {
+		return nil, fmt.Errorf("error getting individual targets: %w", err)
+	}
+
+	targets := make(Targets, len(variants))
+	for flagID, variant := range variants {
+		targets[flagID] = map[string]string{key: variant}
+	}
+	return targets, nil
+}
+
+// Experiments are the experiments running in a scope, by flag id, and the holdouts of its project
+type Experiments struct {
+	ByFlag   map[string]*model.Experiment
+	Holdouts []*model.Holdout
+}
+
+// Assign returns the experiment running on a flag and the variant it serves to a context key,
+// or false if there is none, the key is in a holdout or it is not enrolled
+func (x *Experiments) Assign(flagID, key string) (*model.Experiment, string, bool) {
+	return x.assign(flagID, key, nil)
+}
+
+func (x *Experiments) assign(flagID, key string, t *trace) (*model.Experiment, string, bool) {
+	if x == nil || x.ByFlag[flagID] == nil {
+		return nil, "", false
+	}
+
+	for _, holdout := range x.Holdouts {
+		if heldOut(holdout, key, t) {
+			return nil, "", false
+		}
+	}
+
+	experiment := x.ByFlag[flagID]
+	variant, ok := assign(experiment, key, t)
+	if !ok {
+		return nil, "", false
+	}
+	return experiment, variant, true
+}
+
+// Experiments loads the running experiments and holdouts of a scope
+func (e *Evaluator) Experiments(ctx context.Context, scope Scope) (*Experiments, error) {
+	running, err := e.Storage.GetRunningExperiments(ctx, scope.ProjectID, scope.Environment)
+	if err != nil {
+		return nil, fmt.Errorf("error getting experiments: %w", err)
+	}
+
+	x := &Experiments{ByFlag: make(map[string]*model.Experiment, len(running))}
+	for _, experiment := range running {
+		x.ByFlag[experiment.FeatureFlag.ID] = experiment
+	}
+
+	// Holdouts only matter while something runs
+	if len(running) > 0 {
+		if x.Holdouts, err = e.Storage.GetProjectHoldouts(ctx, scope.ProjectID); err != nil {
+			return nil, fmt.Errorf("error getting holdouts: %w", err)
+		}
+	}
+
+	return x, nil
+}
+
+// EvaluateAll resolves every flag visible in the scope
+func (e *Evaluator) EvaluateAll(ctx context.Context, scope Scope, evalCtx Context) ([]*Result, error) {
+	flags, err := e.Storage.GetProjectFeatureFlags(ctx, scope.ProjectID)
+	if err != nil {
+		return nil, fmt.Errorf("error getting feature flags: %w", err)
+	}
+
+	targets, err := e.Targets(ctx, scope, evalCtx)
+	if err != nil {
+		return nil, err
+	}
+
+	experiments, err := e.Experiments(ctx, scope)
+	if err != nil {
+		return nil, err
+	}
+
+	results := make([]*Result, 0, len(flags))
+	for _, flag := range flags {
+		if !scope.visible(flag) {
+			continue
+		}
+
+		flag.States, err = e.Storage.GetFeatureFlagStates(ctx, flag.ID)
+		if err != nil {
+			return nil, fmt.Errorf("error getting toggle states: %w", err)
+		}
+
+		results = append(results, Evaluate(flag, scope.Environment, evalCtx, targets, experiments))
+	}
+
+	return results, nil
+}
+
+// Evaluate resolves a flag whose states are already loaded. Individually targeted context keys get their
+// variant first, even while the flag is off. The experiments, which may be nil, are the ones running in
+// the environment; the one on the flag splits enrolled contexts while the flag is enabled.
+func Evaluate(flag *model.FeatureFlag, env model.Environment, evalCtx Context, targets Targets, experiments *Experiments) *Result {
+	return evaluate(flag, env, evalCtx, targets, experiments, nil)
+}
+
+func evaluate(flag *model.FeatureFlag, env model.Environment, evalCtx Context, targets Targets, experiments *Experiments, t *trace) *Result {
+	result := &Result{
+		FlagID:  flag.ID,
+		FlagKey: flag.Key,
+		Value:   false,
+		Variant: VariantOff,
+		Reason:  ReasonDefault,
+	}
+
+	state := stateFor(flag, env)
+	if state == nil {
+		// Flags without a state in this environment were never configured there
+		t.add(&model.EvaluationStep{Kind: model.EvaluationStepKindOff, Matched: true}, "the flag is not configured in %s", env)
+		return result
+	}
+
+	if key, ok := evalCtx.TargetingKey(); ok {
+		if variant, targeted := targets.variant(flag.ID, key); targeted {
+			t.add(&model.EvaluationStep{Kind: model.EvaluationStepKindTarget, Matched: true}, "context key %q is individually targeted with variant %s", key, variant)
+			result.Value = variant == VariantOn
+			result.Variant = variant
+			result.Reason = ReasonTargetingMatch
+			return result
+		}
+		t.add(&model.EvaluationStep{Kind: model.EvaluationStepKindTarget}, "context key %q is not individually targeted", key)
+	}
+
+	if !state.Enabled {
+		t.add(&model.EvaluationStep{Kind: model.EvaluationStepKindOff, Matched: true}, "the flag is off in %s", env)
+		result.Reason = ReasonDisabled
+		return result
+	}
+	t.add(&model.EvaluationStep{Kind: model.EvaluationStepKindOff}, "the flag is on in %s", env)
+
+	if key, ok := evalCtx.TargetingKey(); !ok && experiments != nil && experiments.ByFlag[flag.ID] != nil {
+		t.add(&model.EvaluationStep{Kind: model.EvaluationStepKindExperiment, Experiment: experiments.ByFlag[flag.ID]},
+			"the context has no targetingKey, experiment %q only enrolls contexts with one", experiments.ByFlag[flag.ID].Name)
+	} else if ok {
+		if experiment, variant, enrolled := experiments.assign(flag.ID, key, t); enrolled {
+			result.Value = variant == VariantOn
+			result.Variant = variant
+			result.Reason = ReasonSplit
+			result.Metadata = map[string]any{"experimentId": experiment.ID}
+			result.ExperimentID = experiment.ID
+			return result
+		}
+	}
+
+	t.add(&model.EvaluationStep{Kind: model.EvaluationStepKindFallthrough, Matched: true}, "the flag serves %s", VariantOn)
+	result.Value = true
+	result.Variant = VariantOn
+	result.Reason = ReasonStatic
+	return result
+}
+
+func stateFor(flag *model.FeatureFlag, env model.Environment) *model.ToggleState {
+	for _, state := range flag.States {
+		if state.Environment == env {
+			return state
+		}
+	}
+	return nil
+}
@@ -0,0 +1,50 @@
+package evaluation
+
+import (
+	"fmt"
+
+	"github.com/shubham-tomar/feature-toggler/graphQl/model"
+)
+
+// trace collects the steps of an evaluation, a nil trace records nothing so that plain
+// evaluations take the same path without the cost of describing it
+type trace struct {
+	steps []*model.EvaluationStep
+}
+
+func (t *trace) add(step *model.EvaluationStep, format string, args ...any) {
+	if t == nil {
+		return
+	}
+	step.Detail = fmt.Sprintf(format, args...)
+	t.steps = append(t.steps, step)
+}
+
+// Explain evaluates a flag whose states are already loaded like Evaluate does, and also returns
+// every check that was made on the way. Unlike Evaluate it accepts archived flags, which clients
+// cannot evaluate, to say so.
+func Explain(flag *model.FeatureFlag, env model.Environment, evalCtx Context, targets Targets, experiments *Experiments) *model.EvaluationExplanation {
+	explanation := &model.EvaluationExplanation{
+		FeatureFlag: flag,
+		Environment: env,
+	}
+
+	if flag.Status == model.FlagStatusArchived {
+		explanation.Reason = string(ReasonError)
+		explanation.Steps = []*model.EvaluationStep{{
+			Kind:    model.EvaluationStepKindArchived,
+			Matched: true,
+			Detail:  "the flag is archived, clients get a FLAG_NOT_FOUND error and use their own default",
+		}}
+		return explanation
+	}
+
+	t := &trace{}
+	result := evaluate(flag, env, evalCtx, targets, experiments, t)
+
+	explanation.Value = &result.Value
+	explanation.Variant = &result.Variant
+	explanation.Reason = string(result.Reason)
+	explanation.Steps = t.steps
+	return explanation
+}
@@ -0,0 +1,81 @@
+package evaluation
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/shubham-tomar/feature-toggler/graphQl/model"
+)
+
+func TestLayerExclusivity(t *testing.T) {
+	layer := &model.ExperimentLayer{ID: "layer-1"}
+	experiment := func(id string, offset, traffic int) *model.Experiment {
+		return &model.Experiment{
+			ID:                id,
+			Layer:             layer,
+			LayerOffset:       &offset,
+			TrafficPercentage: traffic,
+			Allocations:       []*model.VariantAllocation{{Variant: "on", Weight: 100}},
+		}
+	}
+	experiments := []*model.Experiment{
+		experiment("exp-a", 0, 30),
+		experiment("exp-b", 30, 30),
+		experiment("exp-c", 60, 20),
+	}
+
+	const keys = 10000
+	enrolled := map[string]int{}
+	for i := range keys {
+		key := fmt.Sprintf("user-%d", i)
+
+		var in []string
+		for _, e := range experiments {
+			if _, ok := Assign(e, key); ok {
+				in = append(in, e.ID)
+			}
+		}
+		if len(in) > 1 {
+			t.Fatalf("%s is enrolled in %v, experiments of a layer must not overlap", key, in)
+		}
+		if len(in) == 1 {
+			enrolled[in[0]]++
+		}
+	}
+
+	tests := []struct {
+		id      string
+		traffic int
+	}{
+		{"exp-a", 30},
+		{"exp-b", 30},
+		{"exp-c", 20},
+	}
+	for _, tt := range tests {
+		want := keys * tt.traffic / 100
+		if got := enrolled[tt.id]; got < want-300 || got > want+300 {
+			t.Errorf("%s enrolled %d of %d keys, want about %d", tt.id, got, keys, want)
+		}
+	}
+}
+
+func TestHeldOut(t *testing.T) {
+	holdout := &model.Holdout{ID: "holdout-1", Name: "global", Percentage: 10}
+
+	const keys = 10000
+	held := 0
+	for i := range keys {
+		key := fmt.Sprintf("user-%d", i)
+		out := HeldOut(holdout, key)
+		if out != HeldOut(holdout, key) {
+			t.Fatalf("%s moved in and out of the holdout", key)
+		}
+		if out {
+			held++
+		}
+	}
+
+	if held < 800 || held > 1200 {
+		t.Errorf("%d of %d keys held out at 10%%", held, keys)
+	}
+}
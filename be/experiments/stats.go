@@ -0,0 +1,111 @@
+package experiments
+
+import (
+	"math"
+
+	"github.com/shubham-tomar/feature-toggler/graphQl/model"
+)
+
+// DefaultConfidenceLevel is used for intervals and significance unless another one is asked for
+const DefaultConfidenceLevel = 0.95
+
+// Sample is the outcome of one variant for one metric
+type Sample struct {
+	Variant     string
+	Exposures   int
+	Conversions int
+}
+
+func (s Sample) rate() float64 {
+	if s.Exposures == 0 {
+		return 0
+	}
+	return float64(s.Conversions) / float64(s.Exposures)
+}
+
+// Compare computes the result of every sample against the control sample
+func Compare(control Sample, samples []Sample, confidenceLevel float64) []*model.VariantResult {
+	z := zScore(confidenceLevel)
+
+	results := make([]*model.VariantResult, 0, len(samples))
+	for _, s := range samples {
+		lower, upper := wilson(s, z)
+		result := &model.VariantResult{
+			Variant:            s.Variant,
+			Exposures:          s.Exposures,
+			Conversions:        s.Conversions,
+			ConversionRate:     s.rate(),
+			ConfidenceInterval: &model.ConfidenceInterval{Lower: lower, Upper: upper},
+		}
+
+		if s.Variant != control.Variant {
+			if control.rate() > 0 {
+				lift := (s.rate() - control.rate()) / control.rate()
+				result.Lift = &lift
+			}
+			if p, ok := pValue(control, s); ok {
+				result.PValue = &p
+				result.Significant = p < 1-confidenceLevel
+			}
+			beat := probabilityToBeat(control, s)
+			result.ProbabilityToBeatControl = &beat
+		}
+
+		results = append(results, result)
+	}
+
+	return results
+}
+
+// zScore returns the two-sided critical value of the standard normal distribution
+func zScore(confidenceLevel float64) float64 {
+	return math.Sqrt2 * math.Erfinv(confidenceLevel)
+}
+
+// wilson returns the Wilson score interval of a conversion rate, which stays within [0, 1] for small samples
+func wilson(s Sample, z float64) (float64, float64) {
+	if s.Exposures == 0 {
+		return 0, 1
+	}
+
+	n := float64(s.Exposures)
+	p := s.rate()
+	denominator := 1 + z*z/n
+	center := (p + z*z/(2*n)) / denominator
+	margin := z * math.Sqrt(p*(1-p)/n+z*z/(4*n*n)) / denominator
+	return math.Max(0, center-margin), math.Min(1, center+margin)
+}
+
+// pValue runs a two-sided two-proportion z-test, it is undefined without exposures or variance
+func pValue(control, s Sample) (float64, bool) {
+	if control.Exposures == 0 || s.Exposures == 0 {
+		return 0, false
+	}
+
+	pooled := float64(control.Conversions+s.Conversions) / float64(control.Exposures+s.Exposures)
+	se := math.Sqrt(pooled * (1 - pooled) * (1/float64(control.Exposures) + 1/float64(s.Exposures)))
+	if se == 0 {
+		return 0, false
+	}
+
+	z := (s.rate() - control.rate()) / se
+	return math.Erfc(math.Abs(z) / math.Sqrt2), true
+}
+
+// probabilityToBeat is the chance that the true rate of s exceeds the control's, with Beta(1, 1) priors.
+// The Beta posteriors are approximated by normal distributions, close enough once a few dozen contexts are exposed.
+func probabilityToBeat(control, s Sample) float64 {
+	meanC, varC := betaPosterior(control)
+	meanS, varS := betaPosterior(s)
+	return normalCDF((meanS - meanC) / math.Sqrt(varC+varS))
+}
+
+func betaPosterior(s Sample) (float64, float64) {
+	a := float64(1 + s.Conversions)
+	b := float64(1 + s.Exposures - s.Conversions)
+	return a / (a + b), a * b / ((a + b) * (a + b) * (a + b + 1))
+}
+
+func normalCDF(x float64) float64 {
+	return 0.5 * math.Erfc(-x/math.Sqrt2)
+}
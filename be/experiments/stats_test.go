@@ -0,0 +1,123 @@
+package experiments
+
+import (
+	"math"
+	"testing"
+)
+
+// Expected values were computed independently, the Bayesian ones by integrating the exact Beta posteriors
+
+func TestZScore(t *testing.T) {
+	tests := []struct {
+		confidenceLevel float64
+		want            float64
+	}{
+		{0.90, 1.644854},
+		{0.95, 1.959964},
+		{0.99, 2.575829},
+	}
+
+	for _, tt := range tests {
+		if got := zScore(tt.confidenceLevel); math.Abs(got-tt.want) > 1e-6 {
+			t.Errorf("zScore(%v) = %v, want %v", tt.confidenceLevel, got, tt.want)
+		}
+	}
+}
+
+func TestWilson(t *testing.T) {
+	tests := []struct {
+		name                 string
+		sample               Sample
+		wantLower, wantUpper float64
+	}{
+		{"ten percent", Sample{Exposures: 100, Conversions: 10}, 0.055229, 0.174366},
+		{"no conversions", Sample{Exposures: 10, Conversions: 0}, 0, 0.277533},
+		{"all converted", Sample{Exposures: 10, Conversions: 10}, 0.722467, 1},
+		{"half of two", Sample{Exposures: 2, Conversions: 1}, 0.094531, 0.905469},
+		{"no exposures", Sample{}, 0, 1},
+	}
+
+	z := zScore(DefaultConfidenceLevel)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			lower, upper := wilson(tt.sample, z)
+			if math.Abs(lower-tt.wantLower) > 1e-6 || math.Abs(upper-tt.wantUpper) > 1e-6 {
+				t.Errorf("wilson() = [%v, %v], want [%v, %v]", lower, upper, tt.wantLower, tt.wantUpper)
+			}
+		})
+	}
+}
+
+func TestPValue(t *testing.T) {
+	tests := []struct {
+		name      string
+		control   Sample
+		sample    Sample
+		want      float64
+		wantValid bool
+	}{
+		{"significant", Sample{Exposures: 1000, Conversions: 200}, Sample{Exposures: 1000, Conversions: 250}, 0.007420, true},
+		{"not significant", Sample{Exposures: 1000, Conversions: 100}, Sample{Exposures: 1000, Conversions: 105}, 0.712413, true},
+		{"symmetric", Sample{Exposures: 1000, Conversions: 250}, Sample{Exposures: 1000, Conversions: 200}, 0.007420, true},
+		{"equal rates", Sample{Exposures: 500, Conversions: 50}, Sample{Exposures: 1000, Conversions: 100}, 1, true},
+		{"no exposures", Sample{Exposures: 1000, Conversions: 100}, Sample{}, 0, false},
+		{"no variance", Sample{Exposures: 100}, Sample{Exposures: 100}, 0, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := pValue(tt.control, tt.sample)
+			if ok != tt.wantValid || math.Abs(got-tt.want) > 1e-6 {
+				t.Errorf("pValue() = %v, %v, want %v, %v", got, ok, tt.want, tt.wantValid)
+			}
+		})
+	}
+}
+
+func TestProbabilityToBeat(t *testing.T) {
+	tests := []struct {
+		name      string
+		control   Sample
+		sample    Sample
+		want      float64
+		tolerance float64 // The normal approximation drifts from the exact posterior on small samples
+	}{
+		{"clear winner", Sample{Exposures: 1000, Conversions: 200}, Sample{Exposures: 1000, Conversions: 250}, 0.996283, 0.002},
+		{"close call", Sample{Exposures: 1000, Conversions: 100}, Sample{Exposures: 1000, Conversions: 105}, 0.643451, 0.005},
+		{"loser", Sample{Exposures: 100, Conversions: 20}, Sample{Exposures: 100, Conversions: 15}, 0.179057, 0.01},
+		{"identical", Sample{Exposures: 300, Conversions: 30}, Sample{Exposures: 300, Conversions: 30}, 0.5, 1e-9},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := probabilityToBeat(tt.control, tt.sample); math.Abs(got-tt.want) > tt.tolerance {
+				t.Errorf("probabilityToBeat() = %v, want %v ± %v", got, tt.want, tt.tolerance)
+			}
+		})
+	}
+}
+
+func TestCompare(t *testing.T) {
+	control := Sample{Variant: "off", Exposures: 1000, Conversions: 200}
+	treatment := Sample{Variant: "on", Exposures: 1000, Conversions: 250}
+
+	results := Compare(control, []Sample{control, treatment}, DefaultConfidenceLevel)
+	if len(results) != 2 {
+		t.Fatalf("Compare() returned %d results, want 2", len(results))
+	}
+
+	if r := results[0]; r.Lift != nil || r.PValue != nil || r.ProbabilityToBeatControl != nil || r.Significant {
+		t.Errorf("control result compares the control with itself: %+v", r)
+	}
+
+	r := results[1]
+	if r.Lift == nil || math.Abs(*r.Lift-0.25) > 1e-9 {
+		t.Errorf("lift = %v, want 0.25", r.Lift)
+	}
+	if !r.Significant {
+		t.Errorf("a p-value of %v is not significant at %v", *r.PValue, DefaultConfidenceLevel)
+	}
+	if r.ConversionRate != 0.25 {
+		t.Errorf("conversion rate = %v, want 0.25", r.ConversionRate)
+	}
+}
@@ -16,8 +16,8 @@ var MockUser = &model.User{
 	Email: "tomar@gmail.com",
 }
 
-func WithUser(ctx context.Context) context.Context {
-	return context.WithValue(ctx, userKey, MockUser)
+func WithUser(ctx context.Context, user *model.User) context.Context {
+	return context.WithValue(ctx, userKey, user)
 }
 
 func GetUser(ctx context.Context) *model.User {
@@ -0,0 +1,107 @@
+package loaders
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/shubham-tomar/feature-toggler/db"
+)
+
+// FetchFunc loads the values of many keys at once. Keys missing from the result are not found.
+type FetchFunc[K comparable, V any] func(ctx context.Context, keys []K) (map[K]V, error)
+
+// Loader collects the keys requested within a short window and fetches them in one batch.
+// Results are cached for the lifetime of the loader, which is a single request.
+type Loader[K comparable, V any] struct {
+	fetch    FetchFunc[K, V]
+	wait     time.Duration
+	maxBatch int
+
+	mu      sync.Mutex
+	cache   map[K]*result[V]
+	pending *batch[K, V]
+}
+
+type result[V any] struct {
+	done  chan struct{}
+	value V
+	err   error
+}
+
+type batch[K comparable, V any] struct {
+	keys    []K
+	results map[K]*result[V]
+	once    sync.Once
+}
+
+// NewLoader creates a loader that waits for more keys for the given duration before fetching
+func NewLoader[K comparable, V any](fetch FetchFunc[K, V], wait time.Duration, maxBatch int) *Loader[K, V] {
+	return &Loader[K, V]{
+		fetch:    fetch,
+		wait:     wait,
+		maxBatch: maxBatch,
+		cache:    map[K]*result[V]{},
+	}
+}
+
+// Load returns the value of a key, fetching it together with the keys requested around the same time
+func (l *Loader[K, V]) Load(ctx context.Context, key K) (V, error) {
+	l.mu.Lock()
+
+	r, ok := l.cache[key]
+	if !ok {
+		r = &result[V]{done: make(chan struct{})}
+		l.cache[key] = r
+
+		b := l.pending
+		if b == nil {
+			b = &batch[K, V]{results: map[K]*result[V]{}}
+			l.pending = b
+			time.AfterFunc(l.wait, func() { l.dispatch(ctx, b) })
+		}
+
+		b.keys = append(b.keys, key)
+		b.results[key] = r
+
+		// A full batch takes no more keys, even before its fetch starts
+		if len(b.keys) >= l.maxBatch {
+			l.pending = nil
+			go l.dispatch(ctx, b)
+		}
+	}
+
+	l.mu.Unlock()
+
+	select {
+	case <-r.done:
+		return r.value, r.err
+	case <-ctx.Done():
+		var zero V
+		return zero, ctx.Err()
+	}
+}
+
+// dispatch fetches a batch once, whether the window ran out or the batch filled up first
+func (l *Loader[K, V]) dispatch(ctx context.Context, b *batch[K, V]) {
+	b.once.Do(func() {
+		l.mu.Lock()
+		if l.pending == b {
+			l.pending = nil
+		}
+		l.mu.Unlock()
+
+		values, err := l.fetch(ctx, b.keys)
+		for key, r := range b.results {
+			if err != nil {
+				r.err = err
+			} else if value, ok := values[key]; ok {
+				r.value = value
+			} else {
+				r.err = fmt.Errorf("%v %w", key, db.ErrNotFound)
+			}
+			close(r.done)
+		}
+	})
+}
@@ -0,0 +1,135 @@
+package loaders
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/shubham-tomar/feature-toggler/db"
+)
+
+// recordingFetch answers every key with its square and records the batches it was called with
+type recordingFetch struct {
+	mu      sync.Mutex
+	batches [][]int
+	missing int
+	err     error
+}
+
+func (f *recordingFetch) fetch(_ context.Context, keys []int) (map[int]int, error) {
+	f.mu.Lock()
+	f.batches = append(f.batches, slices.Clone(keys))
+	f.mu.Unlock()
+
+	if f.err != nil {
+		return nil, f.err
+	}
+	values := map[int]int{}
+	for _, key := range keys {
+		if key != f.missing {
+			values[key] = key * key
+		}
+	}
+	return values, nil
+}
+
+// loadAll loads the keys concurrently, as sibling resolvers do
+func loadAll(l *Loader[int, int], keys ...int) ([]int, []error) {
+	values := make([]int, len(keys))
+	errs := make([]error, len(keys))
+
+	var wg sync.WaitGroup
+	for i, key := range keys {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			values[i], errs[i] = l.Load(context.Background(), key)
+		}()
+	}
+	wg.Wait()
+
+	return values, errs
+}
+
+func TestLoaderBatchesConcurrentLoads(t *testing.T) {
+	f := &recordingFetch{missing: -1}
+	l := NewLoader(f.fetch, 20*time.Millisecond, 100)
+
+	values, errs := loadAll(l, 1, 2, 3, 2, 1)
+	for i, want := range []int{1, 4, 9, 4, 1} {
+		if values[i] != want || errs[i] != nil {
+			t.Errorf("load %d = %d, %v, want %d", i, values[i], errs[i], want)
+		}
+	}
+
+	if len(f.batches) != 1 {
+		t.Fatalf("fetched %d batches %v, want 1", len(f.batches), f.batches)
+	}
+	batch := slices.Sorted(slices.Values(f.batches[0]))
+	if !slices.Equal(batch, []int{1, 2, 3}) {
+		t.Errorf("fetched %v, want every key once", batch)
+	}
+
+	// Loaded keys are cached for the rest of the request
+	if v, err := l.Load(context.Background(), 3); v != 9 || err != nil || len(f.batches) != 1 {
+		t.Errorf("load of a cached key = %d, %v after %d fetches, want 9 without a fetch", v, err, len(f.batches))
+	}
+}
+
+func TestLoaderSplitsFullBatches(t *testing.T) {
+	f := &recordingFetch{missing: -1}
+	l := NewLoader(f.fetch, 20*time.Millisecond, 3)
+
+	if _, errs := loadAll(l, 1, 2, 3, 4, 5, 6, 7); errors.Join(errs...) != nil {
+		t.Fatal(errors.Join(errs...))
+	}
+
+	var keys []int
+	for _, batch := range f.batches {
+		if len(batch) > 3 {
+			t.Errorf("fetched a batch of %d keys, want at most 3", len(batch))
+		}
+		keys = append(keys, batch...)
+	}
+	slices.Sort(keys)
+	if !slices.Equal(keys, []int{1, 2, 3, 4, 5, 6, 7}) {
+		t.Errorf("fetched %v, want every key once", keys)
+	}
+}
+
+func TestLoaderErrors(t *testing.T) {
+	t.Run("missing key", func(t *testing.T) {
+		l := NewLoader((&recordingFetch{missing: 2}).fetch, time.Millisecond, 100)
+
+		_, errs := loadAll(l, 1, 2)
+		if errs[0] != nil || !errors.Is(errs[1], db.ErrNotFound) {
+			t.Errorf("got errors %v, want only %v for the missing key", errs, db.ErrNotFound)
+		}
+	})
+
+	t.Run("failed fetch", func(t *testing.T) {
+		failure := fmt.Errorf("database is down")
+		l := NewLoader((&recordingFetch{err: failure}).fetch, time.Millisecond, 100)
+
+		_, errs := loadAll(l, 1, 2)
+		for i, err := range errs {
+			if !errors.Is(err, failure) {
+				t.Errorf("load %d: got error %v, want %v", i, err, failure)
+			}
+		}
+	})
+
+	t.Run("cancelled request", func(t *testing.T) {
+		l := NewLoader((&recordingFetch{}).fetch, time.Hour, 100)
+
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		if _, err := l.Load(ctx, 1); !errors.Is(err, context.Canceled) {
+			t.Errorf("got error %v, want %v", err, context.Canceled)
+		}
+	})
+}
@@ -0,0 +1,121 @@
+package loaders
+
+import (
+	"context"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/shubham-tomar/feature-toggler/db"
+	"github.com/shubham-tomar/feature-toggler/graphQl/model"
+)
+
+type ctxKey string
+
+const loadersKey = ctxKey("loaders")
+
+// Long enough for sibling resolvers, which gqlgen runs concurrently, to join the same batch
+const (
+	batchWait = 2 * time.Millisecond
+	maxBatch  = 100
+)
+
+// Loaders batches the lookups of nested fields within a single GraphQL request
+type Loaders struct {
+	Users    *Loader[string, *model.User]
+	Projects *Loader[string, *model.Project]
+	// Members and States are keyed by the id of the project and feature flag they belong to
+	Members *Loader[string, []*model.ProjectUser]
+	States  *Loader[string, []*model.ToggleState]
+	// LastEvaluations is keyed by flag id, flags never evaluated get nil
+	LastEvaluations *Loader[string, *time.Time]
+}
+
+// New creates loaders backed by the storage, they must not be shared between requests
+func New(storage db.Storage) *Loaders {
+	return &Loaders{
+		Users: NewLoader(func(ctx context.Context, ids []string) (map[string]*model.User, error) {
+			users, err := storage.GetUsersByIDs(ctx, ids)
+			if err != nil {
+				return nil, err
+			}
+			return byID(users, func(u *model.User) string { return u.ID }), nil
+		}, batchWait, maxBatch),
+
+		Projects: NewLoader(func(ctx context.Context, ids []string) (map[string]*model.Project, error) {
+			projects, err := storage.GetProjectsByIDs(ctx, ids)
+			if err != nil {
+				return nil, err
+			}
+			return byID(projects, func(p *model.Project) string { return p.ID }), nil
+		}, batchWait, maxBatch),
+
+		Members: NewLoader(func(ctx context.Context, projectIDs []string) (map[string][]*model.ProjectUser, error) {
+			members, err := storage.GetMembersByProjectIDs(ctx, projectIDs)
+			if err != nil {
+				return nil, err
+			}
+			return groupBy(projectIDs, members, func(m *model.ProjectUser) string { return m.Project.ID }), nil
+		}, batchWait, maxBatch),
+
+		States: NewLoader(func(ctx context.Context, flagIDs []string) (map[string][]*model.ToggleState, error) {
+			states, err := storage.GetStatesByFeatureFlagIDs(ctx, flagIDs)
+			if err != nil {
+				return nil, err
+			}
+			return groupBy(flagIDs, states, func(s *model.ToggleState) string { return s.FeatureFlag.ID }), nil
+		}, batchWait, maxBatch),
+
+		LastEvaluations: NewLoader(func(ctx context.Context, flagIDs []string) (map[string]*time.Time, error) {
+			last, err := storage.GetLastEvaluations(ctx, flagIDs)
+			if err != nil {
+				return nil, err
+			}
+			m := make(map[string]*time.Time, len(flagIDs))
+			for _, id := range flagIDs {
+				m[id] = nil
+				if at, ok := last[id]; ok {
+					m[id] = &at
+				}
+			}
+			return m, nil
+		}, batchWait, maxBatch),
+	}
+}
+
+// Middleware gives every request its own loaders
+func Middleware(storage db.Storage) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Request = c.Request.WithContext(WithLoaders(c.Request.Context(), New(storage)))
+		c.Next()
+	}
+}
+
+func WithLoaders(ctx context.Context, l *Loaders) context.Context {
+	return context.WithValue(ctx, loadersKey, l)
+}
+
+// For returns the loaders of the request, or nil outside of Middleware
+func For(ctx context.Context) *Loaders {
+	l, _ := ctx.Value(loadersKey).(*Loaders)
+	return l
+}
+
+func byID[V any](values []V, id func(V) string) map[string]V {
+	m := make(map[string]V, len(values))
+	for _, v := range values {
+		m[id(v)] = v
+	}
+	return m
+}
+
+// groupBy groups values by their parent, every requested parent gets an entry even without values
+func groupBy[V any](parents []string, values []V, parent func(V) string) map[string][]V {
+	m := make(map[string][]V, len(parents))
+	for _, p := range parents {
+		m[p] = []V{}
+	}
+	for _, v := range values {
+		m[parent(v)] = append(m[parent(v)], v)
+	}
+	return m
+}
@@ -0,0 +1,41 @@
+package loaders
+
+import (
+	"context"
+	"testing"
+
+	"github.com/shubham-tomar/feature-toggler/db/sqlite/sqlitetest"
+	"github.com/shubham-tomar/feature-toggler/graphQl/model"
+)
+
+func TestStatesAreGroupedByFlag(t *testing.T) {
+	storage := sqlitetest.New(t)
+	project := sqlitetest.Project(t, storage, sqlitetest.User(t, storage, "admin"), "checkout")
+	on := sqlitetest.Flag(t, storage, project, "on", model.AllEnvironment...)
+	off := sqlitetest.Flag(t, storage, project, "off")
+
+	// Flags without states get an empty list rather than an error
+	l := New(storage)
+	for _, tt := range []struct {
+		id              string
+		states, enabled int
+	}{{on.ID, 3, 3}, {off.ID, 3, 0}, {"deleted", 0, 0}} {
+		states, err := l.States.Load(context.Background(), tt.id)
+		if err != nil {
+			t.Fatalf("%s: %v", tt.id, err)
+		}
+
+		enabled := 0
+		for _, state := range states {
+			if state.FeatureFlag.ID != tt.id {
+				t.Errorf("%s got the state of flag %s", tt.id, state.FeatureFlag.ID)
+			}
+			if state.Enabled {
+				enabled++
+			}
+		}
+		if len(states) != tt.states || enabled != tt.enabled {
+			t.Errorf("%s: got %d states, %d enabled, want %d, %d enabled", tt.id, len(states), enabled, tt.states, tt.enabled)
+		}
+	}
+}
@@ -8,41 +8,397 @@ import (
 	"io"
 	"strconv"
 	"time"
+
+	"github.com/99designs/gqlgen/graphql"
 )
 
+type AccessToken struct {
+	ID         string       `json:"id"`
+	Name       string       `json:"name"`
+	User       *User        `json:"user"`
+	Scopes     []TokenScope `json:"scopes"`
+	Prefix     string       `json:"prefix"`
+	CreatedAt  time.Time    `json:"created_at"`
+	ExpiresAt  *time.Time   `json:"expires_at,omitempty"`
+	LastUsedAt *time.Time   `json:"last_used_at,omitempty"`
+	RevokedAt  *time.Time   `json:"revoked_at,omitempty"`
+}
+
+type AddIndividualTargetsInput struct {
+	FeatureFlagID string      `json:"featureFlagId"`
+	Environment   Environment `json:"environment"`
+	Variant       string      `json:"variant"`
+	ContextKeys   []string    `json:"contextKeys"`
+}
+
 type AddProjectMemberInput struct {
 	ProjectID string `json:"projectId"`
 	UserID    string `json:"userId"`
 	Role      Role   `json:"role"`
 }
 
+type ChangeSet struct {
+	ID          string          `json:"id"`
+	Project     *Project        `json:"project"`
+	Name        string          `json:"name"`
+	Description *string         `json:"description,omitempty"`
+	Status      ChangeSetStatus `json:"status"`
+	Changes     []*FlagChange   `json:"changes"`
+	ScheduledAt *time.Time      `json:"scheduled_at,omitempty"`
+	Error       *string         `json:"error,omitempty"`
+	CreatedBy   *User           `json:"created_by"`
+	CreatedAt   time.Time       `json:"created_at"`
+	UpdatedAt   time.Time       `json:"updated_at"`
+	SubmittedBy *User           `json:"submitted_by,omitempty"`
+	DecidedBy   *User           `json:"decided_by,omitempty"`
+	AppliedAt   *time.Time      `json:"applied_at,omitempty"`
+	RevertedBy  *User           `json:"reverted_by,omitempty"`
+	RevertedAt  *time.Time      `json:"reverted_at,omitempty"`
+}
+
+type ConfidenceInterval struct {
+	Lower float64 `json:"lower"`
+	Upper float64 `json:"upper"`
+}
+
+type CreateAccessTokenInput struct {
+	Name          string       `json:"name"`
+	Scopes        []TokenScope `json:"scopes"`
+	UserID        *string      `json:"userId,omitempty"`
+	ExpiresInDays *int         `json:"expiresInDays,omitempty"`
+}
+
+type CreateChangeSetInput struct {
+	ProjectID   string             `json:"projectId"`
+	Name        string             `json:"name"`
+	Description *string            `json:"description,omitempty"`
+	Changes     []*FlagChangeInput `json:"changes"`
+}
+
+type CreateExperimentInput struct {
+	FeatureFlagID     string                    `json:"featureFlagId"`
+	Environment       Environment               `json:"environment"`
+	Name              string                    `json:"name"`
+	Hypothesis        *string                   `json:"hypothesis,omitempty"`
+	TrafficPercentage *int                      `json:"trafficPercentage,omitempty"`
+	Allocations       []*VariantAllocationInput `json:"allocations,omitempty"`
+	Metrics           []*ExperimentMetricInput  `json:"metrics"`
+	LayerID           *string                   `json:"layerId,omitempty"`
+}
+
+type CreateExperimentLayerInput struct {
+	ProjectID   string  `json:"projectId"`
+	Name        string  `json:"name"`
+	Description *string `json:"description,omitempty"`
+}
+
 type CreateFeatureFlagInput struct {
 	ProjectID     string               `json:"projectId"`
 	Key           string               `json:"key"`
 	Name          string               `json:"name"`
 	Description   *string              `json:"description,omitempty"`
+	ClientSide    *bool                `json:"clientSide,omitempty"`
 	InitialStates []*InitialStateInput `json:"initialStates,omitempty"`
+	Kind          *FlagKind            `json:"kind,omitempty"`
+	Tags          []string             `json:"tags,omitempty"`
+	OwnerID       *string              `json:"ownerId,omitempty"`
+	OwnerTeam     *string              `json:"ownerTeam,omitempty"`
+	RemovalDate   *time.Time           `json:"removalDate,omitempty"`
+	Links         []*FlagLinkInput     `json:"links,omitempty"`
+}
+
+type CreateHoldoutInput struct {
+	ProjectID  string `json:"projectId"`
+	Name       string `json:"name"`
+	Percentage int    `json:"percentage"`
+}
+
+type CreateOverrideTokenInput struct {
+	ProjectID      string               `json:"projectId"`
+	Environment    Environment          `json:"environment"`
+	Overrides      []*FlagOverrideInput `json:"overrides"`
+	ExpiresInHours *int                 `json:"expiresInHours,omitempty"`
 }
 
 type CreateProjectInput struct {
 	Name string `json:"name"`
 }
 
+type CreateSdkKeyInput struct {
+	ProjectID   string      `json:"projectId"`
+	Environment Environment `json:"environment"`
+	Kind        SdkKeyKind  `json:"kind"`
+	Name        string      `json:"name"`
+}
+
 type CreateUserInput struct {
 	Name  string `json:"name"`
 	Email string `json:"email"`
 }
 
+type CreateWebhookInput struct {
+	ProjectID  string             `json:"projectId"`
+	URL        string             `json:"url"`
+	EventTypes []WebhookEventType `json:"eventTypes,omitempty"`
+	Secret     *string            `json:"secret,omitempty"`
+}
+
+type CreatedAccessToken struct {
+	AccessToken *AccessToken `json:"access_token"`
+	Secret      string       `json:"secret"`
+}
+
+type CreatedSdkKey struct {
+	SdkKey *SdkKey `json:"sdk_key"`
+	Secret string  `json:"secret"`
+}
+
+type CreatedWebhook struct {
+	Webhook *Webhook `json:"webhook"`
+	Secret  string   `json:"secret"`
+}
+
+type EnvironmentComparison struct {
+	A        Environment  `json:"a"`
+	B        Environment  `json:"b"`
+	Compared int          `json:"compared"`
+	Drift    []*FlagDrift `json:"drift"`
+}
+
+type EvaluationBucket struct {
+	Start time.Time `json:"start"`
+	Count int       `json:"count"`
+}
+
+type EvaluationExplanation struct {
+	FeatureFlag *FeatureFlag      `json:"feature_flag"`
+	Environment Environment       `json:"environment"`
+	Value       *bool             `json:"value,omitempty"`
+	Variant     *string           `json:"variant,omitempty"`
+	Reason      string            `json:"reason"`
+	Steps       []*EvaluationStep `json:"steps"`
+}
+
+type EvaluationStats struct {
+	Total           int                 `json:"total"`
+	LastEvaluatedAt *time.Time          `json:"last_evaluated_at,omitempty"`
+	UniqueContexts  int                 `json:"unique_contexts"`
+	Variants        []*VariantCount     `json:"variants"`
+	Buckets         []*EvaluationBucket `json:"buckets"`
+}
+
+type EvaluationStep struct {
+	Kind       EvaluationStepKind `json:"kind"`
+	Matched    bool               `json:"matched"`
+	Detail     string             `json:"detail"`
+	Bucket     *int               `json:"bucket,omitempty"`
+	Experiment *Experiment        `json:"experiment,omitempty"`
+	Holdout    *Holdout           `json:"holdout,omitempty"`
+}
+
+type Experiment struct {
+	ID                string               `json:"id"`
+	FeatureFlag       *FeatureFlag         `json:"feature_flag"`
+	Environment       Environment          `json:"environment"`
+	Name              string               `json:"name"`
+	Hypothesis        *string              `json:"hypothesis,omitempty"`
+	Status            ExperimentStatus     `json:"status"`
+	TrafficPercentage int                  `json:"traffic_percentage"`
+	Allocations       []*VariantAllocation `json:"allocations"`
+	Metrics           []*ExperimentMetric  `json:"metrics"`
+	CreatedBy         *User                `json:"created_by"`
+	CreatedAt         time.Time            `json:"created_at"`
+	UpdatedAt         time.Time            `json:"updated_at"`
+	StartedAt         *time.Time           `json:"started_at,omitempty"`
+	StoppedAt         *time.Time           `json:"stopped_at,omitempty"`
+	Results           *ExperimentResults   `json:"results"`
+	Layer             *ExperimentLayer     `json:"layer,omitempty"`
+	LayerOffset       *int                 `json:"layer_offset,omitempty"`
+}
+
+type ExperimentLayer struct {
+	ID             string        `json:"id"`
+	Project        *Project      `json:"project"`
+	Name           string        `json:"name"`
+	Description    *string       `json:"description,omitempty"`
+	CreatedAt      time.Time     `json:"created_at"`
+	UpdatedAt      time.Time     `json:"updated_at"`
+	Experiments    []*Experiment `json:"experiments"`
+	FreePercentage int           `json:"free_percentage"`
+}
+
+type ExperimentMetric struct {
+	Key  string `json:"key"`
+	Name string `json:"name"`
+}
+
+type ExperimentMetricInput struct {
+	Key  string  `json:"key"`
+	Name *string `json:"name,omitempty"`
+}
+
+type ExperimentResults struct {
+	ConfidenceLevel float64         `json:"confidence_level"`
+	Control         string          `json:"control"`
+	Exposures       int             `json:"exposures"`
+	Metrics         []*MetricResult `json:"metrics"`
+}
+
 type FeatureFlag struct {
-	ID          string         `json:"id"`
-	Key         string         `json:"key"`
-	Name        string         `json:"name"`
-	Description *string        `json:"description,omitempty"`
-	CreatedBy   *User          `json:"created_by"`
-	CreatedAt   time.Time      `json:"created_at"`
-	UpdatedAt   time.Time      `json:"updated_at"`
-	States      []*ToggleState `json:"states"`
-	Project     *Project       `json:"project"`
+	ID          string           `json:"id"`
+	Key         string           `json:"key"`
+	Name        string           `json:"name"`
+	Description *string          `json:"description,omitempty"`
+	CreatedBy   *User            `json:"created_by"`
+	CreatedAt   time.Time        `json:"created_at"`
+	UpdatedAt   time.Time        `json:"updated_at"`
+	States      []*ToggleState   `json:"states"`
+	Project     *Project         `json:"project"`
+	ClientSide  bool             `json:"client_side"`
+	Status      FlagStatus       `json:"status"`
+	ArchivedAt  *time.Time       `json:"archived_at,omitempty"`
+	Evaluations *EvaluationStats `json:"evaluations"`
+	Health      *FlagHealth      `json:"health"`
+	Kind        FlagKind         `json:"kind"`
+	Tags        []string         `json:"tags"`
+	Owner       *User            `json:"owner,omitempty"`
+	OwnerTeam   *string          `json:"owner_team,omitempty"`
+	RemovalDate *time.Time       `json:"removal_date,omitempty"`
+	Links       []*FlagLink      `json:"links"`
+	Expired     bool             `json:"expired"`
+	Warnings    []string         `json:"warnings"`
+}
+
+type FeatureFlagConnection struct {
+	Edges      []*FeatureFlagEdge `json:"edges"`
+	PageInfo   *PageInfo          `json:"pageInfo"`
+	TotalCount int                `json:"totalCount"`
+}
+
+type FeatureFlagEdge struct {
+	Cursor string       `json:"cursor"`
+	Node   *FeatureFlag `json:"node"`
+}
+
+type FeatureFlagFilter struct {
+	Search         *string      `json:"search,omitempty"`
+	Status         *FlagStatus  `json:"status,omitempty"`
+	EnabledIn      *Environment `json:"enabledIn,omitempty"`
+	CreatedBy      *string      `json:"createdBy,omitempty"`
+	Stale          *bool        `json:"stale,omitempty"`
+	StaleAfterDays *int         `json:"staleAfterDays,omitempty"`
+	Tags           []string     `json:"tags,omitempty"`
+	Kind           *FlagKind    `json:"kind,omitempty"`
+	OwnerID        *string      `json:"ownerId,omitempty"`
+	OwnerTeam      *string      `json:"ownerTeam,omitempty"`
+	Expired        *bool        `json:"expired,omitempty"`
+}
+
+type FeatureFlagOrder struct {
+	Field     FeatureFlagOrderField `json:"field"`
+	Direction OrderDirection        `json:"direction"`
+}
+
+type FlagChange struct {
+	FeatureFlag     *FeatureFlag   `json:"feature_flag,omitempty"`
+	Environment     Environment    `json:"environment"`
+	Enabled         *bool          `json:"enabled,omitempty"`
+	AddTargets      []*TargetGroup `json:"add_targets"`
+	RemoveTargets   []string       `json:"remove_targets"`
+	PreviousEnabled *bool          `json:"previous_enabled,omitempty"`
+}
+
+type FlagChangeInput struct {
+	FeatureFlagID string              `json:"featureFlagId"`
+	Environment   Environment         `json:"environment"`
+	Enabled       *bool               `json:"enabled,omitempty"`
+	AddTargets    []*TargetGroupInput `json:"addTargets,omitempty"`
+	RemoveTargets []string            `json:"removeTargets,omitempty"`
+}
+
+type FlagDrift struct {
+	FeatureFlag *FeatureFlag          `json:"feature_flag"`
+	Fields      []FlagStateField      `json:"fields"`
+	A           *FlagEnvironmentState `json:"a"`
+	B           *FlagEnvironmentState `json:"b"`
+}
+
+type FlagEnvironmentState struct {
+	Configured bool       `json:"configured"`
+	Enabled    *bool      `json:"enabled,omitempty"`
+	Targets    int        `json:"targets"`
+	UpdatedAt  *time.Time `json:"updated_at,omitempty"`
+	UpdatedBy  *User      `json:"updated_by,omitempty"`
+}
+
+type FlagHealth struct {
+	Status          FlagHealthStatus `json:"status"`
+	Reason          string           `json:"reason"`
+	LastToggledAt   time.Time        `json:"last_toggled_at"`
+	LastEvaluatedAt *time.Time       `json:"last_evaluated_at,omitempty"`
+}
+
+type FlagLink struct {
+	Title *string `json:"title,omitempty"`
+	URL   string  `json:"url"`
+}
+
+type FlagLinkInput struct {
+	Title *string `json:"title,omitempty"`
+	URL   string  `json:"url"`
+}
+
+type FlagOverride struct {
+	FlagKey string `json:"flag_key"`
+	Variant string `json:"variant"`
+}
+
+type FlagOverrideInput struct {
+	FlagKey string `json:"flagKey"`
+	Variant string `json:"variant"`
+}
+
+type FlagSimulation struct {
+	Contexts int                `json:"contexts"`
+	Current  []*VariantCount    `json:"current"`
+	Proposed []*VariantCount    `json:"proposed"`
+	Changes  []*SimulatedChange `json:"changes"`
+}
+
+type FlagStateChange struct {
+	FeatureFlag    *FeatureFlag `json:"feature_flag,omitempty"`
+	FlagKey        string       `json:"flag_key"`
+	EnabledBefore  *bool        `json:"enabled_before,omitempty"`
+	EnabledAfter   bool         `json:"enabled_after"`
+	TargetsAdded   int          `json:"targets_added"`
+	TargetsRemoved int          `json:"targets_removed"`
+	TargetsMoved   int          `json:"targets_moved"`
+}
+
+type Holdout struct {
+	ID         string    `json:"id"`
+	Project    *Project  `json:"project"`
+	Name       string    `json:"name"`
+	Percentage int       `json:"percentage"`
+	CreatedAt  time.Time `json:"created_at"`
+	UpdatedAt  time.Time `json:"updated_at"`
+}
+
+type IndividualTarget struct {
+	ContextKey string    `json:"context_key"`
+	Variant    string    `json:"variant"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+type IndividualTargetConnection struct {
+	Edges      []*IndividualTargetEdge `json:"edges"`
+	PageInfo   *PageInfo               `json:"pageInfo"`
+	TotalCount int                     `json:"totalCount"`
+}
+
+type IndividualTargetEdge struct {
+	Cursor string            `json:"cursor"`
+	Node   *IndividualTarget `json:"node"`
 }
 
 type InitialStateInput struct {
@@ -50,15 +406,72 @@ type InitialStateInput struct {
 	Enabled     bool        `json:"enabled"`
 }
 
+type InviteProjectMemberInput struct {
+	ProjectID     string `json:"projectId"`
+	Email         string `json:"email"`
+	Role          Role   `json:"role"`
+	ExpiresInDays *int   `json:"expiresInDays,omitempty"`
+}
+
+type MetricResult struct {
+	Metric   *ExperimentMetric `json:"metric"`
+	Variants []*VariantResult  `json:"variants"`
+}
+
 type Mutation struct {
 }
 
+type OverrideToken struct {
+	Token       string          `json:"token"`
+	Environment Environment     `json:"environment"`
+	Overrides   []*FlagOverride `json:"overrides"`
+	ExpiresAt   time.Time       `json:"expires_at"`
+}
+
+type PageInfo struct {
+	HasNextPage     bool    `json:"hasNextPage"`
+	HasPreviousPage bool    `json:"hasPreviousPage"`
+	StartCursor     *string `json:"startCursor,omitempty"`
+	EndCursor       *string `json:"endCursor,omitempty"`
+}
+
 type Project struct {
-	ID        string         `json:"id"`
-	Name      string         `json:"name"`
-	CreatedAt time.Time      `json:"created_at"`
-	UpdatedAt time.Time      `json:"updated_at"`
-	Members   []*ProjectUser `json:"members"`
+	ID                    string                 `json:"id"`
+	Name                  string                 `json:"name"`
+	CreatedAt             time.Time              `json:"created_at"`
+	UpdatedAt             time.Time              `json:"updated_at"`
+	Members               []*ProjectUser         `json:"members"`
+	FlagKeyPattern        *string                `json:"flag_key_pattern,omitempty"`
+	FlagKeyMaxLength      *int                   `json:"flag_key_max_length,omitempty"`
+	NewFlagDays           *int                   `json:"new_flag_days,omitempty"`
+	StaleAfterDays        *int                   `json:"stale_after_days,omitempty"`
+	UnusedAfterDays       *int                   `json:"unused_after_days,omitempty"`
+	ProtectedEnvironments []Environment          `json:"protected_environments"`
+	ProtectionChanges     []*ProtectionChange    `json:"protection_changes"`
+	FeatureFlags          *FeatureFlagConnection `json:"featureFlags"`
+}
+
+type ProjectConnection struct {
+	Edges      []*ProjectEdge `json:"edges"`
+	PageInfo   *PageInfo      `json:"pageInfo"`
+	TotalCount int            `json:"totalCount"`
+}
+
+type ProjectEdge struct {
+	Cursor string   `json:"cursor"`
+	Node   *Project `json:"node"`
+}
+
+type ProjectInvitation struct {
+	ID          string           `json:"id"`
+	Project     *Project         `json:"project"`
+	Email       string           `json:"email"`
+	Role        Role             `json:"role"`
+	Status      InvitationStatus `json:"status"`
+	InvitedBy   *User            `json:"invited_by"`
+	CreatedAt   time.Time        `json:"created_at"`
+	ExpiresAt   time.Time        `json:"expires_at"`
+	RespondedAt *time.Time       `json:"responded_at,omitempty"`
 }
 
 type ProjectUser struct {
@@ -68,9 +481,87 @@ type ProjectUser struct {
 	Role    Role     `json:"role"`
 }
 
+type Promotion struct {
+	ID          *string            `json:"id,omitempty"`
+	Project     *Project           `json:"project"`
+	FeatureFlag *FeatureFlag       `json:"feature_flag,omitempty"`
+	From        Environment        `json:"from"`
+	To          Environment        `json:"to"`
+	Status      PromotionStatus    `json:"status"`
+	Changes     []*FlagStateChange `json:"changes"`
+	RequestedBy *User              `json:"requested_by"`
+	CreatedAt   time.Time          `json:"created_at"`
+	DecidedBy   *User              `json:"decided_by,omitempty"`
+	DecidedAt   *time.Time         `json:"decided_at,omitempty"`
+}
+
+type ProposedFlagConfigInput struct {
+	Enabled        *bool               `json:"enabled,omitempty"`
+	StopExperiment *bool               `json:"stopExperiment,omitempty"`
+	AddTargets     []*TargetGroupInput `json:"addTargets,omitempty"`
+	RemoveTargets  []string            `json:"removeTargets,omitempty"`
+}
+
+type ProtectionChange struct {
+	Environments []Environment `json:"environments"`
+	ChangedBy    *User         `json:"changed_by"`
+	ChangedAt    time.Time     `json:"changed_at"`
+}
+
 type Query struct {
 }
 
+type RemoveIndividualTargetsInput struct {
+	FeatureFlagID string      `json:"featureFlagId"`
+	Environment   Environment `json:"environment"`
+	ContextKeys   []string    `json:"contextKeys"`
+}
+
+type SdkKey struct {
+	ID          string      `json:"id"`
+	Name        string      `json:"name"`
+	Kind        SdkKeyKind  `json:"kind"`
+	Environment Environment `json:"environment"`
+	Project     *Project    `json:"project"`
+	Prefix      string      `json:"prefix"`
+	CreatedBy   *User       `json:"created_by"`
+	CreatedAt   time.Time   `json:"created_at"`
+	ExpiresAt   *time.Time  `json:"expires_at,omitempty"`
+	RevokedAt   *time.Time  `json:"revoked_at,omitempty"`
+}
+
+type SimulateFeatureFlagInput struct {
+	FeatureFlagID string                   `json:"featureFlagId"`
+	Environment   Environment              `json:"environment"`
+	Proposed      *ProposedFlagConfigInput `json:"proposed"`
+	Format        ContextFormat            `json:"format"`
+	Contexts      *string                  `json:"contexts,omitempty"`
+	File          *graphql.Upload          `json:"file,omitempty"`
+}
+
+type SimulatedChange struct {
+	Row      int             `json:"row"`
+	Context  map[string]any  `json:"context"`
+	Current  *SimulatedValue `json:"current"`
+	Proposed *SimulatedValue `json:"proposed"`
+}
+
+type SimulatedValue struct {
+	Value   bool   `json:"value"`
+	Variant string `json:"variant"`
+	Reason  string `json:"reason"`
+}
+
+type TargetGroup struct {
+	Variant     string   `json:"variant"`
+	ContextKeys []string `json:"context_keys"`
+}
+
+type TargetGroupInput struct {
+	Variant     string   `json:"variant"`
+	ContextKeys []string `json:"contextKeys"`
+}
+
 type ToggleFeatureFlagInput struct {
 	FeatureFlagID string      `json:"featureFlagId"`
 	Environment   Environment `json:"environment"`
@@ -78,21 +569,55 @@ type ToggleFeatureFlagInput struct {
 }
 
 type ToggleState struct {
-	ID          string       `json:"id"`
-	Enabled     bool         `json:"enabled"`
-	Environment Environment  `json:"environment"`
-	FeatureFlag *FeatureFlag `json:"feature_flag"`
-	UpdatedAt   time.Time    `json:"updated_at"`
-	UpdatedBy   *User        `json:"updated_by"`
+	ID          string                      `json:"id"`
+	Enabled     bool                        `json:"enabled"`
+	Environment Environment                 `json:"environment"`
+	FeatureFlag *FeatureFlag                `json:"feature_flag"`
+	UpdatedAt   time.Time                   `json:"updated_at"`
+	UpdatedBy   *User                       `json:"updated_by"`
+	Targets     *IndividualTargetConnection `json:"targets"`
 }
 
-type UpdateFeatureFlagInput struct {
+type UpdateExperimentInput struct {
+	Name              *string                   `json:"name,omitempty"`
+	Hypothesis        *string                   `json:"hypothesis,omitempty"`
+	TrafficPercentage *int                      `json:"trafficPercentage,omitempty"`
+	Allocations       []*VariantAllocationInput `json:"allocations,omitempty"`
+	Metrics           []*ExperimentMetricInput  `json:"metrics,omitempty"`
+	LayerID           *string                   `json:"layerId,omitempty"`
+	ClearLayer        *bool                     `json:"clearLayer,omitempty"`
+}
+
+type UpdateExperimentLayerInput struct {
 	Name        *string `json:"name,omitempty"`
 	Description *string `json:"description,omitempty"`
 }
 
+type UpdateFeatureFlagInput struct {
+	Name             *string          `json:"name,omitempty"`
+	Description      *string          `json:"description,omitempty"`
+	Kind             *FlagKind        `json:"kind,omitempty"`
+	Tags             []string         `json:"tags,omitempty"`
+	OwnerID          *string          `json:"ownerId,omitempty"`
+	OwnerTeam        *string          `json:"ownerTeam,omitempty"`
+	RemovalDate      *time.Time       `json:"removalDate,omitempty"`
+	ClearRemovalDate *bool            `json:"clearRemovalDate,omitempty"`
+	Links            []*FlagLinkInput `json:"links,omitempty"`
+}
+
+type UpdateHoldoutInput struct {
+	Name       *string `json:"name,omitempty"`
+	Percentage *int    `json:"percentage,omitempty"`
+}
+
 type UpdateProjectInput struct {
-	Name *string `json:"name,omitempty"`
+	Name                  *string       `json:"name,omitempty"`
+	FlagKeyPattern        *string       `json:"flagKeyPattern,omitempty"`
+	FlagKeyMaxLength      *int          `json:"flagKeyMaxLength,omitempty"`
+	NewFlagDays           *int          `json:"newFlagDays,omitempty"`
+	StaleAfterDays        *int          `json:"staleAfterDays,omitempty"`
+	UnusedAfterDays       *int          `json:"unusedAfterDays,omitempty"`
+	ProtectedEnvironments []Environment `json:"protectedEnvironments,omitempty"`
 }
 
 type UpdateUserInput struct {
@@ -100,15 +625,211 @@ type UpdateUserInput struct {
 	Email *string `json:"email,omitempty"`
 }
 
+type UpdateWebhookInput struct {
+	URL        *string            `json:"url,omitempty"`
+	EventTypes []WebhookEventType `json:"eventTypes,omitempty"`
+	Active     *bool              `json:"active,omitempty"`
+	Secret     *string            `json:"secret,omitempty"`
+}
+
 type User struct {
 	ID                 string         `json:"id"`
 	Name               string         `json:"name"`
 	Email              string         `json:"email"`
+	Kind               UserKind       `json:"kind"`
+	CreatedBy          *User          `json:"created_by,omitempty"`
 	CreatedAt          time.Time      `json:"created_at"`
 	UpdatedAt          time.Time      `json:"updated_at"`
 	ProjectMemberships []*ProjectUser `json:"project_memberships"`
 }
 
+type VariantAllocation struct {
+	Variant string `json:"variant"`
+	Weight  int    `json:"weight"`
+}
+
+type VariantAllocationInput struct {
+	Variant string `json:"variant"`
+	Weight  int    `json:"weight"`
+}
+
+type VariantCount struct {
+	Variant string `json:"variant"`
+	Count   int    `json:"count"`
+}
+
+type VariantResult struct {
+	Variant                  string              `json:"variant"`
+	Exposures                int                 `json:"exposures"`
+	Conversions              int                 `json:"conversions"`
+	ConversionRate           float64             `json:"conversion_rate"`
+	ConfidenceInterval       *ConfidenceInterval `json:"confidence_interval"`
+	Lift                     *float64            `json:"lift,omitempty"`
+	PValue                   *float64            `json:"p_value,omitempty"`
+	Significant              bool                `json:"significant"`
+	ProbabilityToBeatControl *float64            `json:"probability_to_beat_control,omitempty"`
+}
+
+type Webhook struct {
+	ID         string                     `json:"id"`
+	Project    *Project                   `json:"project"`
+	URL        string                     `json:"url"`
+	EventTypes []WebhookEventType         `json:"event_types"`
+	Active     bool                       `json:"active"`
+	CreatedBy  *User                      `json:"created_by"`
+	CreatedAt  time.Time                  `json:"created_at"`
+	UpdatedAt  time.Time                  `json:"updated_at"`
+	Deliveries *WebhookDeliveryConnection `json:"deliveries"`
+}
+
+type WebhookDelivery struct {
+	ID             string                `json:"id"`
+	Webhook        *Webhook              `json:"webhook"`
+	EventID        string                `json:"event_id"`
+	EventType      WebhookEventType      `json:"event_type"`
+	Payload        string                `json:"payload"`
+	Status         WebhookDeliveryStatus `json:"status"`
+	Attempts       int                   `json:"attempts"`
+	NextAttemptAt  *time.Time            `json:"next_attempt_at,omitempty"`
+	LastAttemptAt  *time.Time            `json:"last_attempt_at,omitempty"`
+	ResponseStatus *int                  `json:"response_status,omitempty"`
+	LastError      *string               `json:"last_error,omitempty"`
+	CreatedAt      time.Time             `json:"created_at"`
+	DeliveredAt    *time.Time            `json:"delivered_at,omitempty"`
+	RedeliveryOf   *string               `json:"redelivery_of,omitempty"`
+}
+
+type WebhookDeliveryConnection struct {
+	Edges      []*WebhookDeliveryEdge `json:"edges"`
+	PageInfo   *PageInfo              `json:"pageInfo"`
+	TotalCount int                    `json:"totalCount"`
+}
+
+type WebhookDeliveryEdge struct {
+	Cursor string           `json:"cursor"`
+	Node   *WebhookDelivery `json:"node"`
+}
+
+type ChangeSetStatus string
+
+const (
+	ChangeSetStatusDraft           ChangeSetStatus = "DRAFT"
+	ChangeSetStatusPendingApproval ChangeSetStatus = "PENDING_APPROVAL"
+	ChangeSetStatusScheduled       ChangeSetStatus = "SCHEDULED"
+	ChangeSetStatusApplied         ChangeSetStatus = "APPLIED"
+	ChangeSetStatusReverted        ChangeSetStatus = "REVERTED"
+	ChangeSetStatusRejected        ChangeSetStatus = "REJECTED"
+	ChangeSetStatusFailed          ChangeSetStatus = "FAILED"
+)
+
+var AllChangeSetStatus = []ChangeSetStatus{
+	ChangeSetStatusDraft,
+	ChangeSetStatusPendingApproval,
+	ChangeSetStatusScheduled,
+	ChangeSetStatusApplied,
+	ChangeSetStatusReverted,
+	ChangeSetStatusRejected,
+	ChangeSetStatusFailed,
+}
+
+func (e ChangeSetStatus) IsValid() bool {
+	switch e {
+	case ChangeSetStatusDraft, ChangeSetStatusPendingApproval, ChangeSetStatusScheduled, ChangeSetStatusApplied, ChangeSetStatusReverted, ChangeSetStatusRejected, ChangeSetStatusFailed:
+		return true
+	}
+	return false
+}
+
+func (e ChangeSetStatus) String() string {
+	return string(e)
+}
+
+func (e *ChangeSetStatus) UnmarshalGQL(v any) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = ChangeSetStatus(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid ChangeSetStatus", str)
+	}
+	return nil
+}
+
+func (e ChangeSetStatus) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+func (e *ChangeSetStatus) UnmarshalJSON(b []byte) error {
+	s, err := strconv.Unquote(string(b))
+	if err != nil {
+		return err
+	}
+	return e.UnmarshalGQL(s)
+}
+
+func (e ChangeSetStatus) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	e.MarshalGQL(&buf)
+	return buf.Bytes(), nil
+}
+
+type ContextFormat string
+
+const (
+	ContextFormatJSON ContextFormat = "JSON"
+	ContextFormatCSV  ContextFormat = "CSV"
+)
+
+var AllContextFormat = []ContextFormat{
+	ContextFormatJSON,
+	ContextFormatCSV,
+}
+
+func (e ContextFormat) IsValid() bool {
+	switch e {
+	case ContextFormatJSON, ContextFormatCSV:
+		return true
+	}
+	return false
+}
+
+func (e ContextFormat) String() string {
+	return string(e)
+}
+
+func (e *ContextFormat) UnmarshalGQL(v any) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = ContextFormat(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid ContextFormat", str)
+	}
+	return nil
+}
+
+func (e ContextFormat) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+func (e *ContextFormat) UnmarshalJSON(b []byte) error {
+	s, err := strconv.Unquote(string(b))
+	if err != nil {
+		return err
+	}
+	return e.UnmarshalGQL(s)
+}
+
+func (e ContextFormat) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	e.MarshalGQL(&buf)
+	return buf.Bytes(), nil
+}
+
 type Environment string
 
 const (
@@ -166,50 +887,58 @@ func (e Environment) MarshalJSON() ([]byte, error) {
 	return buf.Bytes(), nil
 }
 
-type Role string
+type EvaluationStepKind string
 
 const (
-	RoleAdmin     Role = "ADMIN"
-	RoleDeveloper Role = "DEVELOPER"
-	RoleViewer    Role = "VIEWER"
+	EvaluationStepKindArchived    EvaluationStepKind = "ARCHIVED"
+	EvaluationStepKindOff         EvaluationStepKind = "OFF"
+	EvaluationStepKindTarget      EvaluationStepKind = "TARGET"
+	EvaluationStepKindHoldout     EvaluationStepKind = "HOLDOUT"
+	EvaluationStepKindExperiment  EvaluationStepKind = "EXPERIMENT"
+	EvaluationStepKindVariant     EvaluationStepKind = "VARIANT"
+	EvaluationStepKindFallthrough EvaluationStepKind = "FALLTHROUGH"
 )
 
-var AllRole = []Role{
-	RoleAdmin,
-	RoleDeveloper,
-	RoleViewer,
+var AllEvaluationStepKind = []EvaluationStepKind{
+	EvaluationStepKindArchived,
+	EvaluationStepKindOff,
+	EvaluationStepKindTarget,
+	EvaluationStepKindHoldout,
+	EvaluationStepKindExperiment,
+	EvaluationStepKindVariant,
+	EvaluationStepKindFallthrough,
 }
 
-func (e Role) IsValid() bool {
+func (e EvaluationStepKind) IsValid() bool {
 	switch e {
-	case RoleAdmin, RoleDeveloper, RoleViewer:
+	case EvaluationStepKindArchived, EvaluationStepKindOff, EvaluationStepKindTarget, EvaluationStepKindHoldout, EvaluationStepKindExperiment, EvaluationStepKindVariant, EvaluationStepKindFallthrough:
 		return true
 	}
 	return false
 }
 
-func (e Role) String() string {
+func (e EvaluationStepKind) String() string {
 	return string(e)
 }
 
-func (e *Role) UnmarshalGQL(v any) error {
+func (e *EvaluationStepKind) UnmarshalGQL(v any) error {
 	str, ok := v.(string)
 	if !ok {
 		return fmt.Errorf("enums must be strings")
 	}
 
-	*e = Role(str)
+	*e = EvaluationStepKind(str)
 	if !e.IsValid() {
-		return fmt.Errorf("%s is not a valid Role", str)
+		return fmt.Errorf("%s is not a valid EvaluationStepKind", str)
 	}
 	return nil
 }
 
-func (e Role) MarshalGQL(w io.Writer) {
+func (e EvaluationStepKind) MarshalGQL(w io.Writer) {
 	fmt.Fprint(w, strconv.Quote(e.String()))
 }
 
-func (e *Role) UnmarshalJSON(b []byte) error {
+func (e *EvaluationStepKind) UnmarshalJSON(b []byte) error {
 	s, err := strconv.Unquote(string(b))
 	if err != nil {
 		return err
@@ -217,7 +946,874 @@ func (e *Role) UnmarshalJSON(b []byte) error {
 	return e.UnmarshalGQL(s)
 }
 
-func (e Role) MarshalJSON() ([]byte, error) {
+func (e EvaluationStepKind) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	e.MarshalGQL(&buf)
+	return buf.Bytes(), nil
+}
+
+type ExperimentStatus string
+
+const (
+	ExperimentStatusDraft   ExperimentStatus = "DRAFT"
+	ExperimentStatusRunning ExperimentStatus = "RUNNING"
+	ExperimentStatusStopped ExperimentStatus = "STOPPED"
+)
+
+var AllExperimentStatus = []ExperimentStatus{
+	ExperimentStatusDraft,
+	ExperimentStatusRunning,
+	ExperimentStatusStopped,
+}
+
+func (e ExperimentStatus) IsValid() bool {
+	switch e {
+	case ExperimentStatusDraft, ExperimentStatusRunning, ExperimentStatusStopped:
+		return true
+	}
+	return false
+}
+
+func (e ExperimentStatus) String() string {
+	return string(e)
+}
+
+func (e *ExperimentStatus) UnmarshalGQL(v any) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = ExperimentStatus(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid ExperimentStatus", str)
+	}
+	return nil
+}
+
+func (e ExperimentStatus) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+func (e *ExperimentStatus) UnmarshalJSON(b []byte) error {
+	s, err := strconv.Unquote(string(b))
+	if err != nil {
+		return err
+	}
+	return e.UnmarshalGQL(s)
+}
+
+func (e ExperimentStatus) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	e.MarshalGQL(&buf)
+	return buf.Bytes(), nil
+}
+
+type FeatureFlagOrderField string
+
+const (
+	FeatureFlagOrderFieldKey       FeatureFlagOrderField = "KEY"
+	FeatureFlagOrderFieldName      FeatureFlagOrderField = "NAME"
+	FeatureFlagOrderFieldCreatedAt FeatureFlagOrderField = "CREATED_AT"
+	FeatureFlagOrderFieldUpdatedAt FeatureFlagOrderField = "UPDATED_AT"
+)
+
+var AllFeatureFlagOrderField = []FeatureFlagOrderField{
+	FeatureFlagOrderFieldKey,
+	FeatureFlagOrderFieldName,
+	FeatureFlagOrderFieldCreatedAt,
+	FeatureFlagOrderFieldUpdatedAt,
+}
+
+func (e FeatureFlagOrderField) IsValid() bool {
+	switch e {
+	case FeatureFlagOrderFieldKey, FeatureFlagOrderFieldName, FeatureFlagOrderFieldCreatedAt, FeatureFlagOrderFieldUpdatedAt:
+		return true
+	}
+	return false
+}
+
+func (e FeatureFlagOrderField) String() string {
+	return string(e)
+}
+
+func (e *FeatureFlagOrderField) UnmarshalGQL(v any) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = FeatureFlagOrderField(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid FeatureFlagOrderField", str)
+	}
+	return nil
+}
+
+func (e FeatureFlagOrderField) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+func (e *FeatureFlagOrderField) UnmarshalJSON(b []byte) error {
+	s, err := strconv.Unquote(string(b))
+	if err != nil {
+		return err
+	}
+	return e.UnmarshalGQL(s)
+}
+
+func (e FeatureFlagOrderField) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	e.MarshalGQL(&buf)
+	return buf.Bytes(), nil
+}
+
+type FlagHealthStatus string
+
+const (
+	FlagHealthStatusNew       FlagHealthStatus = "NEW"
+	FlagHealthStatusActive    FlagHealthStatus = "ACTIVE"
+	FlagHealthStatusRolledOut FlagHealthStatus = "ROLLED_OUT"
+	FlagHealthStatusStale     FlagHealthStatus = "STALE"
+	FlagHealthStatusUnused    FlagHealthStatus = "UNUSED"
+)
+
+var AllFlagHealthStatus = []FlagHealthStatus{
+	FlagHealthStatusNew,
+	FlagHealthStatusActive,
+	FlagHealthStatusRolledOut,
+	FlagHealthStatusStale,
+	FlagHealthStatusUnused,
+}
+
+func (e FlagHealthStatus) IsValid() bool {
+	switch e {
+	case FlagHealthStatusNew, FlagHealthStatusActive, FlagHealthStatusRolledOut, FlagHealthStatusStale, FlagHealthStatusUnused:
+		return true
+	}
+	return false
+}
+
+func (e FlagHealthStatus) String() string {
+	return string(e)
+}
+
+func (e *FlagHealthStatus) UnmarshalGQL(v any) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = FlagHealthStatus(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid FlagHealthStatus", str)
+	}
+	return nil
+}
+
+func (e FlagHealthStatus) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+func (e *FlagHealthStatus) UnmarshalJSON(b []byte) error {
+	s, err := strconv.Unquote(string(b))
+	if err != nil {
+		return err
+	}
+	return e.UnmarshalGQL(s)
+}
+
+func (e FlagHealthStatus) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	e.MarshalGQL(&buf)
+	return buf.Bytes(), nil
+}
+
+type FlagKind string
+
+const (
+	FlagKindRelease    FlagKind = "RELEASE"
+	FlagKindExperiment FlagKind = "EXPERIMENT"
+	FlagKindOps        FlagKind = "OPS"
+	FlagKindPermission FlagKind = "PERMISSION"
+)
+
+var AllFlagKind = []FlagKind{
+	FlagKindRelease,
+	FlagKindExperiment,
+	FlagKindOps,
+	FlagKindPermission,
+}
+
+func (e FlagKind) IsValid() bool {
+	switch e {
+	case FlagKindRelease, FlagKindExperiment, FlagKindOps, FlagKindPermission:
+		return true
+	}
+	return false
+}
+
+func (e FlagKind) String() string {
+	return string(e)
+}
+
+func (e *FlagKind) UnmarshalGQL(v any) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = FlagKind(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid FlagKind", str)
+	}
+	return nil
+}
+
+func (e FlagKind) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+func (e *FlagKind) UnmarshalJSON(b []byte) error {
+	s, err := strconv.Unquote(string(b))
+	if err != nil {
+		return err
+	}
+	return e.UnmarshalGQL(s)
+}
+
+func (e FlagKind) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	e.MarshalGQL(&buf)
+	return buf.Bytes(), nil
+}
+
+type FlagStateField string
+
+const (
+	FlagStateFieldConfigured FlagStateField = "CONFIGURED"
+	FlagStateFieldEnabled    FlagStateField = "ENABLED"
+	FlagStateFieldTargets    FlagStateField = "TARGETS"
+)
+
+var AllFlagStateField = []FlagStateField{
+	FlagStateFieldConfigured,
+	FlagStateFieldEnabled,
+	FlagStateFieldTargets,
+}
+
+func (e FlagStateField) IsValid() bool {
+	switch e {
+	case FlagStateFieldConfigured, FlagStateFieldEnabled, FlagStateFieldTargets:
+		return true
+	}
+	return false
+}
+
+func (e FlagStateField) String() string {
+	return string(e)
+}
+
+func (e *FlagStateField) UnmarshalGQL(v any) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = FlagStateField(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid FlagStateField", str)
+	}
+	return nil
+}
+
+func (e FlagStateField) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+func (e *FlagStateField) UnmarshalJSON(b []byte) error {
+	s, err := strconv.Unquote(string(b))
+	if err != nil {
+		return err
+	}
+	return e.UnmarshalGQL(s)
+}
+
+func (e FlagStateField) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	e.MarshalGQL(&buf)
+	return buf.Bytes(), nil
+}
+
+type FlagStatus string
+
+const (
+	FlagStatusActive   FlagStatus = "ACTIVE"
+	FlagStatusArchived FlagStatus = "ARCHIVED"
+)
+
+var AllFlagStatus = []FlagStatus{
+	FlagStatusActive,
+	FlagStatusArchived,
+}
+
+func (e FlagStatus) IsValid() bool {
+	switch e {
+	case FlagStatusActive, FlagStatusArchived:
+		return true
+	}
+	return false
+}
+
+func (e FlagStatus) String() string {
+	return string(e)
+}
+
+func (e *FlagStatus) UnmarshalGQL(v any) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = FlagStatus(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid FlagStatus", str)
+	}
+	return nil
+}
+
+func (e FlagStatus) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+func (e *FlagStatus) UnmarshalJSON(b []byte) error {
+	s, err := strconv.Unquote(string(b))
+	if err != nil {
+		return err
+	}
+	return e.UnmarshalGQL(s)
+}
+
+func (e FlagStatus) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	e.MarshalGQL(&buf)
+	return buf.Bytes(), nil
+}
+
+type InvitationStatus string
+
+const (
+	InvitationStatusPending  InvitationStatus = "PENDING"
+	InvitationStatusAccepted InvitationStatus = "ACCEPTED"
+	InvitationStatusDeclined InvitationStatus = "DECLINED"
+	InvitationStatusRevoked  InvitationStatus = "REVOKED"
+	InvitationStatusExpired  InvitationStatus = "EXPIRED"
+)
+
+var AllInvitationStatus = []InvitationStatus{
+	InvitationStatusPending,
+	InvitationStatusAccepted,
+	InvitationStatusDeclined,
+	InvitationStatusRevoked,
+	InvitationStatusExpired,
+}
+
+func (e InvitationStatus) IsValid() bool {
+	switch e {
+	case InvitationStatusPending, InvitationStatusAccepted, InvitationStatusDeclined, InvitationStatusRevoked, InvitationStatusExpired:
+		return true
+	}
+	return false
+}
+
+func (e InvitationStatus) String() string {
+	return string(e)
+}
+
+func (e *InvitationStatus) UnmarshalGQL(v any) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = InvitationStatus(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid InvitationStatus", str)
+	}
+	return nil
+}
+
+func (e InvitationStatus) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+func (e *InvitationStatus) UnmarshalJSON(b []byte) error {
+	s, err := strconv.Unquote(string(b))
+	if err != nil {
+		return err
+	}
+	return e.UnmarshalGQL(s)
+}
+
+func (e InvitationStatus) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	e.MarshalGQL(&buf)
+	return buf.Bytes(), nil
+}
+
+type OrderDirection string
+
+const (
+	OrderDirectionAsc  OrderDirection = "ASC"
+	OrderDirectionDesc OrderDirection = "DESC"
+)
+
+var AllOrderDirection = []OrderDirection{
+	OrderDirectionAsc,
+	OrderDirectionDesc,
+}
+
+func (e OrderDirection) IsValid() bool {
+	switch e {
+	case OrderDirectionAsc, OrderDirectionDesc:
+		return true
+	}
+	return false
+}
+
+func (e OrderDirection) String() string {
+	return string(e)
+}
+
+func (e *OrderDirection) UnmarshalGQL(v any) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = OrderDirection(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid OrderDirection", str)
+	}
+	return nil
+}
+
+func (e OrderDirection) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+func (e *OrderDirection) UnmarshalJSON(b []byte) error {
+	s, err := strconv.Unquote(string(b))
+	if err != nil {
+		return err
+	}
+	return e.UnmarshalGQL(s)
+}
+
+func (e OrderDirection) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	e.MarshalGQL(&buf)
+	return buf.Bytes(), nil
+}
+
+type PromotionStatus string
+
+const (
+	PromotionStatusPreview         PromotionStatus = "PREVIEW"
+	PromotionStatusPendingApproval PromotionStatus = "PENDING_APPROVAL"
+	PromotionStatusApplied         PromotionStatus = "APPLIED"
+	PromotionStatusRejected        PromotionStatus = "REJECTED"
+)
+
+var AllPromotionStatus = []PromotionStatus{
+	PromotionStatusPreview,
+	PromotionStatusPendingApproval,
+	PromotionStatusApplied,
+	PromotionStatusRejected,
+}
+
+func (e PromotionStatus) IsValid() bool {
+	switch e {
+	case PromotionStatusPreview, PromotionStatusPendingApproval, PromotionStatusApplied, PromotionStatusRejected:
+		return true
+	}
+	return false
+}
+
+func (e PromotionStatus) String() string {
+	return string(e)
+}
+
+func (e *PromotionStatus) UnmarshalGQL(v any) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = PromotionStatus(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid PromotionStatus", str)
+	}
+	return nil
+}
+
+func (e PromotionStatus) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+func (e *PromotionStatus) UnmarshalJSON(b []byte) error {
+	s, err := strconv.Unquote(string(b))
+	if err != nil {
+		return err
+	}
+	return e.UnmarshalGQL(s)
+}
+
+func (e PromotionStatus) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	e.MarshalGQL(&buf)
+	return buf.Bytes(), nil
+}
+
+type Role string
+
+const (
+	RoleAdmin     Role = "ADMIN"
+	RoleDeveloper Role = "DEVELOPER"
+	RoleViewer    Role = "VIEWER"
+)
+
+var AllRole = []Role{
+	RoleAdmin,
+	RoleDeveloper,
+	RoleViewer,
+}
+
+func (e Role) IsValid() bool {
+	switch e {
+	case RoleAdmin, RoleDeveloper, RoleViewer:
+		return true
+	}
+	return false
+}
+
+func (e Role) String() string {
+	return string(e)
+}
+
+func (e *Role) UnmarshalGQL(v any) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = Role(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid Role", str)
+	}
+	return nil
+}
+
+func (e Role) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+func (e *Role) UnmarshalJSON(b []byte) error {
+	s, err := strconv.Unquote(string(b))
+	if err != nil {
+		return err
+	}
+	return e.UnmarshalGQL(s)
+}
+
+func (e Role) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	e.MarshalGQL(&buf)
+	return buf.Bytes(), nil
+}
+
+type SdkKeyKind string
+
+const (
+	SdkKeyKindServer SdkKeyKind = "SERVER"
+	SdkKeyKindClient SdkKeyKind = "CLIENT"
+)
+
+var AllSdkKeyKind = []SdkKeyKind{
+	SdkKeyKindServer,
+	SdkKeyKindClient,
+}
+
+func (e SdkKeyKind) IsValid() bool {
+	switch e {
+	case SdkKeyKindServer, SdkKeyKindClient:
+		return true
+	}
+	return false
+}
+
+func (e SdkKeyKind) String() string {
+	return string(e)
+}
+
+func (e *SdkKeyKind) UnmarshalGQL(v any) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = SdkKeyKind(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid SdkKeyKind", str)
+	}
+	return nil
+}
+
+func (e SdkKeyKind) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+func (e *SdkKeyKind) UnmarshalJSON(b []byte) error {
+	s, err := strconv.Unquote(string(b))
+	if err != nil {
+		return err
+	}
+	return e.UnmarshalGQL(s)
+}
+
+func (e SdkKeyKind) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	e.MarshalGQL(&buf)
+	return buf.Bytes(), nil
+}
+
+type TokenScope string
+
+const (
+	TokenScopeRead   TokenScope = "READ"
+	TokenScopeToggle TokenScope = "TOGGLE"
+	TokenScopeAdmin  TokenScope = "ADMIN"
+)
+
+var AllTokenScope = []TokenScope{
+	TokenScopeRead,
+	TokenScopeToggle,
+	TokenScopeAdmin,
+}
+
+func (e TokenScope) IsValid() bool {
+	switch e {
+	case TokenScopeRead, TokenScopeToggle, TokenScopeAdmin:
+		return true
+	}
+	return false
+}
+
+func (e TokenScope) String() string {
+	return string(e)
+}
+
+func (e *TokenScope) UnmarshalGQL(v any) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = TokenScope(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid TokenScope", str)
+	}
+	return nil
+}
+
+func (e TokenScope) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+func (e *TokenScope) UnmarshalJSON(b []byte) error {
+	s, err := strconv.Unquote(string(b))
+	if err != nil {
+		return err
+	}
+	return e.UnmarshalGQL(s)
+}
+
+func (e TokenScope) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	e.MarshalGQL(&buf)
+	return buf.Bytes(), nil
+}
+
+type UserKind string
+
+const (
+	UserKindHuman          UserKind = "HUMAN"
+	UserKindServiceAccount UserKind = "SERVICE_ACCOUNT"
+)
+
+var AllUserKind = []UserKind{
+	UserKindHuman,
+	UserKindServiceAccount,
+}
+
+func (e UserKind) IsValid() bool {
+	switch e {
+	case UserKindHuman, UserKindServiceAccount:
+		return true
+	}
+	return false
+}
+
+func (e UserKind) String() string {
+	return string(e)
+}
+
+func (e *UserKind) UnmarshalGQL(v any) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = UserKind(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid UserKind", str)
+	}
+	return nil
+}
+
+func (e UserKind) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+func (e *UserKind) UnmarshalJSON(b []byte) error {
+	s, err := strconv.Unquote(string(b))
+	if err != nil {
+		return err
+	}
+	return e.UnmarshalGQL(s)
+}
+
+func (e UserKind) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	e.MarshalGQL(&buf)
+	return buf.Bytes(), nil
+}
+
+type WebhookDeliveryStatus string
+
+const (
+	WebhookDeliveryStatusPending   WebhookDeliveryStatus = "PENDING"
+	WebhookDeliveryStatusSucceeded WebhookDeliveryStatus = "SUCCEEDED"
+	WebhookDeliveryStatusDead      WebhookDeliveryStatus = "DEAD"
+)
+
+var AllWebhookDeliveryStatus = []WebhookDeliveryStatus{
+	WebhookDeliveryStatusPending,
+	WebhookDeliveryStatusSucceeded,
+	WebhookDeliveryStatusDead,
+}
+
+func (e WebhookDeliveryStatus) IsValid() bool {
+	switch e {
+	case WebhookDeliveryStatusPending, WebhookDeliveryStatusSucceeded, WebhookDeliveryStatusDead:
+		return true
+	}
+	return false
+}
+
+func (e WebhookDeliveryStatus) String() string {
+	return string(e)
+}
+
+func (e *WebhookDeliveryStatus) UnmarshalGQL(v any) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = WebhookDeliveryStatus(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid WebhookDeliveryStatus", str)
+	}
+	return nil
+}
+
+func (e WebhookDeliveryStatus) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+func (e *WebhookDeliveryStatus) UnmarshalJSON(b []byte) error {
+	s, err := strconv.Unquote(string(b))
+	if err != nil {
+		return err
+	}
+	return e.UnmarshalGQL(s)
+}
+
+func (e WebhookDeliveryStatus) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	e.MarshalGQL(&buf)
+	return buf.Bytes(), nil
+}
+
+type WebhookEventType string
+
+const (
+	WebhookEventTypeFlagCreated  WebhookEventType = "FLAG_CREATED"
+	WebhookEventTypeFlagUpdated  WebhookEventType = "FLAG_UPDATED"
+	WebhookEventTypeFlagArchived WebhookEventType = "FLAG_ARCHIVED"
+	WebhookEventTypeFlagRestored WebhookEventType = "FLAG_RESTORED"
+	WebhookEventTypeFlagDeleted  WebhookEventType = "FLAG_DELETED"
+	WebhookEventTypeFlagToggled  WebhookEventType = "FLAG_TOGGLED"
+)
+
+var AllWebhookEventType = []WebhookEventType{
+	WebhookEventTypeFlagCreated,
+	WebhookEventTypeFlagUpdated,
+	WebhookEventTypeFlagArchived,
+	WebhookEventTypeFlagRestored,
+	WebhookEventTypeFlagDeleted,
+	WebhookEventTypeFlagToggled,
+}
+
+func (e WebhookEventType) IsValid() bool {
+	switch e {
+	case WebhookEventTypeFlagCreated, WebhookEventTypeFlagUpdated, WebhookEventTypeFlagArchived, WebhookEventTypeFlagRestored, WebhookEventTypeFlagDeleted, WebhookEventTypeFlagToggled:
+		return true
+	}
+	return false
+}
+
+func (e WebhookEventType) String() string {
+	return string(e)
+}
+
+func (e *WebhookEventType) UnmarshalGQL(v any) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = WebhookEventType(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid WebhookEventType", str)
+	}
+	return nil
+}
+
+func (e WebhookEventType) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+func (e *WebhookEventType) UnmarshalJSON(b []byte) error {
+	s, err := strconv.Unquote(string(b))
+	if err != nil {
+		return err
+	}
+	return e.UnmarshalGQL(s)
+}
+
+func (e WebhookEventType) MarshalJSON() ([]byte, error) {
 	var buf bytes.Buffer
 	e.MarshalGQL(&buf)
 	return buf.Bytes(), nil
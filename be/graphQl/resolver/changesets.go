@@ -0,0 +1,178 @@
+package resolver
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/shubham-tomar/feature-toggler/db"
+	userctx "github.com/shubham-tomar/feature-toggler/graphQl/context"
+	"github.com/shubham-tomar/feature-toggler/graphQl/model"
+)
+
+const maxChangesPerChangeSet = 100
+
+// changeSetChanges validates the changes of a new change set: every change must do something, to a flag
+// of the project that is configured in the environment, and each flag changes at most once per environment
+func (r *Resolver) changeSetChanges(ctx context.Context, projectID string, inputs []*model.FlagChangeInput) ([]*model.FlagChange, error) {
+	if len(inputs) == 0 {
+		return nil, invalidInputError("a change set needs at least one change")
+	}
+	if len(inputs) > maxChangesPerChangeSet {
+		return nil, invalidInputError("a change set holds at most %d changes", maxChangesPerChangeSet)
+	}
+
+	type flagEnvironment struct {
+		flagID      string
+		environment model.Environment
+	}
+	seen := make(map[flagEnvironment]bool, len(inputs))
+	flags := map[string]*model.FeatureFlag{}
+	keys := 0
+
+	changes := make([]*model.FlagChange, 0, len(inputs))
+	for _, input := range inputs {
+		flag, ok := flags[input.FeatureFlagID]
+		if !ok {
+			var err error
+			flag, err = r.Storage.GetFeatureFlagByID(ctx, input.FeatureFlagID)
+			if err != nil {
+				return nil, fmt.Errorf("failed to get feature flag: %w", err)
+			}
+			flags[flag.ID] = flag
+		}
+
+		if flag.Project.ID != projectID {
+			return nil, invalidInputError("feature flag %s belongs to another project", flag.Key)
+		}
+		if flag.Status == model.FlagStatusArchived {
+			return nil, invalidInputError("feature flag %s is archived, restore it before changing it", flag.Key)
+		}
+		if stateIn(flag, input.Environment) == nil {
+			return nil, invalidInputError("feature flag %s is not configured in %s", flag.Key, input.Environment)
+		}
+
+		key := flagEnvironment{flag.ID, input.Environment}
+		if seen[key] {
+			return nil, invalidInputError("feature flag %s changes more than once in %s", flag.Key, input.Environment)
+		}
+		seen[key] = true
+
+		addTargets, removeTargets, err := targetChanges(flag, input.AddTargets, input.RemoveTargets)
+		if err != nil {
+			return nil, err
+		}
+		change := &model.FlagChange{
+			FeatureFlag:   flag,
+			Environment:   input.Environment,
+			Enabled:       input.Enabled,
+			AddTargets:    addTargets,
+			RemoveTargets: removeTargets,
+		}
+
+		for _, group := range addTargets {
+			keys += len(group.ContextKeys)
+		}
+		keys += len(removeTargets)
+		if keys > maxTargetKeysPerRequest {
+			return nil, invalidInputError("at most %d context keys can change per change set", maxTargetKeysPerRequest)
+		}
+		if change.Enabled == nil && len(change.AddTargets) == 0 && len(change.RemoveTargets) == 0 {
+			return nil, invalidInputError("the change of feature flag %s in %s changes nothing", flag.Key, input.Environment)
+		}
+
+		changes = append(changes, change)
+	}
+
+	return changes, nil
+}
+
+// submitChangeSet applies a draft or failed change set now, schedules it, or submits it for approval
+// when it changes a protected environment
+func (r *Resolver) submitChangeSet(ctx context.Context, changeSet *model.ChangeSet, at *time.Time) (*model.ChangeSet, error) {
+	if at != nil && !at.After(time.Now()) {
+		return nil, invalidInputError("change sets can only be scheduled in the future")
+	}
+
+	project, err := r.Storage.GetProjectByID(ctx, changeSet.Project.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get project: %w", err)
+	}
+
+	expected := []model.ChangeSetStatus{model.ChangeSetStatusDraft, model.ChangeSetStatusFailed}
+	changeSet.SubmittedBy = userctx.GetUser(ctx)
+	changeSet.DecidedBy = nil
+	changeSet.ScheduledAt = at
+	changeSet.Error = nil
+
+	switch {
+	case changesProtected(project, changeSet):
+		changeSet.Status = model.ChangeSetStatusPendingApproval
+		return r.updateChangeSetStatus(ctx, changeSet, expected)
+	case at != nil:
+		changeSet.Status = model.ChangeSetStatusScheduled
+		return r.updateChangeSetStatus(ctx, changeSet, expected)
+	}
+
+	return r.applyChangeSet(ctx, changeSet, expected)
+}
+
+// changesProtected tells whether a change set changes one of the protected environments of the project
+func changesProtected(project *model.Project, changeSet *model.ChangeSet) bool {
+	return slices.ContainsFunc(changeSet.Changes, func(change *model.FlagChange) bool {
+		return slices.Contains(project.ProtectedEnvironments, change.Environment)
+	})
+}
+
+// pendingChangeSet loads a change set waiting for approval that the current user may decide on
+func (r *Resolver) pendingChangeSet(ctx context.Context, id string, approving bool) (*model.ChangeSet, error) {
+	changeSet, err := r.changeSetIn(ctx, id, model.ChangeSetStatusPendingApproval)
+	if err != nil {
+		return nil, err
+	}
+
+	changeSet.DecidedBy, err = r.decider(ctx, changeSet.Project.ID, changeSet.SubmittedBy, approving, "change sets")
+	if err != nil {
+		return nil, err
+	}
+	return changeSet, nil
+}
+
+// changeSetIn loads a change set that must be in one of the given statuses
+func (r *Resolver) changeSetIn(ctx context.Context, id string, statuses ...model.ChangeSetStatus) (*model.ChangeSet, error) {
+	changeSet, err := r.Storage.GetChangeSetByID(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get change set: %w", err)
+	}
+
+	if !slices.Contains(statuses, changeSet.Status) {
+		return nil, conflictError("change set is %s", changeSet.Status)
+	}
+	return changeSet, nil
+}
+
+func (r *Resolver) updateChangeSetStatus(ctx context.Context, changeSet *model.ChangeSet, expected []model.ChangeSetStatus) (*model.ChangeSet, error) {
+	if err := r.Storage.UpdateChangeSetStatus(ctx, changeSet, expected); err != nil {
+		if errors.Is(err, db.ErrConflict) {
+			return nil, conflictError("change set was changed in the meantime")
+		}
+		return nil, fmt.Errorf("failed to update change set: %w", err)
+	}
+	return changeSet, nil
+}
+
+func (r *Resolver) applyChangeSet(ctx context.Context, changeSet *model.ChangeSet, expected []model.ChangeSetStatus) (*model.ChangeSet, error) {
+	if err := r.Storage.ApplyChangeSet(ctx, changeSet, expected); err != nil {
+		switch {
+		case errors.Is(err, db.ErrNotApplicable):
+			// Nothing was applied, the change set stays as it was
+			return nil, invalidInputError("%s", err)
+		case errors.Is(err, db.ErrConflict):
+			return nil, conflictError("change set was changed in the meantime")
+		}
+		return nil, fmt.Errorf("failed to apply change set: %w", err)
+	}
+	return changeSet, nil
+}
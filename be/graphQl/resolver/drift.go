@@ -0,0 +1,61 @@
+package resolver
+
+import (
+	"context"
+	"maps"
+
+	"github.com/shubham-tomar/feature-toggler/graphQl/model"
+)
+
+// flagDrift compares the state of a flag in two environments, it returns nil when they match
+func (r *Resolver) flagDrift(ctx context.Context, flag *model.FeatureFlag, a, b model.Environment) (*model.FlagDrift, error) {
+	stateA, stateB := stateIn(flag, a), stateIn(flag, b)
+	if stateA == nil && stateB == nil {
+		return nil, nil
+	}
+
+	targetsA, err := r.flagTargets(ctx, flag.ID, a)
+	if err != nil {
+		return nil, err
+	}
+	targetsB, err := r.flagTargets(ctx, flag.ID, b)
+	if err != nil {
+		return nil, err
+	}
+
+	var fields []model.FlagStateField
+	switch {
+	case stateA == nil || stateB == nil:
+		fields = append(fields, model.FlagStateFieldConfigured)
+	case stateA.Enabled != stateB.Enabled:
+		fields = append(fields, model.FlagStateFieldEnabled)
+	}
+	if !maps.Equal(targetsA[flag.ID], targetsB[flag.ID]) {
+		fields = append(fields, model.FlagStateFieldTargets)
+	}
+
+	if len(fields) == 0 {
+		return nil, nil
+	}
+
+	return &model.FlagDrift{
+		FeatureFlag: flag,
+		Fields:      fields,
+		A:           environmentState(stateA, len(targetsA[flag.ID])),
+		B:           environmentState(stateB, len(targetsB[flag.ID])),
+	}, nil
+}
+
+func environmentState(state *model.ToggleState, targets int) *model.FlagEnvironmentState {
+	if state == nil {
+		return &model.FlagEnvironmentState{Targets: targets}
+	}
+
+	return &model.FlagEnvironmentState{
+		Configured: true,
+		Enabled:    &state.Enabled,
+		Targets:    targets,
+		UpdatedAt:  &state.UpdatedAt,
+		UpdatedBy:  state.UpdatedBy,
+	}
+}
@@ -0,0 +1,33 @@
+package resolver
+
+import (
+	"fmt"
+
+	"github.com/vektah/gqlparser/v2/gqlerror"
+)
+
+// Error codes set in the extensions of errors clients are expected to handle
+const (
+	codeConflict     = "CONFLICT"
+	codeForbidden    = "FORBIDDEN"
+	codeInvalidInput = "BAD_USER_INPUT"
+)
+
+func conflictError(format string, args ...any) error {
+	return codedError(codeConflict, format, args...)
+}
+
+func forbiddenError(format string, args ...any) error {
+	return codedError(codeForbidden, format, args...)
+}
+
+func invalidInputError(format string, args ...any) error {
+	return codedError(codeInvalidInput, format, args...)
+}
+
+func codedError(code, format string, args ...any) error {
+	return &gqlerror.Error{
+		Message:    fmt.Sprintf(format, args...),
+		Extensions: map[string]any{"code": code},
+	}
+}
@@ -0,0 +1,52 @@
+package resolver
+
+import (
+	"sort"
+	"time"
+
+	"github.com/shubham-tomar/feature-toggler/db"
+	"github.com/shubham-tomar/feature-toggler/graphQl/model"
+)
+
+// Window of evaluation stats when no start is given
+const defaultEvaluationWindow = 30 * 24 * time.Hour
+
+// summarizeEvaluations folds the stored buckets, which are split by environment and variant, into stats
+func summarizeEvaluations(counts []*db.EvaluationCount, uniqueContexts int) *model.EvaluationStats {
+	stats := &model.EvaluationStats{
+		UniqueContexts: uniqueContexts,
+		Variants:       []*model.VariantCount{},
+		Buckets:        []*model.EvaluationBucket{},
+	}
+
+	variants := map[string]*model.VariantCount{}
+	for _, c := range counts {
+		stats.Total += c.Count
+
+		if stats.LastEvaluatedAt == nil || c.LastEvaluatedAt.After(*stats.LastEvaluatedAt) {
+			last := c.LastEvaluatedAt
+			stats.LastEvaluatedAt = &last
+		}
+
+		v, ok := variants[c.Variant]
+		if !ok {
+			v = &model.VariantCount{Variant: c.Variant}
+			variants[c.Variant] = v
+			stats.Variants = append(stats.Variants, v)
+		}
+		v.Count += c.Count
+
+		// Counts come oldest first, so a bucket only ever continues the last one
+		if n := len(stats.Buckets); n > 0 && stats.Buckets[n-1].Start.Equal(c.BucketStart) {
+			stats.Buckets[n-1].Count += c.Count
+		} else {
+			stats.Buckets = append(stats.Buckets, &model.EvaluationBucket{Start: c.BucketStart, Count: c.Count})
+		}
+	}
+
+	sort.SliceStable(stats.Variants, func(i, j int) bool {
+		return stats.Variants[i].Count > stats.Variants[j].Count
+	})
+
+	return stats
+}
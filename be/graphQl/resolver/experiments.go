@@ -0,0 +1,224 @@
+package resolver
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/shubham-tomar/feature-toggler/evaluation"
+	"github.com/shubham-tomar/feature-toggler/experiments"
+	"github.com/shubham-tomar/feature-toggler/graphQl/model"
+)
+
+const (
+	maxExperimentMetrics = 10
+	maxMetricKeyLength   = 64
+	maxHoldoutPercentage = 50
+)
+
+// Boolean flags serve two variants, experiments split traffic between them and targets pin one
+var flagVariants = []string{evaluation.VariantOff, evaluation.VariantOn}
+
+func defaultAllocations() []*model.VariantAllocation {
+	return []*model.VariantAllocation{
+		{Variant: evaluation.VariantOff, Weight: 50},
+		{Variant: evaluation.VariantOn, Weight: 50},
+	}
+}
+
+func experimentAllocations(inputs []*model.VariantAllocationInput) ([]*model.VariantAllocation, error) {
+	allocations := make([]*model.VariantAllocation, 0, len(inputs))
+	total := 0
+	for _, input := range inputs {
+		if !slices.Contains(flagVariants, input.Variant) {
+			return nil, invalidInputError("variant %q does not exist, flags serve %s", input.Variant, strings.Join(flagVariants, " and "))
+		}
+		if slices.ContainsFunc(allocations, func(a *model.VariantAllocation) bool { return a.Variant == input.Variant }) {
+			return nil, invalidInputError("variant %q is allocated twice", input.Variant)
+		}
+		if input.Weight < 1 || input.Weight > 100 {
+			return nil, invalidInputError("the weight of variant %q must be between 1 and 100", input.Variant)
+		}
+		total += input.Weight
+		allocations = append(allocations, &model.VariantAllocation{Variant: input.Variant, Weight: input.Weight})
+	}
+
+	if total != 100 {
+		return nil, invalidInputError("allocation weights must add up to 100, not %d", total)
+	}
+	return allocations, nil
+}
+
+func experimentMetrics(inputs []*model.ExperimentMetricInput) ([]*model.ExperimentMetric, error) {
+	if len(inputs) == 0 {
+		return nil, invalidInputError("an experiment needs at least one metric")
+	}
+	if len(inputs) > maxExperimentMetrics {
+		return nil, invalidInputError("an experiment can have at most %d metrics", maxExperimentMetrics)
+	}
+
+	metrics := make([]*model.ExperimentMetric, 0, len(inputs))
+	for _, input := range inputs {
+		key := strings.TrimSpace(input.Key)
+		if key == "" || len(key) > maxMetricKeyLength {
+			return nil, invalidInputError("metric keys must be between 1 and %d characters", maxMetricKeyLength)
+		}
+		if slices.ContainsFunc(metrics, func(m *model.ExperimentMetric) bool { return m.Key == key }) {
+			return nil, invalidInputError("metric %q is listed twice", key)
+		}
+
+		metric := &model.ExperimentMetric{Key: key, Name: key}
+		if input.Name != nil && strings.TrimSpace(*input.Name) != "" {
+			metric.Name = strings.TrimSpace(*input.Name)
+		}
+		metrics = append(metrics, metric)
+	}
+
+	return metrics, nil
+}
+
+func trafficPercentage(percentage int) error {
+	if percentage < 1 || percentage > 100 {
+		return invalidInputError("traffic percentage must be between 1 and 100")
+	}
+	return nil
+}
+
+func holdoutPercentage(percentage int) error {
+	if percentage < 1 || percentage > maxHoldoutPercentage {
+		return invalidInputError("holdout percentage must be between 1 and %d", maxHoldoutPercentage)
+	}
+	return nil
+}
+
+// layerRanges returns the sorted ranges of a layer taken by the experiments running in an environment
+func layerRanges(experiments []*model.Experiment, environment model.Environment) [][2]int {
+	var ranges [][2]int
+	for _, experiment := range experiments {
+		if experiment.Status == model.ExperimentStatusRunning && experiment.Environment == environment && experiment.LayerOffset != nil {
+			ranges = append(ranges, [2]int{*experiment.LayerOffset, *experiment.LayerOffset + experiment.TrafficPercentage})
+		}
+	}
+	slices.SortFunc(ranges, func(a, b [2]int) int { return a[0] - b[0] })
+	return ranges
+}
+
+// layerOffset finds the first free range of a layer wide enough for the traffic of an experiment
+func layerOffset(ranges [][2]int, traffic int) (int, bool) {
+	offset := 0
+	for _, taken := range ranges {
+		if taken[0]-offset >= traffic {
+			return offset, true
+		}
+		offset = max(offset, taken[1])
+	}
+	return offset, 100-offset >= traffic
+}
+
+// freePercentage is the widest free range of a layer
+func freePercentage(ranges [][2]int) int {
+	free, offset := 0, 0
+	for _, taken := range ranges {
+		free = max(free, taken[0]-offset)
+		offset = max(offset, taken[1])
+	}
+	return max(free, 100-offset)
+}
+
+// experimentLayer loads a layer an experiment on a flag of the project can join
+func (r *Resolver) experimentLayer(ctx context.Context, id, projectID string) (*model.ExperimentLayer, error) {
+	layer, err := r.Storage.GetExperimentLayerByID(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get experiment layer: %w", err)
+	}
+	if layer.Project.ID != projectID {
+		return nil, invalidInputError("experiment layer %q belongs to another project", layer.Name)
+	}
+	return layer, nil
+}
+
+// requireNoRunningExperiments keeps holdouts fixed while experiments of the project run
+func (r *Resolver) requireNoRunningExperiments(ctx context.Context, projectID string) error {
+	status := model.ExperimentStatusRunning
+	running, err := r.Storage.GetProjectExperiments(ctx, projectID, &status)
+	if err != nil {
+		return fmt.Errorf("failed to get experiments: %w", err)
+	}
+	if len(running) > 0 {
+		return conflictError("holdouts cannot change while experiment %q is running", running[0].Name)
+	}
+	return nil
+}
+
+// controlVariant is the variant the others are compared with, off whenever it is allocated
+func controlVariant(experiment *model.Experiment) string {
+	for _, allocation := range experiment.Allocations {
+		if allocation.Variant == evaluation.VariantOff {
+			return allocation.Variant
+		}
+	}
+	return experiment.Allocations[0].Variant
+}
+
+// experimentResults compares the conversions of every allocated variant with the control, per metric
+func (r *Resolver) experimentResults(ctx context.Context, experiment *model.Experiment, confidenceLevel float64) (*model.ExperimentResults, error) {
+	exposures, err := r.Storage.CountExposures(ctx, experiment.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count exposures: %w", err)
+	}
+
+	results := &model.ExperimentResults{
+		ConfidenceLevel: confidenceLevel,
+		Control:         controlVariant(experiment),
+		Metrics:         make([]*model.MetricResult, 0, len(experiment.Metrics)),
+	}
+	for _, n := range exposures {
+		results.Exposures += n
+	}
+
+	for _, metric := range experiment.Metrics {
+		conversions, err := r.Storage.CountConversions(ctx, experiment, metric.Key)
+		if err != nil {
+			return nil, fmt.Errorf("failed to count conversions: %w", err)
+		}
+
+		var control experiments.Sample
+		samples := make([]experiments.Sample, 0, len(experiment.Allocations))
+		for _, allocation := range experiment.Allocations {
+			sample := experiments.Sample{
+				Variant:     allocation.Variant,
+				Exposures:   exposures[allocation.Variant],
+				Conversions: conversions[allocation.Variant],
+			}
+			if sample.Variant == results.Control {
+				control = sample
+			}
+			samples = append(samples, sample)
+		}
+
+		results.Metrics = append(results.Metrics, &model.MetricResult{
+			Metric:   metric,
+			Variants: experiments.Compare(control, samples, confidenceLevel),
+		})
+	}
+
+	return results, nil
+}
+
+// experimentConflict explains why an experiment could not change status
+func (r *Resolver) experimentConflict(ctx context.Context, id string, required model.ExperimentStatus, action string) error {
+	experiment, err := r.Storage.GetExperimentByID(ctx, id)
+	if err != nil {
+		return fmt.Errorf("failed to get experiment: %w", err)
+	}
+
+	if experiment.Status != required {
+		return conflictError("experiment %q is %s, only %s experiments can be %s",
+			experiment.Name, strings.ToLower(string(experiment.Status)), strings.ToLower(string(required)), action)
+	}
+	if experiment.Layer != nil {
+		return conflictError("another experiment is already running on this flag in %s, or the range of its layer was taken", experiment.Environment)
+	}
+	return conflictError("another experiment is already running on this flag in %s", experiment.Environment)
+}
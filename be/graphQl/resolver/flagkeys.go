@@ -0,0 +1,60 @@
+package resolver
+
+import (
+	"fmt"
+	"regexp"
+
+	"github.com/shubham-tomar/feature-toggler/graphQl/model"
+)
+
+// Projects use these rules for flag keys unless they configure their own
+const (
+	defaultFlagKeyPattern   = `^[a-zA-Z0-9][a-zA-Z0-9._-]*$`
+	defaultFlagKeyMaxLength = 64
+	maxFlagKeyMaxLength     = 255
+)
+
+// validateFlagKey checks a new flag key against the rules of its project
+func validateFlagKey(project *model.Project, key string) error {
+	pattern, maxLength := defaultFlagKeyPattern, defaultFlagKeyMaxLength
+	if project.FlagKeyPattern != nil {
+		pattern = *project.FlagKeyPattern
+	}
+	if project.FlagKeyMaxLength != nil {
+		maxLength = *project.FlagKeyMaxLength
+	}
+
+	if key == "" {
+		return invalidInputError("flag key must not be empty")
+	}
+
+	if len(key) > maxLength {
+		return invalidInputError("flag key %q is longer than %d characters", key, maxLength)
+	}
+
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return fmt.Errorf("project has an invalid flag key pattern: %w", err)
+	}
+
+	if !re.MatchString(key) {
+		return invalidInputError("flag key %q does not match the pattern %s", key, pattern)
+	}
+
+	return nil
+}
+
+// validateFlagKeyRules checks the key rules a project is configured with
+func validateFlagKeyRules(pattern *string, maxLength *int) error {
+	if pattern != nil {
+		if _, err := regexp.Compile(*pattern); err != nil {
+			return invalidInputError("invalid flag key pattern: %v", err)
+		}
+	}
+
+	if maxLength != nil && (*maxLength < 1 || *maxLength > maxFlagKeyMaxLength) {
+		return invalidInputError("flag key length must be between 1 and %d", maxFlagKeyMaxLength)
+	}
+
+	return nil
+}
@@ -0,0 +1,20 @@
+package resolver
+
+import "github.com/shubham-tomar/feature-toggler/graphQl/model"
+
+// Statuses listed by staleFlags when none are asked for
+var defaultStaleStatuses = []model.FlagHealthStatus{model.FlagHealthStatusStale, model.FlagHealthStatusUnused}
+
+// healthThreshold applies an update of a flag health threshold, zero restores the default
+func healthThreshold(current, update *int, field string) (*int, error) {
+	if update == nil {
+		return current, nil
+	}
+	if *update < 0 {
+		return nil, invalidInputError("%s must not be negative", field)
+	}
+	if *update == 0 {
+		return nil, nil
+	}
+	return update, nil
+}
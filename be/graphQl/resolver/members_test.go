@@ -0,0 +1,89 @@
+package resolver
+
+import (
+	"testing"
+
+	"github.com/shubham-tomar/feature-toggler/graphQl/model"
+)
+
+func TestMembershipChangesNeedProjectAdmin(t *testing.T) {
+	r := newTestResolver(t)
+	mutation := r.Mutation()
+
+	_, adminCtx := newTestUser(t, r, "admin")
+	developer, developerCtx := newTestUser(t, r, "developer")
+	outsider, outsiderCtx := newTestUser(t, r, "outsider")
+
+	project, err := mutation.CreateProject(adminCtx, "checkout")
+	if err != nil {
+		t.Fatal(err)
+	}
+	membership, err := mutation.AddProjectMember(adminCtx, model.AddProjectMemberInput{
+		ProjectID: project.ID, UserID: developer.ID, Role: model.RoleDeveloper,
+	})
+	if err != nil {
+		t.Fatalf("an admin could not add a member: %v", err)
+	}
+	invitation, err := mutation.InviteProjectMember(adminCtx, model.InviteProjectMemberInput{
+		ProjectID: project.ID, Email: "new@example.com", Role: model.RoleViewer,
+	})
+	if err != nil {
+		t.Fatalf("an admin could not invite: %v", err)
+	}
+
+	tests := []struct {
+		name string
+		call func() error
+	}{
+		{"developer makes themselves admin", func() error {
+			_, err := mutation.UpdateProjectMember(developerCtx, membership.ID, model.RoleAdmin)
+			return err
+		}},
+		{"developer adds an outsider", func() error {
+			_, err := mutation.AddProjectMember(developerCtx, model.AddProjectMemberInput{
+				ProjectID: project.ID, UserID: outsider.ID, Role: model.RoleAdmin,
+			})
+			return err
+		}},
+		{"outsider adds themselves", func() error {
+			_, err := mutation.AddProjectMember(outsiderCtx, model.AddProjectMemberInput{
+				ProjectID: project.ID, UserID: outsider.ID, Role: model.RoleAdmin,
+			})
+			return err
+		}},
+		{"developer removes a member", func() error {
+			_, err := mutation.RemoveProjectMember(developerCtx, membership.ID)
+			return err
+		}},
+		{"developer invites", func() error {
+			_, err := mutation.InviteProjectMember(developerCtx, model.InviteProjectMemberInput{
+				ProjectID: project.ID, Email: "friend@example.com", Role: model.RoleAdmin,
+			})
+			return err
+		}},
+		{"outsider revokes an invitation", func() error {
+			_, err := mutation.RevokeInvitation(outsiderCtx, invitation.ID)
+			return err
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.call(); errorCode(err) != codeForbidden {
+				t.Errorf("got error %v, want a %s error", err, codeForbidden)
+			}
+		})
+	}
+
+	// Nothing the rejected calls tried went through
+	current, err := r.Storage.GetProjectMember(adminCtx, membership.ID)
+	if err != nil {
+		t.Fatalf("the member was removed: %v", err)
+	}
+	if current.Role != model.RoleDeveloper {
+		t.Errorf("the member became %s", current.Role)
+	}
+	if m, err := r.projectMembership(adminCtx, project.ID, outsider.ID); err != nil || m != nil {
+		t.Errorf("the outsider joined the project: %v, %v", m, err)
+	}
+}
@@ -0,0 +1,100 @@
+package resolver
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"net/url"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/shubham-tomar/feature-toggler/db"
+	"github.com/shubham-tomar/feature-toggler/graphQl/model"
+	"github.com/shubham-tomar/feature-toggler/health"
+)
+
+const (
+	maxFlagTags      = 20
+	maxFlagTagLength = 50
+	maxFlagLinks     = 20
+	// Flags due for removal within this many days get a warning ahead of time
+	removalWarningDays = 7
+)
+
+// normalizeTags trims and lowercases tags so that filters match regardless of spelling, duplicates are dropped
+func normalizeTags(tags []string) ([]string, error) {
+	normalized := make([]string, 0, len(tags))
+	for _, tag := range tags {
+		tag = strings.ToLower(strings.TrimSpace(tag))
+		if tag == "" {
+			return nil, invalidInputError("tags must not be empty")
+		}
+		if len(tag) > maxFlagTagLength {
+			return nil, invalidInputError("tag %q is longer than %d characters", tag, maxFlagTagLength)
+		}
+		if !slices.Contains(normalized, tag) {
+			normalized = append(normalized, tag)
+		}
+	}
+
+	if len(normalized) > maxFlagTags {
+		return nil, invalidInputError("a flag can have at most %d tags", maxFlagTags)
+	}
+
+	slices.Sort(normalized)
+	return normalized, nil
+}
+
+func flagLinks(inputs []*model.FlagLinkInput) ([]*model.FlagLink, error) {
+	if len(inputs) > maxFlagLinks {
+		return nil, invalidInputError("a flag can have at most %d links", maxFlagLinks)
+	}
+
+	links := make([]*model.FlagLink, 0, len(inputs))
+	for _, input := range inputs {
+		u, err := url.Parse(strings.TrimSpace(input.URL))
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return nil, invalidInputError("link %q must be an absolute http or https URL", input.URL)
+		}
+
+		link := &model.FlagLink{URL: u.String()}
+		if input.Title != nil && strings.TrimSpace(*input.Title) != "" {
+			title := strings.TrimSpace(*input.Title)
+			link.Title = &title
+		}
+		links = append(links, link)
+	}
+
+	return links, nil
+}
+
+// flagOwner returns a stub of the owning user after checking that the user exists
+func (r *Resolver) flagOwner(ctx context.Context, ownerID string) (*model.User, error) {
+	if _, err := r.Storage.GetUserByID(ctx, ownerID); errors.Is(err, db.ErrNotFound) {
+		return nil, invalidInputError("owner %s does not exist", ownerID)
+	} else if err != nil {
+		return nil, fmt.Errorf("failed to get owner: %w", err)
+	}
+
+	return &model.User{ID: ownerID}, nil
+}
+
+func flagWarnings(flag *model.FeatureFlag, now time.Time) []string {
+	warnings := []string{}
+	if flag.RemovalDate == nil {
+		return warnings
+	}
+
+	date := flag.RemovalDate.Format(time.DateOnly)
+	days := int(math.Ceil(flag.RemovalDate.Sub(now).Hours() / 24))
+	switch {
+	case health.Expired(flag, now):
+		warnings = append(warnings, fmt.Sprintf("past its removal date %s, remove it from the code and archive it", date))
+	case days < removalWarningDays:
+		warnings = append(warnings, fmt.Sprintf("due for removal on %s, in %d days", date, days))
+	}
+
+	return warnings
+}
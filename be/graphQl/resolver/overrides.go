@@ -0,0 +1,52 @@
+package resolver
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/shubham-tomar/feature-toggler/db"
+	"github.com/shubham-tomar/feature-toggler/graphQl/model"
+)
+
+const (
+	maxOverridesPerToken  = 50
+	defaultOverrideHours  = 24
+	maxOverrideTokenHours = 720
+)
+
+// overrideFlags checks the overrides of a new token and returns the variants by flag key
+func (r *Resolver) overrideFlags(ctx context.Context, projectID string, input []*model.FlagOverrideInput) (map[string]string, error) {
+	if len(input) == 0 {
+		return nil, invalidInputError("at least one override is required")
+	}
+	if len(input) > maxOverridesPerToken {
+		return nil, invalidInputError("a token overrides at most %d flags", maxOverridesPerToken)
+	}
+
+	flags := make(map[string]string, len(input))
+	for _, override := range input {
+		if _, ok := flags[override.FlagKey]; ok {
+			return nil, invalidInputError("feature flag %s is overridden twice", override.FlagKey)
+		}
+		if !slices.Contains(flagVariants, override.Variant) {
+			return nil, invalidInputError("variant %q does not exist, flags serve %s", override.Variant, strings.Join(flagVariants, " and "))
+		}
+
+		flag, err := r.Storage.GetFeatureFlagByKey(ctx, projectID, override.FlagKey)
+		if errors.Is(err, db.ErrNotFound) {
+			return nil, invalidInputError("feature flag %s does not exist", override.FlagKey)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to get feature flag: %w", err)
+		}
+		if flag.Status == model.FlagStatusArchived {
+			return nil, invalidInputError("feature flag %s is archived", override.FlagKey)
+		}
+
+		flags[override.FlagKey] = override.Variant
+	}
+	return flags, nil
+}
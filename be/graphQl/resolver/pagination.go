@@ -0,0 +1,75 @@
+package resolver
+
+import (
+	"encoding/base64"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/shubham-tomar/feature-toggler/db"
+	"github.com/shubham-tomar/feature-toggler/graphQl/model"
+)
+
+const (
+	defaultPageSize = 20
+	maxPageSize     = 100
+	cursorPrefix    = "offset:"
+)
+
+// pageFor turns Relay pagination arguments into a storage page. Cursors are opaque to
+// clients, so the offset they encode can be replaced by keys later without breaking them.
+func pageFor(first *int, after *string) (db.Page, error) {
+	page := db.Page{Limit: defaultPageSize}
+
+	if first != nil {
+		if *first < 1 || *first > maxPageSize {
+			return page, fmt.Errorf("first must be between 1 and %d", maxPageSize)
+		}
+		page.Limit = *first
+	}
+
+	if after != nil && *after != "" {
+		offset, err := decodeCursor(*after)
+		if err != nil {
+			return page, err
+		}
+		page.Offset = offset + 1
+	}
+
+	return page, nil
+}
+
+// cursorAt returns the cursor of the item at the given position of the full list
+func cursorAt(position int) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(cursorPrefix + strconv.Itoa(position)))
+}
+
+func decodeCursor(cursor string) (int, error) {
+	decoded, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil || !strings.HasPrefix(string(decoded), cursorPrefix) {
+		return 0, fmt.Errorf("invalid cursor %q", cursor)
+	}
+
+	offset, err := strconv.Atoi(strings.TrimPrefix(string(decoded), cursorPrefix))
+	if err != nil || offset < 0 {
+		return 0, fmt.Errorf("invalid cursor %q", cursor)
+	}
+
+	return offset, nil
+}
+
+// pageInfo describes a page holding count items out of total
+func pageInfo(page db.Page, count, total int) *model.PageInfo {
+	info := &model.PageInfo{
+		HasPreviousPage: page.Offset > 0,
+		HasNextPage:     page.Offset+count < total,
+	}
+
+	if count > 0 {
+		start, end := cursorAt(page.Offset), cursorAt(page.Offset+count-1)
+		info.StartCursor = &start
+		info.EndCursor = &end
+	}
+
+	return info
+}
@@ -0,0 +1,59 @@
+package resolver
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/shubham-tomar/feature-toggler/graphQl/model"
+)
+
+func TestProtectedEnvironmentsChangeOnlyByAdmins(t *testing.T) {
+	r := newTestResolver(t)
+	mutation := r.Mutation()
+
+	admin, adminCtx := newTestUser(t, r, "admin")
+	developer, developerCtx := newTestUser(t, r, "developer")
+
+	project, err := mutation.CreateProject(adminCtx, "checkout")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := mutation.AddProjectMember(adminCtx, model.AddProjectMemberInput{
+		ProjectID: project.ID, UserID: developer.ID, Role: model.RoleDeveloper,
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	production := []model.Environment{model.EnvironmentProduction}
+	if _, err := mutation.UpdateProject(adminCtx, project.ID, model.UpdateProjectInput{ProtectedEnvironments: production}); err != nil {
+		t.Fatalf("an admin could not protect production: %v", err)
+	}
+	// Setting the same list again is not a change
+	if _, err := mutation.UpdateProject(adminCtx, project.ID, model.UpdateProjectInput{ProtectedEnvironments: production}); err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = mutation.UpdateProject(developerCtx, project.ID, model.UpdateProjectInput{ProtectedEnvironments: []model.Environment{}})
+	if errorCode(err) != codeForbidden {
+		t.Fatalf("a developer unprotecting production got %v, want a %s error", err, codeForbidden)
+	}
+
+	current, err := r.Storage.GetProjectByID(adminCtx, project.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !slices.Equal(current.ProtectedEnvironments, production) {
+		t.Errorf("protected environments = %v, want %v", current.ProtectedEnvironments, production)
+	}
+
+	changes, err := r.Project().ProtectionChanges(adminCtx, current)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(changes) != 1 {
+		t.Fatalf("recorded %d protection changes, want 1", len(changes))
+	}
+	if changes[0].ChangedBy.ID != admin.ID || !slices.Equal(changes[0].Environments, production) {
+		t.Errorf("recorded %v by %s, want %v by %s", changes[0].Environments, changes[0].ChangedBy.ID, production, admin.ID)
+	}
+}
@@ -0,0 +1,199 @@
+package resolver
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+
+	"github.com/shubham-tomar/feature-toggler/db"
+	userctx "github.com/shubham-tomar/feature-toggler/graphQl/context"
+	"github.com/shubham-tomar/feature-toggler/graphQl/model"
+)
+
+// promote previews, applies or submits for approval a promotion whose project, flag and environments are set
+func (r *Resolver) promote(ctx context.Context, promotion *model.Promotion, dryRun *bool) (*model.Promotion, error) {
+	if promotion.From == promotion.To {
+		return nil, invalidInputError("cannot promote from %s to itself", promotion.From)
+	}
+
+	project, err := r.Storage.GetProjectByID(ctx, promotion.Project.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get project: %w", err)
+	}
+
+	promotion.Project = project
+	promotion.RequestedBy = userctx.GetUser(ctx)
+
+	promotion.Changes, err = r.promotionChanges(ctx, promotion)
+	if err != nil {
+		return nil, err
+	}
+
+	switch {
+	case dryRun != nil && *dryRun:
+		promotion.Status = model.PromotionStatusPreview
+		return promotion, nil
+	case len(promotion.Changes) == 0:
+		// Nothing to approve or record, the environments already match
+		promotion.Status = model.PromotionStatusApplied
+		return promotion, nil
+	case slices.Contains(project.ProtectedEnvironments, promotion.To):
+		if err := r.Storage.CreatePromotion(ctx, promotion); err != nil {
+			return nil, fmt.Errorf("failed to create promotion: %w", err)
+		}
+		return promotion, nil
+	}
+
+	promotion.DecidedBy = promotion.RequestedBy
+	if err := r.Storage.ApplyPromotion(ctx, promotion); err != nil {
+		return nil, fmt.Errorf("failed to apply promotion: %w", err)
+	}
+	return promotion, nil
+}
+
+// pendingPromotion loads a promotion waiting for approval that the current user may decide on
+func (r *Resolver) pendingPromotion(ctx context.Context, id string, approving bool) (*model.Promotion, error) {
+	promotion, err := r.Storage.GetPromotionByID(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get promotion: %w", err)
+	}
+
+	if promotion.Status != model.PromotionStatusPendingApproval {
+		return nil, conflictError("promotion is %s", promotion.Status)
+	}
+
+	promotion.DecidedBy, err = r.decider(ctx, promotion.Project.ID, promotion.RequestedBy, approving, "promotions")
+	if err != nil {
+		return nil, err
+	}
+	return promotion, nil
+}
+
+// promotionChanges compares the flags of a promotion between its environments, as they are now
+func (r *Resolver) promotionChanges(ctx context.Context, promotion *model.Promotion) ([]*model.FlagStateChange, error) {
+	var flags []*model.FeatureFlag
+	if promotion.FeatureFlag != nil {
+		flag, err := r.Storage.GetFeatureFlagByID(ctx, promotion.FeatureFlag.ID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get feature flag: %w", err)
+		}
+		if flag.Status == model.FlagStatusArchived {
+			return nil, invalidInputError("feature flag %s is archived, restore it before promoting it", flag.Key)
+		}
+		if stateIn(flag, promotion.From) == nil {
+			return nil, invalidInputError("feature flag %s is not configured in %s", flag.Key, promotion.From)
+		}
+		flags = []*model.FeatureFlag{flag}
+	} else {
+		projectFlags, err := r.Storage.GetProjectFeatureFlags(ctx, promotion.Project.ID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get feature flags: %w", err)
+		}
+		for _, flag := range projectFlags {
+			if flag.Status == model.FlagStatusArchived {
+				continue
+			}
+			if flag.States, err = r.Storage.GetFeatureFlagStates(ctx, flag.ID); err != nil {
+				return nil, fmt.Errorf("failed to get toggle states: %w", err)
+			}
+			if stateIn(flag, promotion.From) != nil {
+				flags = append(flags, flag)
+			}
+		}
+	}
+
+	changes := []*model.FlagStateChange{}
+	for _, flag := range flags {
+		change, err := r.flagStateChange(ctx, flag, promotion.From, promotion.To)
+		if err != nil {
+			return nil, err
+		}
+		if change != nil {
+			changes = append(changes, change)
+		}
+	}
+	return changes, nil
+}
+
+// flagStateChange returns what promoting a flag would change in the target environment, or nil if nothing
+func (r *Resolver) flagStateChange(ctx context.Context, flag *model.FeatureFlag, from, to model.Environment) (*model.FlagStateChange, error) {
+	source, target := stateIn(flag, from), stateIn(flag, to)
+
+	sourceTargets, err := r.flagTargets(ctx, flag.ID, from)
+	if err != nil {
+		return nil, err
+	}
+	targetTargets, err := r.flagTargets(ctx, flag.ID, to)
+	if err != nil {
+		return nil, err
+	}
+
+	change := &model.FlagStateChange{FeatureFlag: flag, FlagKey: flag.Key, EnabledAfter: source.Enabled}
+	if target != nil {
+		change.EnabledBefore = &target.Enabled
+	}
+
+	sourceKeys, targetKeys := sourceTargets[flag.ID], targetTargets[flag.ID]
+	for key, variant := range sourceKeys {
+		current, ok := targetKeys[key]
+		switch {
+		case !ok:
+			change.TargetsAdded++
+		case current != variant:
+			change.TargetsMoved++
+		}
+	}
+	for key := range targetKeys {
+		if _, ok := sourceKeys[key]; !ok {
+			change.TargetsRemoved++
+		}
+	}
+
+	if target != nil && target.Enabled == source.Enabled && change.TargetsAdded+change.TargetsMoved+change.TargetsRemoved == 0 {
+		return nil, nil
+	}
+	return change, nil
+}
+
+// unprotected rejects direct changes to a protected environment, those go through a promotion or
+// a change set so that another admin of the project approves them
+func (r *Resolver) unprotected(ctx context.Context, projectID string, env model.Environment) error {
+	project, err := r.Storage.GetProjectByID(ctx, projectID)
+	if err != nil {
+		return fmt.Errorf("failed to get project: %w", err)
+	}
+
+	if slices.Contains(project.ProtectedEnvironments, env) {
+		return invalidInputError("%s is protected, change it with a promotion or a change set", env)
+	}
+	return nil
+}
+
+// sameEnvironments tells whether two lists hold the same environments, in any order
+func sameEnvironments(a, b []model.Environment) bool {
+	return len(a) == len(b) && !slices.ContainsFunc(a, func(env model.Environment) bool {
+		return !slices.Contains(b, env)
+	})
+}
+
+// optionalFlag loads a flag that may have been deleted since it was referenced
+func (r *Resolver) optionalFlag(ctx context.Context, id string) (*model.FeatureFlag, error) {
+	flag, err := r.Storage.GetFeatureFlagByID(ctx, id)
+	if errors.Is(err, db.ErrNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get feature flag: %w", err)
+	}
+	return flag, nil
+}
+
+func stateIn(flag *model.FeatureFlag, env model.Environment) *model.ToggleState {
+	for _, state := range flag.States {
+		if state.Environment == env {
+			return state
+		}
+	}
+	return nil
+}
@@ -1,7 +1,14 @@
 package resolver
 
 import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/shubham-tomar/feature-toggler/auth"
 	"github.com/shubham-tomar/feature-toggler/db"
+	"github.com/shubham-tomar/feature-toggler/graphQl/loaders"
+	userctx "github.com/shubham-tomar/feature-toggler/graphQl/context"
 	"github.com/shubham-tomar/feature-toggler/graphQl/model"
 )
 
@@ -9,7 +16,167 @@ import (
 //
 // It serves as dependency injection for your app, add any dependencies you require here.
 
+// Invitations expire after a week unless the inviter asks otherwise
+const defaultInvitationDays = 7
+
 type Resolver struct{
 	projects []*model.Project
 	Storage  db.Storage
+	// Overrides signs the override tokens minted by createOverrideToken
+	Overrides *auth.OverrideSigner
+}
+
+// loaders returns the dataloaders of the request, requests that bypass
+// loaders.Middleware get their own so that resolvers still work
+func (r *Resolver) loaders(ctx context.Context) *loaders.Loaders {
+	if l := loaders.For(ctx); l != nil {
+		return l
+	}
+	return loaders.New(r.Storage)
+}
+
+// tokenOwner resolves whose access tokens are managed: the current user by default,
+// or a service account the current user created or administers, never another person
+func (r *Resolver) tokenOwner(ctx context.Context, userID *string) (*model.User, error) {
+	user := userctx.GetUser(ctx)
+	if userID == nil || *userID == user.ID {
+		return user, nil
+	}
+
+	owner, err := r.Storage.GetUserByID(ctx, *userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user: %w", err)
+	}
+
+	if owner.Kind != model.UserKindServiceAccount {
+		return nil, forbiddenError("access tokens can only be managed for yourself or a service account")
+	}
+
+	if owner.CreatedBy != nil && owner.CreatedBy.ID == user.ID {
+		return owner, nil
+	}
+
+	admin, err := r.administers(ctx, user.ID, owner.ID)
+	if err != nil {
+		return nil, err
+	}
+	if !admin {
+		return nil, forbiddenError("only the creator of service account %s or an admin of all its projects can manage its access tokens", owner.Name)
+	}
+
+	return owner, nil
+}
+
+// administers reports whether the user is an admin of every project the account is a member of,
+// nobody administers an account that is in no project
+func (r *Resolver) administers(ctx context.Context, userID, accountID string) (bool, error) {
+	memberships, err := r.Storage.GetUserMemberships(ctx, accountID)
+	if err != nil {
+		return false, fmt.Errorf("failed to get project memberships: %w", err)
+	}
+	if len(memberships) == 0 {
+		return false, nil
+	}
+
+	for _, m := range memberships {
+		membership, err := r.projectMembership(ctx, m.Project.ID, userID)
+		if err != nil {
+			return false, err
+		}
+		if membership == nil || membership.Role != model.RoleAdmin {
+			return false, nil
+		}
+	}
+
+	return true, nil
+}
+
+// canGrantAdminScope reports whether the current user may issue an ADMIN token to the owner:
+// their own tokens need them to be an admin of some project, those of a service account
+// need them to administer the account
+func (r *Resolver) canGrantAdminScope(ctx context.Context, owner *model.User) (bool, error) {
+	user := userctx.GetUser(ctx)
+	if owner.ID != user.ID {
+		return r.administers(ctx, user.ID, owner.ID)
+	}
+
+	memberships, err := r.Storage.GetUserMemberships(ctx, user.ID)
+	if err != nil {
+		return false, fmt.Errorf("failed to get project memberships: %w", err)
+	}
+
+	for _, m := range memberships {
+		if m.Role == model.RoleAdmin {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
+// projectMembership returns the membership of the user in the project, or nil if there is none
+func (r *Resolver) projectMembership(ctx context.Context, projectID, userID string) (*model.ProjectUser, error) {
+	members, err := r.Storage.GetProjectMembers(ctx, projectID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get project members: %w", err)
+	}
+
+	for _, member := range members {
+		if member.User != nil && member.User.ID == userID {
+			return member, nil
+		}
+	}
+
+	return nil, nil
+}
+
+// requireAdmin fails unless the current user is an admin of the project, action completes "only admins of the project can ..."
+func (r *Resolver) requireAdmin(ctx context.Context, projectID, action string) error {
+	membership, err := r.projectMembership(ctx, projectID, userctx.GetUser(ctx).ID)
+	if err != nil {
+		return err
+	}
+	if membership == nil || membership.Role != model.RoleAdmin {
+		return forbiddenError("only admins of the project can %s", action)
+	}
+	return nil
+}
+
+// decider returns the current user if they may decide on a request of the project: only project admins
+// decide, and approving takes someone other than the requester
+func (r *Resolver) decider(ctx context.Context, projectID string, requester *model.User, approving bool, what string) (*model.User, error) {
+	user := userctx.GetUser(ctx)
+	if approving && requester != nil && requester.ID == user.ID {
+		return nil, invalidInputError("%s must be approved by another admin of the project", what)
+	}
+
+	membership, err := r.projectMembership(ctx, projectID, user.ID)
+	if err != nil {
+		return nil, err
+	}
+	if membership == nil || membership.Role != model.RoleAdmin {
+		return nil, fmt.Errorf("only admins of the project can decide on %s", what)
+	}
+
+	return user, nil
+}
+
+// pendingInvitationForCurrentUser loads an invitation the current user may still respond to.
+// Invitations are addressed by email, so only the user with that email can accept or decline them.
+func (r *Resolver) pendingInvitationForCurrentUser(ctx context.Context, id string) (*model.ProjectInvitation, error) {
+	invitation, err := r.Storage.GetInvitationByID(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get invitation: %w", err)
+	}
+
+	user := userctx.GetUser(ctx)
+	if !strings.EqualFold(user.Email, invitation.Email) {
+		return nil, fmt.Errorf("this invitation was sent to a different email address")
+	}
+
+	if invitation.Status != model.InvitationStatusPending {
+		return nil, conflictError("invitation is %s", invitation.Status)
+	}
+
+	return invitation, nil
 }
@@ -0,0 +1,35 @@
+package resolver
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/shubham-tomar/feature-toggler/db/sqlite/sqlitetest"
+	userctx "github.com/shubham-tomar/feature-toggler/graphQl/context"
+	"github.com/shubham-tomar/feature-toggler/graphQl/model"
+	"github.com/vektah/gqlparser/v2/gqlerror"
+)
+
+// newTestResolver returns a resolver over a fresh SQLite database
+func newTestResolver(t *testing.T) *Resolver {
+	return &Resolver{Storage: sqlitetest.New(t)}
+}
+
+// newTestUser stores a user and returns a context in which they are signed in
+func newTestUser(t *testing.T, r *Resolver, name string) (*model.User, context.Context) {
+	t.Helper()
+
+	user := sqlitetest.User(t, r.Storage, name)
+	return user, userctx.WithUser(context.Background(), user)
+}
+
+// errorCode returns the code in the extensions of a GraphQL error, or "" for errors without one
+func errorCode(err error) string {
+	var gqlErr *gqlerror.Error
+	if errors.As(err, &gqlErr) {
+		code, _ := gqlErr.Extensions["code"].(string)
+		return code
+	}
+	return ""
+}
@@ -6,198 +6,2455 @@ package resolver
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"slices"
+	"strings"
+	"time"
+
 	"github.com/google/uuid"
+	"github.com/shubham-tomar/feature-toggler/auth"
+	"github.com/shubham-tomar/feature-toggler/db"
+	"github.com/shubham-tomar/feature-toggler/evaluation"
+	"github.com/shubham-tomar/feature-toggler/experiments"
 	userctx "github.com/shubham-tomar/feature-toggler/graphQl/context"
+	"github.com/shubham-tomar/feature-toggler/graphQl/generated"
+	"github.com/shubham-tomar/feature-toggler/graphQl/model"
+	"github.com/shubham-tomar/feature-toggler/health"
+	"github.com/shubham-tomar/feature-toggler/simulation"
+	"github.com/shubham-tomar/feature-toggler/webhooks"
+)
+
+// Project is the resolver for the project field.
+func (r *changeSetResolver) Project(ctx context.Context, obj *model.ChangeSet) (*model.Project, error) {
+	project, err := r.loaders(ctx).Projects.Load(ctx, obj.Project.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get project: %w", err)
+	}
+
+	return project, nil
+}
+
+// CreatedBy is the resolver for the created_by field.
+func (r *changeSetResolver) CreatedBy(ctx context.Context, obj *model.ChangeSet) (*model.User, error) {
+	user, err := r.loaders(ctx).Users.Load(ctx, obj.CreatedBy.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user: %w", err)
+	}
+
+	return user, nil
+}
+
+// SubmittedBy is the resolver for the submitted_by field.
+func (r *changeSetResolver) SubmittedBy(ctx context.Context, obj *model.ChangeSet) (*model.User, error) {
+	if obj.SubmittedBy == nil {
+		return nil, nil
+	}
+
+	user, err := r.loaders(ctx).Users.Load(ctx, obj.SubmittedBy.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user: %w", err)
+	}
+
+	return user, nil
+}
+
+// DecidedBy is the resolver for the decided_by field.
+func (r *changeSetResolver) DecidedBy(ctx context.Context, obj *model.ChangeSet) (*model.User, error) {
+	if obj.DecidedBy == nil {
+		return nil, nil
+	}
+
+	user, err := r.loaders(ctx).Users.Load(ctx, obj.DecidedBy.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user: %w", err)
+	}
+
+	return user, nil
+}
+
+// RevertedBy is the resolver for the reverted_by field.
+func (r *changeSetResolver) RevertedBy(ctx context.Context, obj *model.ChangeSet) (*model.User, error) {
+	if obj.RevertedBy == nil {
+		return nil, nil
+	}
+
+	user, err := r.loaders(ctx).Users.Load(ctx, obj.RevertedBy.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user: %w", err)
+	}
+
+	return user, nil
+}
+
+// FeatureFlag is the resolver for the feature_flag field.
+func (r *experimentResolver) FeatureFlag(ctx context.Context, obj *model.Experiment) (*model.FeatureFlag, error) {
+	flag, err := r.Storage.GetFeatureFlagByID(ctx, obj.FeatureFlag.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get feature flag: %w", err)
+	}
+
+	return flag, nil
+}
+
+// CreatedBy is the resolver for the created_by field.
+func (r *experimentResolver) CreatedBy(ctx context.Context, obj *model.Experiment) (*model.User, error) {
+	user, err := r.loaders(ctx).Users.Load(ctx, obj.CreatedBy.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user: %w", err)
+	}
+
+	return user, nil
+}
+
+// Results is the resolver for the results field.
+func (r *experimentResolver) Results(ctx context.Context, obj *model.Experiment, confidenceLevel *float64) (*model.ExperimentResults, error) {
+	level := experiments.DefaultConfidenceLevel
+	if confidenceLevel != nil {
+		if *confidenceLevel <= 0 || *confidenceLevel >= 1 {
+			return nil, invalidInputError("confidence level must be between 0 and 1")
+		}
+		level = *confidenceLevel
+	}
+
+	return r.experimentResults(ctx, obj, level)
+}
+
+// Layer is the resolver for the layer field.
+func (r *experimentResolver) Layer(ctx context.Context, obj *model.Experiment) (*model.ExperimentLayer, error) {
+	if obj.Layer == nil {
+		return nil, nil
+	}
+
+	layer, err := r.Storage.GetExperimentLayerByID(ctx, obj.Layer.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get experiment layer: %w", err)
+	}
+
+	return layer, nil
+}
+
+// Project is the resolver for the project field.
+func (r *experimentLayerResolver) Project(ctx context.Context, obj *model.ExperimentLayer) (*model.Project, error) {
+	project, err := r.loaders(ctx).Projects.Load(ctx, obj.Project.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get project: %w", err)
+	}
+
+	return project, nil
+}
+
+// Experiments is the resolver for the experiments field.
+func (r *experimentLayerResolver) Experiments(ctx context.Context, obj *model.ExperimentLayer) ([]*model.Experiment, error) {
+	experiments, err := r.Storage.GetLayerExperiments(ctx, obj.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get layer experiments: %w", err)
+	}
+
+	return experiments, nil
+}
+
+// FreePercentage is the resolver for the free_percentage field.
+func (r *experimentLayerResolver) FreePercentage(ctx context.Context, obj *model.ExperimentLayer, environment model.Environment) (int, error) {
+	experiments, err := r.Storage.GetLayerExperiments(ctx, obj.ID)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get layer experiments: %w", err)
+	}
+
+	return freePercentage(layerRanges(experiments, environment)), nil
+}
+
+// CreatedBy is the resolver for the created_by field.
+func (r *featureFlagResolver) CreatedBy(ctx context.Context, obj *model.FeatureFlag) (*model.User, error) {
+	user, err := r.loaders(ctx).Users.Load(ctx, obj.CreatedBy.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user: %w", err)
+	}
+
+	return user, nil
+}
+
+// States is the resolver for the states field.
+func (r *featureFlagResolver) States(ctx context.Context, obj *model.FeatureFlag) ([]*model.ToggleState, error) {
+	// Flags loaded on their own already carry their states, lists leave them to the loader
+	if obj.States != nil {
+		return obj.States, nil
+	}
+
+	states, err := r.loaders(ctx).States.Load(ctx, obj.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get toggle states: %w", err)
+	}
+
+	return states, nil
+}
+
+// Project is the resolver for the project field.
+func (r *featureFlagResolver) Project(ctx context.Context, obj *model.FeatureFlag) (*model.Project, error) {
+	project, err := r.loaders(ctx).Projects.Load(ctx, obj.Project.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get project: %w", err)
+	}
+
+	return project, nil
+}
+
+// Evaluations is the resolver for the evaluations field.
+func (r *featureFlagResolver) Evaluations(ctx context.Context, obj *model.FeatureFlag, environment *model.Environment, since *time.Time) (*model.EvaluationStats, error) {
+	from := time.Now().Add(-defaultEvaluationWindow)
+	if since != nil {
+		from = *since
+	}
+
+	counts, err := r.Storage.GetEvaluationCounts(ctx, obj.ID, environment, from)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get evaluation counts: %w", err)
+	}
+
+	uniqueContexts, err := r.Storage.CountImpressions(ctx, obj.ID, environment, from)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count impressions: %w", err)
+	}
+
+	return summarizeEvaluations(counts, uniqueContexts), nil
+}
+
+// Health is the resolver for the health field.
+func (r *featureFlagResolver) Health(ctx context.Context, obj *model.FeatureFlag) (*model.FlagHealth, error) {
+	project, err := r.loaders(ctx).Projects.Load(ctx, obj.Project.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get project: %w", err)
+	}
+
+	states, err := r.States(ctx, obj)
+	if err != nil {
+		return nil, err
+	}
+
+	lastEvaluated, err := r.loaders(ctx).LastEvaluations.Load(ctx, obj.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get last evaluation: %w", err)
+	}
+
+	flag := *obj
+	flag.States = states
+	return health.Classify(&flag, lastEvaluated, health.ThresholdsFor(project), time.Now()), nil
+}
+
+// Owner is the resolver for the owner field.
+func (r *featureFlagResolver) Owner(ctx context.Context, obj *model.FeatureFlag) (*model.User, error) {
+	if obj.Owner == nil {
+		return nil, nil
+	}
+
+	user, err := r.loaders(ctx).Users.Load(ctx, obj.Owner.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get owner: %w", err)
+	}
+
+	return user, nil
+}
+
+// Expired is the resolver for the expired field.
+func (r *featureFlagResolver) Expired(ctx context.Context, obj *model.FeatureFlag) (bool, error) {
+	return health.Expired(obj, time.Now()), nil
+}
+
+// Warnings is the resolver for the warnings field.
+func (r *featureFlagResolver) Warnings(ctx context.Context, obj *model.FeatureFlag) ([]string, error) {
+	return flagWarnings(obj, time.Now()), nil
+}
+
+// FeatureFlag is the resolver for the feature_flag field.
+func (r *flagChangeResolver) FeatureFlag(ctx context.Context, obj *model.FlagChange) (*model.FeatureFlag, error) {
+	return r.optionalFlag(ctx, obj.FeatureFlag.ID)
+}
+
+// UpdatedBy is the resolver for the updated_by field.
+func (r *flagEnvironmentStateResolver) UpdatedBy(ctx context.Context, obj *model.FlagEnvironmentState) (*model.User, error) {
+	if obj.UpdatedBy == nil {
+		return nil, nil
+	}
+
+	user, err := r.loaders(ctx).Users.Load(ctx, obj.UpdatedBy.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user: %w", err)
+	}
+
+	return user, nil
+}
+
+// FeatureFlag is the resolver for the feature_flag field.
+func (r *flagStateChangeResolver) FeatureFlag(ctx context.Context, obj *model.FlagStateChange) (*model.FeatureFlag, error) {
+	return r.optionalFlag(ctx, obj.FeatureFlag.ID)
+}
+
+// Project is the resolver for the project field.
+func (r *holdoutResolver) Project(ctx context.Context, obj *model.Holdout) (*model.Project, error) {
+	project, err := r.loaders(ctx).Projects.Load(ctx, obj.Project.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get project: %w", err)
+	}
+
+	return project, nil
+}
+
+// CreateProject is the resolver for the createProject field.
+func (r *mutationResolver) CreateProject(ctx context.Context, name string) (*model.Project, error) {
+	user := userctx.GetUser(ctx)
+	return r.Storage.CreateProject(ctx, user, name)
+}
+
+// CreateUser is the resolver for the createUser field.
+func (r *mutationResolver) CreateUser(ctx context.Context, input model.CreateUserInput) (*model.User, error) {
+	panic(fmt.Errorf("not implemented: CreateUser - createUser"))
+}
+
+// UpdateUser is the resolver for the updateUser field.
+func (r *mutationResolver) UpdateUser(ctx context.Context, id string, input model.UpdateUserInput) (*model.User, error) {
+	panic(fmt.Errorf("not implemented: UpdateUser - updateUser"))
+}
+
+// UpdateProject is the resolver for the updateProject field.
+func (r *mutationResolver) UpdateProject(ctx context.Context, id string, input model.UpdateProjectInput) (*model.Project, error) {
+	project, err := r.Storage.GetProjectByID(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get project: %w", err)
+	}
+	if err := r.requireAdmin(ctx, project.ID, "change its settings"); err != nil {
+		return nil, err
+	}
+
+	if input.Name != nil {
+		if strings.TrimSpace(*input.Name) == "" {
+			return nil, invalidInputError("name must not be empty")
+		}
+		project.Name = *input.Name
+	}
+
+	// Key rules only apply to flags created afterwards, existing keys stay valid
+	if input.FlagKeyPattern != nil {
+		project.FlagKeyPattern = input.FlagKeyPattern
+		if *input.FlagKeyPattern == "" {
+			project.FlagKeyPattern = nil
+		}
+	}
+
+	if input.FlagKeyMaxLength != nil {
+		project.FlagKeyMaxLength = input.FlagKeyMaxLength
+		if *input.FlagKeyMaxLength == 0 {
+			project.FlagKeyMaxLength = nil
+		}
+	}
+
+	if err := validateFlagKeyRules(project.FlagKeyPattern, project.FlagKeyMaxLength); err != nil {
+		return nil, err
+	}
+
+	if project.NewFlagDays, err = healthThreshold(project.NewFlagDays, input.NewFlagDays, "newFlagDays"); err != nil {
+		return nil, err
+	}
+	if project.StaleAfterDays, err = healthThreshold(project.StaleAfterDays, input.StaleAfterDays, "staleAfterDays"); err != nil {
+		return nil, err
+	}
+	if project.UnusedAfterDays, err = healthThreshold(project.UnusedAfterDays, input.UnusedAfterDays, "unusedAfterDays"); err != nil {
+		return nil, err
+	}
+
+	if err := r.Storage.UpdateProject(ctx, project); err != nil {
+		return nil, fmt.Errorf("failed to update project: %w", err)
+	}
+
+	// Protection changes are recorded with who made them, setting the same list again records nothing
+	if input.ProtectedEnvironments != nil {
+		protected := []model.Environment{}
+		for _, env := range input.ProtectedEnvironments {
+			if !slices.Contains(protected, env) {
+				protected = append(protected, env)
+			}
+		}
+
+		if !sameEnvironments(protected, project.ProtectedEnvironments) {
+			change := &model.ProtectionChange{Environments: protected, ChangedBy: userctx.GetUser(ctx)}
+			if err := r.Storage.UpdateProjectProtection(ctx, project.ID, change); err != nil {
+				return nil, fmt.Errorf("failed to update protected environments: %w", err)
+			}
+		}
+	}
+
+	return r.Storage.GetProjectByID(ctx, id)
+}
+
+// DeleteProject is the resolver for the deleteProject field.
+func (r *mutationResolver) DeleteProject(ctx context.Context, id string) (bool, error) {
+	panic(fmt.Errorf("not implemented: DeleteProject - deleteProject"))
+}
+
+// AddProjectMember is the resolver for the addProjectMember field.
+func (r *mutationResolver) AddProjectMember(ctx context.Context, input model.AddProjectMemberInput) (*model.ProjectUser, error) {
+	if _, err := r.Storage.GetProjectByID(ctx, input.ProjectID); err != nil {
+		return nil, fmt.Errorf("failed to get project: %w", err)
+	}
+	if err := r.requireAdmin(ctx, input.ProjectID, "add members"); err != nil {
+		return nil, err
+	}
+
+	user, err := r.Storage.GetUserByID(ctx, input.UserID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user: %w", err)
+	}
+
+	existing, err := r.projectMembership(ctx, input.ProjectID, user.ID)
+	if err != nil {
+		return nil, err
+	}
+	if existing != nil {
+		return nil, conflictError("user %s is already a member of this project", user.ID)
+	}
+
+	membership := &model.ProjectUser{
+		User:    user,
+		Project: &model.Project{ID: input.ProjectID},
+		Role:    input.Role,
+	}
+	if err := r.Storage.AddProjectMember(ctx, membership); err != nil {
+		return nil, fmt.Errorf("failed to add project member: %w", err)
+	}
+
+	return r.Storage.GetProjectMember(ctx, membership.ID)
+}
+
+// UpdateProjectMember is the resolver for the updateProjectMember field.
+func (r *mutationResolver) UpdateProjectMember(ctx context.Context, id string, role model.Role) (*model.ProjectUser, error) {
+	membership, err := r.Storage.GetProjectMember(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get project member: %w", err)
+	}
+	if err := r.requireAdmin(ctx, membership.Project.ID, "change the roles of members"); err != nil {
+		return nil, err
+	}
+
+	if err := r.Storage.UpdateProjectMemberRole(ctx, id, role); err != nil {
+		return nil, fmt.Errorf("failed to update project member: %w", err)
+	}
+
+	return r.Storage.GetProjectMember(ctx, id)
+}
+
+// RemoveProjectMember is the resolver for the removeProjectMember field.
+func (r *mutationResolver) RemoveProjectMember(ctx context.Context, id string) (bool, error) {
+	membership, err := r.Storage.GetProjectMember(ctx, id)
+	if err != nil {
+		return false, fmt.Errorf("failed to get project member: %w", err)
+	}
+	if err := r.requireAdmin(ctx, membership.Project.ID, "remove members"); err != nil {
+		return false, err
+	}
+
+	if err := r.Storage.RemoveProjectMember(ctx, id); err != nil {
+		return false, fmt.Errorf("failed to remove project member: %w", err)
+	}
+
+	return true, nil
+}
+
+// InviteProjectMember is the resolver for the inviteProjectMember field.
+func (r *mutationResolver) InviteProjectMember(ctx context.Context, input model.InviteProjectMemberInput) (*model.ProjectInvitation, error) {
+	user := userctx.GetUser(ctx)
+
+	email := strings.ToLower(strings.TrimSpace(input.Email))
+	if !strings.Contains(email, "@") {
+		return nil, invalidInputError("invalid email address %q", input.Email)
+	}
+
+	if _, err := r.Storage.GetProjectByID(ctx, input.ProjectID); err != nil {
+		return nil, fmt.Errorf("failed to get project: %w", err)
+	}
+	if err := r.requireAdmin(ctx, input.ProjectID, "invite members"); err != nil {
+		return nil, err
+	}
+
+	// Inviting someone who already has access or a usable invitation is a mistake
+	members, err := r.Storage.GetProjectMembers(ctx, input.ProjectID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get project members: %w", err)
+	}
+	for _, member := range members {
+		if member.User != nil && strings.EqualFold(member.User.Email, email) {
+			return nil, conflictError("%s is already a member of this project", email)
+		}
+	}
+
+	invitations, err := r.Storage.GetProjectInvitations(ctx, input.ProjectID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get invitations: %w", err)
+	}
+	for _, invitation := range invitations {
+		if invitation.Status == model.InvitationStatusPending && invitation.Email == email {
+			return nil, conflictError("%s already has a pending invitation", email)
+		}
+	}
+
+	days := defaultInvitationDays
+	if input.ExpiresInDays != nil {
+		if *input.ExpiresInDays <= 0 {
+			return nil, invalidInputError("expiresInDays must be positive")
+		}
+		days = int(*input.ExpiresInDays)
+	}
+
+	invitation := &model.ProjectInvitation{
+		Project:   &model.Project{ID: input.ProjectID},
+		Email:     email,
+		Role:      input.Role,
+		InvitedBy: user,
+		ExpiresAt: time.Now().AddDate(0, 0, days),
+	}
+	if err := r.Storage.CreateInvitation(ctx, invitation); err != nil {
+		return nil, fmt.Errorf("failed to create invitation: %w", err)
+	}
+
+	return r.Storage.GetInvitationByID(ctx, invitation.ID)
+}
+
+// AcceptInvitation is the resolver for the acceptInvitation field.
+func (r *mutationResolver) AcceptInvitation(ctx context.Context, id string) (*model.ProjectUser, error) {
+	invitation, err := r.pendingInvitationForCurrentUser(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	membership, err := r.Storage.AcceptInvitation(ctx, invitation.ID, userctx.GetUser(ctx))
+	if err != nil {
+		return nil, fmt.Errorf("failed to accept invitation: %w", err)
+	}
+
+	return membership, nil
+}
+
+// DeclineInvitation is the resolver for the declineInvitation field.
+func (r *mutationResolver) DeclineInvitation(ctx context.Context, id string) (*model.ProjectInvitation, error) {
+	invitation, err := r.pendingInvitationForCurrentUser(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := r.Storage.UpdateInvitationStatus(ctx, invitation.ID, model.InvitationStatusDeclined); err != nil {
+		return nil, fmt.Errorf("failed to decline invitation: %w", err)
+	}
+
+	return r.Storage.GetInvitationByID(ctx, invitation.ID)
+}
+
+// RevokeInvitation is the resolver for the revokeInvitation field.
+func (r *mutationResolver) RevokeInvitation(ctx context.Context, id string) (*model.ProjectInvitation, error) {
+	invitation, err := r.Storage.GetInvitationByID(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get invitation: %w", err)
+	}
+	if err := r.requireAdmin(ctx, invitation.Project.ID, "revoke invitations"); err != nil {
+		return nil, err
+	}
+
+	if invitation.Status != model.InvitationStatusPending {
+		return nil, conflictError("only pending invitations can be revoked, this one is %s", invitation.Status)
+	}
+
+	if err := r.Storage.UpdateInvitationStatus(ctx, invitation.ID, model.InvitationStatusRevoked); err != nil {
+		return nil, fmt.Errorf("failed to revoke invitation: %w", err)
+	}
+
+	return r.Storage.GetInvitationByID(ctx, invitation.ID)
+}
+
+// CreateFeatureFlag is the resolver for the createFeatureFlag field.
+func (r *mutationResolver) CreateFeatureFlag(ctx context.Context, input model.CreateFeatureFlagInput) (*model.FeatureFlag, error) {
+	user := userctx.GetUser(ctx)
+
+	project, err := r.Storage.GetProjectByID(ctx, input.ProjectID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get project: %w", err)
+	}
+
+	if err := validateFlagKey(project, input.Key); err != nil {
+		return nil, err
+	}
+
+	// Create the feature flag
+	flag := &model.FeatureFlag{
+		ID:          uuid.New().String(),
+		Key:         input.Key,
+		Name:        input.Name,
+		Description: input.Description,
+		Project:     &model.Project{ID: input.ProjectID},
+		CreatedBy:   user,
+		ClientSide:  input.ClientSide != nil && *input.ClientSide,
+		Kind:        model.FlagKindRelease,
+		RemovalDate: input.RemovalDate,
+	}
+
+	if input.Kind != nil {
+		flag.Kind = *input.Kind
+	}
+
+	if flag.Tags, err = normalizeTags(input.Tags); err != nil {
+		return nil, err
+	}
+
+	if flag.Links, err = flagLinks(input.Links); err != nil {
+		return nil, err
+	}
+
+	if input.OwnerID != nil && *input.OwnerID != "" {
+		if flag.Owner, err = r.flagOwner(ctx, *input.OwnerID); err != nil {
+			return nil, err
+		}
+	}
+
+	if input.OwnerTeam != nil && strings.TrimSpace(*input.OwnerTeam) != "" {
+		team := strings.TrimSpace(*input.OwnerTeam)
+		flag.OwnerTeam = &team
+	}
+
+	// Create toggle states for each environment, protected ones can only start off
+	var states []*model.ToggleState
+	for _, env := range input.InitialStates {
+		if env.Enabled && slices.Contains(project.ProtectedEnvironments, env.Environment) {
+			return nil, invalidInputError("%s is protected, the flag must start off there and be turned on with a promotion or a change set", env.Environment)
+		}
+		state := &model.ToggleState{
+			ID:          uuid.New().String(),
+			FeatureFlag: flag,
+			Environment: env.Environment,
+			Enabled:     env.Enabled,
+			UpdatedBy:   user,
+		}
+		states = append(states, state)
+	}
+
+	// Use storage interface to create feature flag with states
+	if err := r.Storage.CreateFeatureFlag(ctx, flag, states); errors.Is(err, db.ErrConflict) {
+		return nil, conflictError("a feature flag with key %q already exists in this project", input.Key)
+	} else if err != nil {
+		return nil, fmt.Errorf("failed to create feature flag: %w", err)
+	}
+
+	// Get complete feature flag with all related data
+	flag, err = r.Storage.GetFeatureFlagByID(ctx, flag.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get feature flag: %w", err)
+	}
+
+	return flag, nil
+}
+
+// UpdateFeatureFlag is the resolver for the updateFeatureFlag field.
+func (r *mutationResolver) UpdateFeatureFlag(ctx context.Context, id string, input model.UpdateFeatureFlagInput) (*model.FeatureFlag, error) {
+	flag, err := r.Storage.GetFeatureFlagByID(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get feature flag: %w", err)
+	}
+
+	if flag.Status == model.FlagStatusArchived {
+		return nil, invalidInputError("feature flag %s is archived, restore it before editing", flag.Key)
+	}
+
+	if input.Name != nil {
+		if strings.TrimSpace(*input.Name) == "" {
+			return nil, invalidInputError("name must not be empty")
+		}
+		flag.Name = *input.Name
+	}
+
+	// An empty description clears it
+	if input.Description != nil {
+		flag.Description = input.Description
+		if *input.Description == "" {
+			flag.Description = nil
+		}
+	}
+
+	if input.Kind != nil {
+		flag.Kind = *input.Kind
+	}
+
+	if input.Tags != nil {
+		if flag.Tags, err = normalizeTags(input.Tags); err != nil {
+			return nil, err
+		}
+	}
+
+	if input.Links != nil {
+		if flag.Links, err = flagLinks(input.Links); err != nil {
+			return nil, err
+		}
+	}
+
+	if input.OwnerID != nil {
+		flag.Owner = nil
+		if *input.OwnerID != "" {
+			if flag.Owner, err = r.flagOwner(ctx, *input.OwnerID); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	if input.OwnerTeam != nil {
+		flag.OwnerTeam = nil
+		if team := strings.TrimSpace(*input.OwnerTeam); team != "" {
+			flag.OwnerTeam = &team
+		}
+	}
+
+	if input.ClearRemovalDate != nil && *input.ClearRemovalDate {
+		if input.RemovalDate != nil {
+			return nil, invalidInputError("removalDate and clearRemovalDate cannot be combined")
+		}
+		flag.RemovalDate = nil
+	} else if input.RemovalDate != nil {
+		flag.RemovalDate = input.RemovalDate
+	}
+
+	if err := r.Storage.UpdateFeatureFlag(ctx, flag); err != nil {
+		return nil, fmt.Errorf("failed to update feature flag: %w", err)
+	}
+
+	return r.Storage.GetFeatureFlagByID(ctx, id)
+}
+
+// ArchiveFeatureFlag is the resolver for the archiveFeatureFlag field.
+func (r *mutationResolver) ArchiveFeatureFlag(ctx context.Context, id string) (*model.FeatureFlag, error) {
+	flag, err := r.Storage.GetFeatureFlagByID(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get feature flag: %w", err)
+	}
+
+	if flag.Status == model.FlagStatusArchived {
+		return flag, nil
+	}
+
+	if err := r.Storage.ArchiveFeatureFlag(ctx, id); err != nil {
+		return nil, fmt.Errorf("failed to archive feature flag: %w", err)
+	}
+
+	return r.Storage.GetFeatureFlagByID(ctx, id)
+}
+
+// RestoreFeatureFlag is the resolver for the restoreFeatureFlag field.
+func (r *mutationResolver) RestoreFeatureFlag(ctx context.Context, id string) (*model.FeatureFlag, error) {
+	flag, err := r.Storage.GetFeatureFlagByID(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get feature flag: %w", err)
+	}
+
+	if flag.Status != model.FlagStatusArchived {
+		return flag, nil
+	}
+
+	if err := r.Storage.RestoreFeatureFlag(ctx, id); err != nil {
+		return nil, fmt.Errorf("failed to restore feature flag: %w", err)
+	}
+
+	return r.Storage.GetFeatureFlagByID(ctx, id)
+}
+
+// DeleteFeatureFlag is the resolver for the deleteFeatureFlag field.
+func (r *mutationResolver) DeleteFeatureFlag(ctx context.Context, id string) (bool, error) {
+	flag, err := r.Storage.GetFeatureFlagByID(ctx, id)
+	if err != nil {
+		return false, fmt.Errorf("failed to get feature flag: %w", err)
+	}
+
+	// Archiving first gives clients a chance to notice before the flag is gone for good
+	if flag.Status != model.FlagStatusArchived {
+		return false, invalidInputError("feature flag %s must be archived before it can be deleted", flag.Key)
+	}
+
+	if err := r.Storage.DeleteFeatureFlag(ctx, id); err != nil {
+		return false, fmt.Errorf("failed to delete feature flag: %w", err)
+	}
+
+	return true, nil
+}
+
+// ToggleFeatureFlag is the resolver for the toggleFeatureFlag field.
+func (r *mutationResolver) ToggleFeatureFlag(ctx context.Context, input model.ToggleFeatureFlagInput) (*model.ToggleState, error) {
+	user := userctx.GetUser(ctx)
+
+	// Get existing toggle state
+	flag, err := r.Storage.GetFeatureFlagByID(ctx, input.FeatureFlagID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find feature flag with ID %s: %w", input.FeatureFlagID, err)
+	}
+
+	if flag.Status == model.FlagStatusArchived {
+		return nil, invalidInputError("feature flag %s is archived, restore it before toggling", flag.Key)
+	}
+
+	if err := r.unprotected(ctx, flag.Project.ID, input.Environment); err != nil {
+		return nil, err
+	}
+
+	// Find state for the environment
+	var state *model.ToggleState
+	for _, s := range flag.States {
+		if s.Environment == input.Environment {
+			state = s
+			break
+		}
+	}
+
+	if state == nil {
+		return nil, fmt.Errorf("no toggle state found for environment %s", input.Environment)
+	}
+
+	// Update toggle state
+	state.Enabled = input.Enabled
+	state.UpdatedBy = user
+
+	// Save the updated state
+	if err := r.Storage.UpdateFeatureFlagState(ctx, state); err != nil {
+		return nil, fmt.Errorf("failed to update toggle state: %w", err)
+	}
+
+	return state, nil
+}
+
+// AddIndividualTargets is the resolver for the addIndividualTargets field.
+func (r *mutationResolver) AddIndividualTargets(ctx context.Context, input model.AddIndividualTargetsInput) (*model.ToggleState, error) {
+	if err := targetVariant(input.Variant); err != nil {
+		return nil, err
+	}
+
+	keys, err := targetKeys(input.ContextKeys)
+	if err != nil {
+		return nil, err
+	}
+
+	state, err := r.targetState(ctx, input.FeatureFlagID, input.Environment)
+	if err != nil {
+		return nil, err
+	}
+
+	state.UpdatedBy = userctx.GetUser(ctx)
+	if _, err := r.Storage.AddIndividualTargets(ctx, state, input.Variant, keys); err != nil {
+		return nil, fmt.Errorf("failed to add individual targets: %w", err)
+	}
+
+	return state, nil
+}
+
+// RemoveIndividualTargets is the resolver for the removeIndividualTargets field.
+func (r *mutationResolver) RemoveIndividualTargets(ctx context.Context, input model.RemoveIndividualTargetsInput) (*model.ToggleState, error) {
+	keys, err := targetKeys(input.ContextKeys)
+	if err != nil {
+		return nil, err
+	}
+
+	state, err := r.targetState(ctx, input.FeatureFlagID, input.Environment)
+	if err != nil {
+		return nil, err
+	}
+
+	state.UpdatedBy = userctx.GetUser(ctx)
+	if _, err := r.Storage.RemoveIndividualTargets(ctx, state, keys); err != nil {
+		return nil, fmt.Errorf("failed to remove individual targets: %w", err)
+	}
+
+	return state, nil
+}
+
+// CreateOverrideToken is the resolver for the createOverrideToken field.
+func (r *mutationResolver) CreateOverrideToken(ctx context.Context, input model.CreateOverrideTokenInput) (*model.OverrideToken, error) {
+	project, err := r.Storage.GetProjectByID(ctx, input.ProjectID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get project: %w", err)
+	}
+
+	if slices.Contains(project.ProtectedEnvironments, input.Environment) {
+		return nil, invalidInputError("overrides are disabled in %s, it is a protected environment", input.Environment)
+	}
+
+	hours := defaultOverrideHours
+	if input.ExpiresInHours != nil {
+		hours = *input.ExpiresInHours
+	}
+	if hours < 1 || hours > maxOverrideTokenHours {
+		return nil, invalidInputError("expiresInHours must be between 1 and %d", maxOverrideTokenHours)
+	}
+
+	flags, err := r.overrideFlags(ctx, project.ID, input.Overrides)
+	if err != nil {
+		return nil, err
+	}
+
+	token := &auth.OverrideToken{
+		ProjectID:   project.ID,
+		Environment: input.Environment,
+		Flags:       flags,
+		ExpiresAt:   time.Now().Add(time.Duration(hours) * time.Hour).UTC().Truncate(time.Second),
+	}
+
+	secret, err := r.Overrides.Sign(token)
+	if err != nil {
+		return nil, fmt.Errorf("failed to sign override token: %w", err)
+	}
+
+	overrides := make([]*model.FlagOverride, 0, len(input.Overrides))
+	for _, override := range input.Overrides {
+		overrides = append(overrides, &model.FlagOverride{FlagKey: override.FlagKey, Variant: override.Variant})
+	}
+
+	return &model.OverrideToken{
+		Token:       secret,
+		Environment: token.Environment,
+		Overrides:   overrides,
+		ExpiresAt:   token.ExpiresAt,
+	}, nil
+}
+
+// PromoteFeatureFlag is the resolver for the promoteFeatureFlag field.
+func (r *mutationResolver) PromoteFeatureFlag(ctx context.Context, flagID string, from model.Environment, to model.Environment, dryRun *bool) (*model.Promotion, error) {
+	flag, err := r.Storage.GetFeatureFlagByID(ctx, flagID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get feature flag: %w", err)
+	}
+
+	return r.promote(ctx, &model.Promotion{Project: flag.Project, FeatureFlag: flag, From: from, To: to}, dryRun)
+}
+
+// PromoteProject is the resolver for the promoteProject field.
+func (r *mutationResolver) PromoteProject(ctx context.Context, projectID string, from model.Environment, to model.Environment, dryRun *bool) (*model.Promotion, error) {
+	return r.promote(ctx, &model.Promotion{Project: &model.Project{ID: projectID}, From: from, To: to}, dryRun)
+}
+
+// ApprovePromotion is the resolver for the approvePromotion field.
+func (r *mutationResolver) ApprovePromotion(ctx context.Context, id string) (*model.Promotion, error) {
+	promotion, err := r.pendingPromotion(ctx, id, true)
+	if err != nil {
+		return nil, err
+	}
+
+	// The approver sees the changes as they are now, so those are the ones applied
+	promotion.Changes, err = r.promotionChanges(ctx, promotion)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := r.Storage.ApplyPromotion(ctx, promotion); err != nil {
+		if errors.Is(err, db.ErrConflict) {
+			return nil, conflictError("promotion was decided in the meantime")
+		}
+		return nil, fmt.Errorf("failed to apply promotion: %w", err)
+	}
+
+	return promotion, nil
+}
+
+// RejectPromotion is the resolver for the rejectPromotion field.
+func (r *mutationResolver) RejectPromotion(ctx context.Context, id string) (*model.Promotion, error) {
+	promotion, err := r.pendingPromotion(ctx, id, false)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := r.Storage.RejectPromotion(ctx, promotion); err != nil {
+		if errors.Is(err, db.ErrConflict) {
+			return nil, conflictError("promotion was decided in the meantime")
+		}
+		return nil, fmt.Errorf("failed to reject promotion: %w", err)
+	}
+
+	return promotion, nil
+}
+
+// CreateChangeSet is the resolver for the createChangeSet field.
+func (r *mutationResolver) CreateChangeSet(ctx context.Context, input model.CreateChangeSetInput) (*model.ChangeSet, error) {
+	if _, err := r.Storage.GetProjectByID(ctx, input.ProjectID); err != nil {
+		return nil, fmt.Errorf("failed to get project: %w", err)
+	}
+
+	name := strings.TrimSpace(input.Name)
+	if name == "" {
+		return nil, invalidInputError("change set name must not be empty")
+	}
+
+	changes, err := r.changeSetChanges(ctx, input.ProjectID, input.Changes)
+	if err != nil {
+		return nil, err
+	}
+
+	changeSet := &model.ChangeSet{
+		Project:     &model.Project{ID: input.ProjectID},
+		Name:        name,
+		Description: input.Description,
+		Changes:     changes,
+		CreatedBy:   userctx.GetUser(ctx),
+	}
+
+	if err := r.Storage.CreateChangeSet(ctx, changeSet); err != nil {
+		return nil, fmt.Errorf("failed to create change set: %w", err)
+	}
+
+	return changeSet, nil
+}
+
+// DeleteChangeSet is the resolver for the deleteChangeSet field.
+func (r *mutationResolver) DeleteChangeSet(ctx context.Context, id string) (bool, error) {
+	if err := r.Storage.DeleteChangeSet(ctx, id); err != nil {
+		if errors.Is(err, db.ErrConflict) {
+			return false, conflictError("only draft, rejected and failed change sets can be deleted")
+		}
+		return false, fmt.Errorf("failed to delete change set: %w", err)
+	}
+
+	return true, nil
+}
+
+// ApplyChangeSet is the resolver for the applyChangeSet field.
+func (r *mutationResolver) ApplyChangeSet(ctx context.Context, id string, at *time.Time) (*model.ChangeSet, error) {
+	changeSet, err := r.changeSetIn(ctx, id, model.ChangeSetStatusDraft, model.ChangeSetStatusFailed)
+	if err != nil {
+		return nil, err
+	}
+
+	return r.submitChangeSet(ctx, changeSet, at)
+}
+
+// ApproveChangeSet is the resolver for the approveChangeSet field.
+func (r *mutationResolver) ApproveChangeSet(ctx context.Context, id string) (*model.ChangeSet, error) {
+	changeSet, err := r.pendingChangeSet(ctx, id, true)
+	if err != nil {
+		return nil, err
+	}
+
+	expected := []model.ChangeSetStatus{model.ChangeSetStatusPendingApproval}
+	if changeSet.ScheduledAt != nil && changeSet.ScheduledAt.After(time.Now()) {
+		changeSet.Status = model.ChangeSetStatusScheduled
+		return r.updateChangeSetStatus(ctx, changeSet, expected)
+	}
+
+	// Approved after its scheduled time, or without one: apply it right away
+	return r.applyChangeSet(ctx, changeSet, expected)
+}
+
+// RejectChangeSet is the resolver for the rejectChangeSet field.
+func (r *mutationResolver) RejectChangeSet(ctx context.Context, id string) (*model.ChangeSet, error) {
+	changeSet, err := r.pendingChangeSet(ctx, id, false)
+	if err != nil {
+		return nil, err
+	}
+
+	changeSet.Status = model.ChangeSetStatusRejected
+	return r.updateChangeSetStatus(ctx, changeSet, []model.ChangeSetStatus{model.ChangeSetStatusPendingApproval})
+}
+
+// CancelChangeSet is the resolver for the cancelChangeSet field.
+func (r *mutationResolver) CancelChangeSet(ctx context.Context, id string) (*model.ChangeSet, error) {
+	expected := []model.ChangeSetStatus{model.ChangeSetStatusPendingApproval, model.ChangeSetStatusScheduled}
+	changeSet, err := r.changeSetIn(ctx, id, expected...)
+	if err != nil {
+		return nil, err
+	}
+
+	changeSet.Status = model.ChangeSetStatusDraft
+	changeSet.ScheduledAt = nil
+	changeSet.SubmittedBy = nil
+	changeSet.DecidedBy = nil
+	return r.updateChangeSetStatus(ctx, changeSet, expected)
+}
+
+// RevertChangeSet is the resolver for the revertChangeSet field.
+func (r *mutationResolver) RevertChangeSet(ctx context.Context, id string) (*model.ChangeSet, error) {
+	changeSet, err := r.changeSetIn(ctx, id, model.ChangeSetStatusApplied)
+	if err != nil {
+		return nil, err
+	}
+
+	project, err := r.Storage.GetProjectByID(ctx, changeSet.Project.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get project: %w", err)
+	}
+
+	// Reverting changes protected environments as much as applying did, so it takes another admin too
+	changeSet.RevertedBy = userctx.GetUser(ctx)
+	if changesProtected(project, changeSet) {
+		changeSet.RevertedBy, err = r.decider(ctx, project.ID, changeSet.SubmittedBy, true, "reverts of change sets in protected environments")
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if err := r.Storage.RevertChangeSet(ctx, changeSet); err != nil {
+		switch {
+		case errors.Is(err, db.ErrDiverged):
+			// Nothing was reverted, newer changes to the flags are kept
+			return nil, conflictError("%s", err)
+		case errors.Is(err, db.ErrConflict):
+			return nil, conflictError("change set was changed in the meantime")
+		}
+		return nil, fmt.Errorf("failed to revert change set: %w", err)
+	}
+
+	return changeSet, nil
+}
+
+// CreateSdkKey is the resolver for the createSdkKey field.
+func (r *mutationResolver) CreateSdkKey(ctx context.Context, input model.CreateSdkKeyInput) (*model.CreatedSdkKey, error) {
+	user := userctx.GetUser(ctx)
+
+	project, err := r.Storage.GetProjectByID(ctx, input.ProjectID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get project: %w", err)
+	}
+
+	key := &model.SdkKey{
+		Name:        input.Name,
+		Kind:        input.Kind,
+		Environment: input.Environment,
+		Project:     project,
+		CreatedBy:   user,
+	}
+
+	// Only the hash is stored, the secret is returned this one time
+	secret, keyHash, err := auth.NewSdkKey(key)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate sdk key: %w", err)
+	}
+
+	if err := r.Storage.CreateSdkKey(ctx, key, keyHash); err != nil {
+		return nil, fmt.Errorf("failed to create sdk key: %w", err)
+	}
+
+	return &model.CreatedSdkKey{SdkKey: key, Secret: secret}, nil
+}
+
+// RotateSdkKey is the resolver for the rotateSdkKey field.
+func (r *mutationResolver) RotateSdkKey(ctx context.Context, id string, gracePeriodMinutes *int) (*model.CreatedSdkKey, error) {
+	user := userctx.GetUser(ctx)
+
+	old, err := r.Storage.GetSdkKeyByID(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get sdk key: %w", err)
+	}
+
+	now := time.Now()
+	if !auth.IsActive(old, now) {
+		return nil, fmt.Errorf("sdk key %s is no longer active", id)
+	}
+
+	// The old key expires once the grace period is over, giving apps time to pick up the new one
+	oldExpiresAt := now
+	if gracePeriodMinutes != nil {
+		if *gracePeriodMinutes < 0 {
+			return nil, fmt.Errorf("grace period must not be negative")
+		}
+		oldExpiresAt = now.Add(time.Duration(*gracePeriodMinutes) * time.Minute)
+	}
+	if old.ExpiresAt != nil && old.ExpiresAt.Before(oldExpiresAt) {
+		oldExpiresAt = *old.ExpiresAt
+	}
+
+	key := &model.SdkKey{
+		Name:        old.Name,
+		Kind:        old.Kind,
+		Environment: old.Environment,
+		Project:     old.Project,
+		CreatedBy:   user,
+	}
+
+	secret, keyHash, err := auth.NewSdkKey(key)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate sdk key: %w", err)
+	}
+
+	if err := r.Storage.RotateSdkKey(ctx, old.ID, oldExpiresAt, key, keyHash); err != nil {
+		return nil, fmt.Errorf("failed to rotate sdk key: %w", err)
+	}
+
+	return &model.CreatedSdkKey{SdkKey: key, Secret: secret}, nil
+}
+
+// RevokeSdkKey is the resolver for the revokeSdkKey field.
+func (r *mutationResolver) RevokeSdkKey(ctx context.Context, id string) (*model.SdkKey, error) {
+	if err := r.Storage.RevokeSdkKey(ctx, id); err != nil {
+		return nil, fmt.Errorf("failed to revoke sdk key: %w", err)
+	}
+
+	key, err := r.Storage.GetSdkKeyByID(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get sdk key: %w", err)
+	}
+
+	return key, nil
+}
+
+// CreateWebhook is the resolver for the createWebhook field.
+func (r *mutationResolver) CreateWebhook(ctx context.Context, input model.CreateWebhookInput) (*model.CreatedWebhook, error) {
+	user := userctx.GetUser(ctx)
+
+	project, err := r.Storage.GetProjectByID(ctx, input.ProjectID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get project: %w", err)
+	}
+
+	if err := r.requireAdmin(ctx, project.ID, "add webhooks"); err != nil {
+		return nil, err
+	}
+
+	hookURL, err := webhookURL(input.URL)
+	if err != nil {
+		return nil, err
+	}
+
+	eventTypes := model.AllWebhookEventType
+	if input.EventTypes != nil {
+		if eventTypes, err = webhookEventTypes(input.EventTypes); err != nil {
+			return nil, err
+		}
+	}
+
+	// The secret is stored to sign deliveries, it is only returned this one time
+	var secret string
+	if input.Secret != nil {
+		secret = *input.Secret
+		if err := webhookSecret(secret); err != nil {
+			return nil, err
+		}
+	} else if secret, err = webhooks.NewSecret(); err != nil {
+		return nil, err
+	}
+
+	hook := &model.Webhook{
+		Project:    project,
+		URL:        hookURL,
+		EventTypes: eventTypes,
+		Active:     true,
+		CreatedBy:  user,
+	}
+
+	if err := r.Storage.CreateWebhook(ctx, hook, secret); err != nil {
+		return nil, fmt.Errorf("failed to create webhook: %w", err)
+	}
+
+	return &model.CreatedWebhook{Webhook: hook, Secret: secret}, nil
+}
+
+// UpdateWebhook is the resolver for the updateWebhook field.
+func (r *mutationResolver) UpdateWebhook(ctx context.Context, id string, input model.UpdateWebhookInput) (*model.Webhook, error) {
+	hook, err := r.Storage.GetWebhookByID(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get webhook: %w", err)
+	}
+
+	if err := r.requireAdmin(ctx, hook.Project.ID, "change webhooks"); err != nil {
+		return nil, err
+	}
+
+	if input.URL != nil {
+		if hook.URL, err = webhookURL(*input.URL); err != nil {
+			return nil, err
+		}
+	}
+
+	if input.EventTypes != nil {
+		if hook.EventTypes, err = webhookEventTypes(input.EventTypes); err != nil {
+			return nil, err
+		}
+	}
+
+	if input.Active != nil {
+		hook.Active = *input.Active
+	}
+
+	if input.Secret != nil {
+		if err := webhookSecret(*input.Secret); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := r.Storage.UpdateWebhook(ctx, hook, input.Secret); err != nil {
+		return nil, fmt.Errorf("failed to update webhook: %w", err)
+	}
+
+	return hook, nil
+}
+
+// DeleteWebhook is the resolver for the deleteWebhook field.
+func (r *mutationResolver) DeleteWebhook(ctx context.Context, id string) (bool, error) {
+	hook, err := r.Storage.GetWebhookByID(ctx, id)
+	if err != nil {
+		return false, fmt.Errorf("failed to get webhook: %w", err)
+	}
+
+	if err := r.requireAdmin(ctx, hook.Project.ID, "delete webhooks"); err != nil {
+		return false, err
+	}
+
+	if err := r.Storage.DeleteWebhook(ctx, id); err != nil {
+		return false, fmt.Errorf("failed to delete webhook: %w", err)
+	}
+
+	return true, nil
+}
+
+// RedeliverWebhookDelivery is the resolver for the redeliverWebhookDelivery field.
+func (r *mutationResolver) RedeliverWebhookDelivery(ctx context.Context, id string) (*model.WebhookDelivery, error) {
+	delivery, err := r.Storage.GetWebhookDelivery(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get webhook delivery: %w", err)
+	}
+
+	hook, err := r.Storage.GetWebhookByID(ctx, delivery.Webhook.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get webhook: %w", err)
+	}
+
+	if err := r.requireAdmin(ctx, hook.Project.ID, "redeliver webhook deliveries"); err != nil {
+		return nil, err
+	}
+
+	delivery, err = r.Storage.RedeliverWebhookDelivery(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to redeliver webhook delivery: %w", err)
+	}
+
+	return delivery, nil
+}
+
+// CreateExperiment is the resolver for the createExperiment field.
+func (r *mutationResolver) CreateExperiment(ctx context.Context, input model.CreateExperimentInput) (*model.Experiment, error) {
+	user := userctx.GetUser(ctx)
+
+	flag, err := r.Storage.GetFeatureFlagByID(ctx, input.FeatureFlagID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get feature flag: %w", err)
+	}
+	if flag.Status == model.FlagStatusArchived {
+		return nil, invalidInputError("feature flag %s is archived", flag.Key)
+	}
+
+	name := strings.TrimSpace(input.Name)
+	if name == "" {
+		return nil, invalidInputError("experiment name must not be empty")
+	}
+
+	experiment := &model.Experiment{
+		FeatureFlag:       flag,
+		Environment:       input.Environment,
+		Name:              name,
+		Hypothesis:        input.Hypothesis,
+		TrafficPercentage: 100,
+		Allocations:       defaultAllocations(),
+		CreatedBy:         user,
+	}
+
+	if input.TrafficPercentage != nil {
+		if err := trafficPercentage(*input.TrafficPercentage); err != nil {
+			return nil, err
+		}
+		experiment.TrafficPercentage = *input.TrafficPercentage
+	}
+
+	if input.Allocations != nil {
+		if experiment.Allocations, err = experimentAllocations(input.Allocations); err != nil {
+			return nil, err
+		}
+	}
+
+	if experiment.Metrics, err = experimentMetrics(input.Metrics); err != nil {
+		return nil, err
+	}
+
+	if input.LayerID != nil {
+		if experiment.Layer, err = r.experimentLayer(ctx, *input.LayerID, flag.Project.ID); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := r.Storage.CreateExperiment(ctx, experiment); err != nil {
+		return nil, fmt.Errorf("failed to create experiment: %w", err)
+	}
+
+	return experiment, nil
+}
+
+// UpdateExperiment is the resolver for the updateExperiment field.
+func (r *mutationResolver) UpdateExperiment(ctx context.Context, id string, input model.UpdateExperimentInput) (*model.Experiment, error) {
+	experiment, err := r.Storage.GetExperimentByID(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get experiment: %w", err)
+	}
+
+	// Changing the split or layer of a started experiment would move contexts between variants and spoil the results
+	changesLayer := input.LayerID != nil || (input.ClearLayer != nil && *input.ClearLayer)
+	if (input.TrafficPercentage != nil || input.Allocations != nil || changesLayer) && experiment.Status != model.ExperimentStatusDraft {
+		return nil, conflictError("the allocation of experiment %q is frozen once it has started", experiment.Name)
+	}
+	if input.LayerID != nil && input.ClearLayer != nil && *input.ClearLayer {
+		return nil, invalidInputError("layerId and clearLayer cannot be used together")
+	}
+
+	if input.Name != nil {
+		name := strings.TrimSpace(*input.Name)
+		if name == "" {
+			return nil, invalidInputError("experiment name must not be empty")
+		}
+		experiment.Name = name
+	}
+
+	if input.Hypothesis != nil {
+		experiment.Hypothesis = input.Hypothesis
+	}
+
+	if input.TrafficPercentage != nil {
+		if err := trafficPercentage(*input.TrafficPercentage); err != nil {
+			return nil, err
+		}
+		experiment.TrafficPercentage = *input.TrafficPercentage
+	}
+
+	if input.Allocations != nil {
+		if experiment.Allocations, err = experimentAllocations(input.Allocations); err != nil {
+			return nil, err
+		}
+	}
+
+	if input.Metrics != nil {
+		if experiment.Metrics, err = experimentMetrics(input.Metrics); err != nil {
+			return nil, err
+		}
+	}
+
+	if input.LayerID != nil {
+		flag, err := r.Storage.GetFeatureFlagByID(ctx, experiment.FeatureFlag.ID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get feature flag: %w", err)
+		}
+		if experiment.Layer, err = r.experimentLayer(ctx, *input.LayerID, flag.Project.ID); err != nil {
+			return nil, err
+		}
+	} else if changesLayer {
+		experiment.Layer = nil
+	}
+
+	if err := r.Storage.UpdateExperiment(ctx, experiment); err != nil {
+		return nil, fmt.Errorf("failed to update experiment: %w", err)
+	}
+
+	return experiment, nil
+}
+
+// StartExperiment is the resolver for the startExperiment field.
+func (r *mutationResolver) StartExperiment(ctx context.Context, id string) (*model.Experiment, error) {
+	experiment, err := r.Storage.GetExperimentByID(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get experiment: %w", err)
+	}
+
+	// Experiments in a layer take a range of its buckets that no other running one holds
+	var offset *int
+	if experiment.Layer != nil && experiment.Status == model.ExperimentStatusDraft {
+		experiments, err := r.Storage.GetLayerExperiments(ctx, experiment.Layer.ID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get layer experiments: %w", err)
+		}
+
+		start, ok := layerOffset(layerRanges(experiments, experiment.Environment), experiment.TrafficPercentage)
+		if !ok {
+			return nil, conflictError("the layer of experiment %q has no room for %d%% of traffic in %s",
+				experiment.Name, experiment.TrafficPercentage, experiment.Environment)
+		}
+		offset = &start
+	}
+
+	if err := r.Storage.StartExperiment(ctx, id, offset); errors.Is(err, db.ErrConflict) {
+		return nil, r.experimentConflict(ctx, id, model.ExperimentStatusDraft, "started")
+	} else if err != nil {
+		return nil, fmt.Errorf("failed to start experiment: %w", err)
+	}
+
+	experiment, err = r.Storage.GetExperimentByID(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get experiment: %w", err)
+	}
+
+	return experiment, nil
+}
+
+// StopExperiment is the resolver for the stopExperiment field.
+func (r *mutationResolver) StopExperiment(ctx context.Context, id string) (*model.Experiment, error) {
+	if err := r.Storage.StopExperiment(ctx, id); errors.Is(err, db.ErrConflict) {
+		return nil, r.experimentConflict(ctx, id, model.ExperimentStatusRunning, "stopped")
+	} else if err != nil {
+		return nil, fmt.Errorf("failed to stop experiment: %w", err)
+	}
+
+	experiment, err := r.Storage.GetExperimentByID(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get experiment: %w", err)
+	}
+
+	return experiment, nil
+}
+
+// DeleteExperiment is the resolver for the deleteExperiment field.
+func (r *mutationResolver) DeleteExperiment(ctx context.Context, id string) (bool, error) {
+	experiment, err := r.Storage.GetExperimentByID(ctx, id)
+	if err != nil {
+		return false, fmt.Errorf("failed to get experiment: %w", err)
+	}
+
+	if experiment.Status == model.ExperimentStatusRunning {
+		return false, conflictError("experiment %q is running, stop it before deleting it", experiment.Name)
+	}
+
+	if err := r.Storage.DeleteExperiment(ctx, id); err != nil {
+		return false, fmt.Errorf("failed to delete experiment: %w", err)
+	}
+
+	return true, nil
+}
+
+// CreateExperimentLayer is the resolver for the createExperimentLayer field.
+func (r *mutationResolver) CreateExperimentLayer(ctx context.Context, input model.CreateExperimentLayerInput) (*model.ExperimentLayer, error) {
+	if _, err := r.Storage.GetProjectByID(ctx, input.ProjectID); err != nil {
+		return nil, fmt.Errorf("failed to get project: %w", err)
+	}
+
+	name := strings.TrimSpace(input.Name)
+	if name == "" {
+		return nil, invalidInputError("layer name must not be empty")
+	}
+
+	layer := &model.ExperimentLayer{
+		Project:     &model.Project{ID: input.ProjectID},
+		Name:        name,
+		Description: input.Description,
+	}
+
+	if err := r.Storage.CreateExperimentLayer(ctx, layer); err != nil {
+		return nil, fmt.Errorf("failed to create experiment layer: %w", err)
+	}
+
+	return layer, nil
+}
+
+// UpdateExperimentLayer is the resolver for the updateExperimentLayer field.
+func (r *mutationResolver) UpdateExperimentLayer(ctx context.Context, id string, input model.UpdateExperimentLayerInput) (*model.ExperimentLayer, error) {
+	layer, err := r.Storage.GetExperimentLayerByID(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get experiment layer: %w", err)
+	}
+
+	if input.Name != nil {
+		name := strings.TrimSpace(*input.Name)
+		if name == "" {
+			return nil, invalidInputError("layer name must not be empty")
+		}
+		layer.Name = name
+	}
+
+	if input.Description != nil {
+		layer.Description = input.Description
+	}
+
+	if err := r.Storage.UpdateExperimentLayer(ctx, layer); err != nil {
+		return nil, fmt.Errorf("failed to update experiment layer: %w", err)
+	}
+
+	return layer, nil
+}
+
+// DeleteExperimentLayer is the resolver for the deleteExperimentLayer field.
+func (r *mutationResolver) DeleteExperimentLayer(ctx context.Context, id string) (bool, error) {
+	if err := r.Storage.DeleteExperimentLayer(ctx, id); errors.Is(err, db.ErrConflict) {
+		return false, conflictError("an experiment of the layer is running, stop it before deleting the layer")
+	} else if err != nil {
+		return false, fmt.Errorf("failed to delete experiment layer: %w", err)
+	}
+
+	return true, nil
+}
+
+// CreateHoldout is the resolver for the createHoldout field.
+func (r *mutationResolver) CreateHoldout(ctx context.Context, input model.CreateHoldoutInput) (*model.Holdout, error) {
+	if _, err := r.Storage.GetProjectByID(ctx, input.ProjectID); err != nil {
+		return nil, fmt.Errorf("failed to get project: %w", err)
+	}
+
+	name := strings.TrimSpace(input.Name)
+	if name == "" {
+		return nil, invalidInputError("holdout name must not be empty")
+	}
+
+	if err := holdoutPercentage(input.Percentage); err != nil {
+		return nil, err
+	}
+
+	if err := r.requireNoRunningExperiments(ctx, input.ProjectID); err != nil {
+		return nil, err
+	}
+
+	holdout := &model.Holdout{
+		Project:    &model.Project{ID: input.ProjectID},
+		Name:       name,
+		Percentage: input.Percentage,
+	}
+
+	if err := r.Storage.CreateHoldout(ctx, holdout); err != nil {
+		return nil, fmt.Errorf("failed to create holdout: %w", err)
+	}
+
+	return holdout, nil
+}
+
+// UpdateHoldout is the resolver for the updateHoldout field.
+func (r *mutationResolver) UpdateHoldout(ctx context.Context, id string, input model.UpdateHoldoutInput) (*model.Holdout, error) {
+	holdout, err := r.Storage.GetHoldoutByID(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get holdout: %w", err)
+	}
+
+	if input.Name != nil {
+		name := strings.TrimSpace(*input.Name)
+		if name == "" {
+			return nil, invalidInputError("holdout name must not be empty")
+		}
+		holdout.Name = name
+	}
+
+	// Renaming is harmless, a new percentage would move contexts in or out of running experiments
+	if input.Percentage != nil && *input.Percentage != holdout.Percentage {
+		if err := holdoutPercentage(*input.Percentage); err != nil {
+			return nil, err
+		}
+		if err := r.requireNoRunningExperiments(ctx, holdout.Project.ID); err != nil {
+			return nil, err
+		}
+		holdout.Percentage = *input.Percentage
+	}
+
+	if err := r.Storage.UpdateHoldout(ctx, holdout); err != nil {
+		return nil, fmt.Errorf("failed to update holdout: %w", err)
+	}
+
+	return holdout, nil
+}
+
+// DeleteHoldout is the resolver for the deleteHoldout field.
+func (r *mutationResolver) DeleteHoldout(ctx context.Context, id string) (bool, error) {
+	holdout, err := r.Storage.GetHoldoutByID(ctx, id)
+	if err != nil {
+		return false, fmt.Errorf("failed to get holdout: %w", err)
+	}
+
+	if err := r.requireNoRunningExperiments(ctx, holdout.Project.ID); err != nil {
+		return false, err
+	}
+
+	if err := r.Storage.DeleteHoldout(ctx, id); err != nil {
+		return false, fmt.Errorf("failed to delete holdout: %w", err)
+	}
+
+	return true, nil
+}
+
+// CreateServiceAccount is the resolver for the createServiceAccount field.
+func (r *mutationResolver) CreateServiceAccount(ctx context.Context, name string) (*model.User, error) {
+	id := uuid.New().String()
+
+	// Service accounts never log in, the address only has to be unique
+	account := &model.User{
+		ID:        id,
+		Name:      name,
+		Email:     id + "@service-accounts.feature-toggler",
+		Kind:      model.UserKindServiceAccount,
+		CreatedBy: userctx.GetUser(ctx),
+	}
+
+	if err := r.Storage.CreateUser(ctx, account); err != nil {
+		return nil, fmt.Errorf("failed to create service account: %w", err)
+	}
+
+	return account, nil
+}
+
+// CreateAccessToken is the resolver for the createAccessToken field.
+func (r *mutationResolver) CreateAccessToken(ctx context.Context, input model.CreateAccessTokenInput) (*model.CreatedAccessToken, error) {
+	owner, err := r.tokenOwner(ctx, input.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(input.Scopes) == 0 {
+		return nil, fmt.Errorf("an access token needs at least one scope")
+	}
+
+	if slices.Contains(input.Scopes, model.TokenScopeAdmin) {
+		allowed, err := r.canGrantAdminScope(ctx, owner)
+		if err != nil {
+			return nil, err
+		}
+		if !allowed {
+			return nil, forbiddenError("only admins can issue access tokens with the %s scope", model.TokenScopeAdmin)
+		}
+	}
+
+	token := &model.AccessToken{
+		Name:   input.Name,
+		User:   owner,
+		Scopes: input.Scopes,
+	}
+
+	if input.ExpiresInDays != nil {
+		if *input.ExpiresInDays <= 0 {
+			return nil, fmt.Errorf("expiresInDays must be positive")
+		}
+		expiresAt := time.Now().AddDate(0, 0, *input.ExpiresInDays)
+		token.ExpiresAt = &expiresAt
+	}
+
+	// Only the hash is stored, the secret is returned this one time
+	secret, tokenHash, err := auth.NewAccessToken(token)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate access token: %w", err)
+	}
+
+	if err := r.Storage.CreateAccessToken(ctx, token, tokenHash); err != nil {
+		return nil, fmt.Errorf("failed to create access token: %w", err)
+	}
+
+	return &model.CreatedAccessToken{AccessToken: token, Secret: secret}, nil
+}
+
+// RevokeAccessToken is the resolver for the revokeAccessToken field.
+func (r *mutationResolver) RevokeAccessToken(ctx context.Context, id string) (*model.AccessToken, error) {
+	token, err := r.Storage.GetAccessTokenByID(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get access token: %w", err)
+	}
+
+	if _, err := r.tokenOwner(ctx, &token.User.ID); err != nil {
+		return nil, err
+	}
+
+	if err := r.Storage.RevokeAccessToken(ctx, id); err != nil {
+		return nil, fmt.Errorf("failed to revoke access token: %w", err)
+	}
+
+	return r.Storage.GetAccessTokenByID(ctx, id)
+}
+
+// Members is the resolver for the members field.
+func (r *projectResolver) Members(ctx context.Context, obj *model.Project) ([]*model.ProjectUser, error) {
+	members, err := r.loaders(ctx).Members.Load(ctx, obj.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get project members: %w", err)
+	}
+
+	return members, nil
+}
+
+// ProtectionChanges is the resolver for the protection_changes field.
+func (r *projectResolver) ProtectionChanges(ctx context.Context, obj *model.Project) ([]*model.ProtectionChange, error) {
+	changes, err := r.Storage.GetProtectionChanges(ctx, obj.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get protection changes: %w", err)
+	}
+
+	return changes, nil
+}
+
+// FeatureFlags is the resolver for the featureFlags field.
+func (r *projectResolver) FeatureFlags(ctx context.Context, obj *model.Project, filter *model.FeatureFlagFilter, orderBy *model.FeatureFlagOrder, first *int, after *string) (*model.FeatureFlagConnection, error) {
+	page, err := pageFor(first, after)
+	if err != nil {
+		return nil, err
+	}
+
+	var where model.FeatureFlagFilter
+	if filter != nil {
+		where = *filter
+	}
+	if where.StaleAfterDays == nil {
+		where.StaleAfterDays = obj.StaleAfterDays
+	}
+	if where.Tags, err = normalizeTags(where.Tags); err != nil {
+		return nil, err
+	}
+
+	order := model.FeatureFlagOrder{Field: model.FeatureFlagOrderFieldKey, Direction: model.OrderDirectionAsc}
+	if orderBy != nil {
+		order = *orderBy
+	}
+
+	flags, total, err := r.Storage.QueryFeatureFlags(ctx, obj.ID, where, order, page)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get feature flags: %w", err)
+	}
+
+	edges := make([]*model.FeatureFlagEdge, 0, len(flags))
+	for i, flag := range flags {
+		edges = append(edges, &model.FeatureFlagEdge{Cursor: cursorAt(page.Offset + i), Node: flag})
+	}
+
+	return &model.FeatureFlagConnection{
+		Edges:      edges,
+		PageInfo:   pageInfo(page, len(edges), total),
+		TotalCount: total,
+	}, nil
+}
+
+// Project is the resolver for the project field.
+func (r *projectUserResolver) Project(ctx context.Context, obj *model.ProjectUser) (*model.Project, error) {
+	project, err := r.loaders(ctx).Projects.Load(ctx, obj.Project.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get project: %w", err)
+	}
+
+	return project, nil
+}
+
+// Project is the resolver for the project field.
+func (r *promotionResolver) Project(ctx context.Context, obj *model.Promotion) (*model.Project, error) {
+	project, err := r.loaders(ctx).Projects.Load(ctx, obj.Project.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get project: %w", err)
+	}
+
+	return project, nil
+}
+
+// FeatureFlag is the resolver for the feature_flag field.
+func (r *promotionResolver) FeatureFlag(ctx context.Context, obj *model.Promotion) (*model.FeatureFlag, error) {
+	if obj.FeatureFlag == nil {
+		return nil, nil
+	}
+
+	return r.optionalFlag(ctx, obj.FeatureFlag.ID)
+}
+
+// Changes is the resolver for the changes field.
+func (r *promotionResolver) Changes(ctx context.Context, obj *model.Promotion) ([]*model.FlagStateChange, error) {
+	if obj.Status != model.PromotionStatusPendingApproval {
+		return obj.Changes, nil
+	}
+
+	// Pending promotions show what approving them would change now
+	if obj.FeatureFlag != nil {
+		if flag, err := r.optionalFlag(ctx, obj.FeatureFlag.ID); err != nil || flag == nil {
+			return []*model.FlagStateChange{}, err
+		}
+	}
+	return r.promotionChanges(ctx, obj)
+}
+
+// RequestedBy is the resolver for the requested_by field.
+func (r *promotionResolver) RequestedBy(ctx context.Context, obj *model.Promotion) (*model.User, error) {
+	user, err := r.loaders(ctx).Users.Load(ctx, obj.RequestedBy.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user: %w", err)
+	}
+
+	return user, nil
+}
+
+// DecidedBy is the resolver for the decided_by field.
+func (r *promotionResolver) DecidedBy(ctx context.Context, obj *model.Promotion) (*model.User, error) {
+	if obj.DecidedBy == nil {
+		return nil, nil
+	}
+
+	user, err := r.loaders(ctx).Users.Load(ctx, obj.DecidedBy.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user: %w", err)
+	}
+
+	return user, nil
+}
+
+// ChangedBy is the resolver for the changed_by field.
+func (r *protectionChangeResolver) ChangedBy(ctx context.Context, obj *model.ProtectionChange) (*model.User, error) {
+	user, err := r.loaders(ctx).Users.Load(ctx, obj.ChangedBy.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user: %w", err)
+	}
+
+	return user, nil
+}
+
+// Me is the resolver for the me field.
+func (r *queryResolver) Me(ctx context.Context) (*model.User, error) {
+	user := userctx.GetUser(ctx)
+	
+	// Get complete user data from database
+	dbUser, err := r.Storage.GetUserByID(ctx, user.ID)
+	if err != nil {
+		// If user not found in db yet, just return the context user
+		return user, nil
+	}
+	
+	return dbUser, nil
+}
+
+// Projects is the resolver for the projects field.
+func (r *queryResolver) Projects(ctx context.Context, first *int, after *string) (*model.ProjectConnection, error) {
+	page, err := pageFor(first, after)
+	if err != nil {
+		return nil, err
+	}
+
+	projects, total, err := r.Storage.GetProjects(ctx, page)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get projects: %w", err)
+	}
+
+	edges := make([]*model.ProjectEdge, 0, len(projects))
+	for i, project := range projects {
+		edges = append(edges, &model.ProjectEdge{Cursor: cursorAt(page.Offset + i), Node: project})
+	}
+
+	return &model.ProjectConnection{
+		Edges:      edges,
+		PageInfo:   pageInfo(page, len(edges), total),
+		TotalCount: total,
+	}, nil
+}
+
+// Project is the resolver for the project field.
+func (r *queryResolver) Project(ctx context.Context, id string) (*model.Project, error) {
+	// Use storage to get project by ID
+	project, err := r.Storage.GetProjectByID(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get project: %w", err)
+	}
+	
+	return project, nil
+}
+
+// FeatureFlag is the resolver for the feature_flag field.
+func (r *queryResolver) FeatureFlag(ctx context.Context, id string) (*model.FeatureFlag, error) {
+	// Use storage to get feature flag by ID
+	flag, err := r.Storage.GetFeatureFlagByID(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get feature flag: %w", err)
+	}
+	
+	return flag, nil
+}
+
+// FeatureFlagByKey is the resolver for the feature_flag_by_key field.
+func (r *queryResolver) FeatureFlagByKey(ctx context.Context, projectID string, key string) (*model.FeatureFlag, error) {
+	// Use storage to get feature flag by key
+	flag, err := r.Storage.GetFeatureFlagByKey(ctx, projectID, key)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get feature flag by key: %w", err)
+	}
+
+	return flag, nil
+}
+
+// FeatureFlags is the resolver for the feature_flags field.
+func (r *queryResolver) FeatureFlags(ctx context.Context, projectID string, status *model.FlagStatus) ([]*model.FeatureFlag, error) {
+	flags, err := r.Storage.GetProjectFeatureFlags(ctx, projectID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get feature flags: %w", err)
+	}
+
+	wanted := model.FlagStatusActive
+	if status != nil {
+		wanted = *status
+	}
+
+	filtered := make([]*model.FeatureFlag, 0, len(flags))
+	for _, flag := range flags {
+		if flag.Status != wanted {
+			continue
+		}
+
+		filtered = append(filtered, flag)
+	}
+
+	return filtered, nil
+}
+
+// SdkKeys is the resolver for the sdk_keys field.
+func (r *queryResolver) SdkKeys(ctx context.Context, projectID string, environment *model.Environment) ([]*model.SdkKey, error) {
+	keys, err := r.Storage.GetProjectSdkKeys(ctx, projectID, environment)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get sdk keys: %w", err)
+	}
+
+	return keys, nil
+}
+
+// ProjectInvitations is the resolver for the project_invitations field.
+func (r *queryResolver) ProjectInvitations(ctx context.Context, projectID string, status *model.InvitationStatus) ([]*model.ProjectInvitation, error) {
+	invitations, err := r.Storage.GetProjectInvitations(ctx, projectID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get invitations: %w", err)
+	}
+
+	if status == nil {
+		return invitations, nil
+	}
+
+	filtered := make([]*model.ProjectInvitation, 0, len(invitations))
+	for _, invitation := range invitations {
+		if invitation.Status == *status {
+			filtered = append(filtered, invitation)
+		}
+	}
+
+	return filtered, nil
+}
+
+// MyInvitations is the resolver for the my_invitations field.
+func (r *queryResolver) MyInvitations(ctx context.Context) ([]*model.ProjectInvitation, error) {
+	user := userctx.GetUser(ctx)
+
+	invitations, err := r.Storage.GetInvitationsByEmail(ctx, user.Email)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get invitations: %w", err)
+	}
 
-	"github.com/shubham-tomar/feature-toggler/graphQl/generated"
-	"github.com/shubham-tomar/feature-toggler/graphQl/model"
-)
+	pending := make([]*model.ProjectInvitation, 0, len(invitations))
+	for _, invitation := range invitations {
+		if invitation.Status == model.InvitationStatusPending {
+			pending = append(pending, invitation)
+		}
+	}
 
-// CreateProject is the resolver for the createProject field.
-func (r *mutationResolver) CreateProject(ctx context.Context, name string) (*model.Project, error) {
-	user := userctx.GetUser(ctx)
-	return r.Storage.CreateProject(ctx, user, name)
+	return pending, nil
 }
 
-// CreateUser is the resolver for the createUser field.
-func (r *mutationResolver) CreateUser(ctx context.Context, input model.CreateUserInput) (*model.User, error) {
-	panic(fmt.Errorf("not implemented: CreateUser - createUser"))
+// ServiceAccounts is the resolver for the service_accounts field.
+func (r *queryResolver) ServiceAccounts(ctx context.Context) ([]*model.User, error) {
+	return r.Storage.GetServiceAccounts(ctx)
 }
 
-// UpdateUser is the resolver for the updateUser field.
-func (r *mutationResolver) UpdateUser(ctx context.Context, id string, input model.UpdateUserInput) (*model.User, error) {
-	panic(fmt.Errorf("not implemented: UpdateUser - updateUser"))
-}
+// AccessTokens is the resolver for the access_tokens field.
+func (r *queryResolver) AccessTokens(ctx context.Context, userID *string) ([]*model.AccessToken, error) {
+	owner, err := r.tokenOwner(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
 
-// UpdateProject is the resolver for the updateProject field.
-func (r *mutationResolver) UpdateProject(ctx context.Context, id string, input model.UpdateProjectInput) (*model.Project, error) {
-	panic(fmt.Errorf("not implemented: UpdateProject - updateProject"))
+	tokens, err := r.Storage.GetUserAccessTokens(ctx, owner.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get access tokens: %w", err)
+	}
+
+	return tokens, nil
 }
 
-// DeleteProject is the resolver for the deleteProject field.
-func (r *mutationResolver) DeleteProject(ctx context.Context, id string) (bool, error) {
-	panic(fmt.Errorf("not implemented: DeleteProject - deleteProject"))
+// StaleFlags is the resolver for the staleFlags field.
+func (r *queryResolver) StaleFlags(ctx context.Context, projectID string, statuses []model.FlagHealthStatus) ([]*model.FeatureFlag, error) {
+	project, err := r.Storage.GetProjectByID(ctx, projectID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get project: %w", err)
+	}
+
+	if len(statuses) == 0 {
+		statuses = defaultStaleStatuses
+	}
+
+	assessments, err := health.Assess(ctx, r.Storage, project, time.Now())
+	if err != nil {
+		return nil, err
+	}
+
+	flags := []*model.FeatureFlag{}
+	for _, a := range assessments {
+		if slices.Contains(statuses, a.Health.Status) {
+			flags = append(flags, a.Flag)
+		}
+	}
+
+	return flags, nil
 }
 
-// AddProjectMember is the resolver for the addProjectMember field.
-func (r *mutationResolver) AddProjectMember(ctx context.Context, input model.AddProjectMemberInput) (*model.ProjectUser, error) {
-	panic(fmt.Errorf("not implemented: AddProjectMember - addProjectMember"))
+// Webhooks is the resolver for the webhooks field.
+func (r *queryResolver) Webhooks(ctx context.Context, projectID string) ([]*model.Webhook, error) {
+	hooks, err := r.Storage.GetProjectWebhooks(ctx, projectID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get webhooks: %w", err)
+	}
+
+	return hooks, nil
 }
 
-// UpdateProjectMember is the resolver for the updateProjectMember field.
-func (r *mutationResolver) UpdateProjectMember(ctx context.Context, id string, role model.Role) (*model.ProjectUser, error) {
-	panic(fmt.Errorf("not implemented: UpdateProjectMember - updateProjectMember"))
+// Webhook is the resolver for the webhook field.
+func (r *queryResolver) Webhook(ctx context.Context, id string) (*model.Webhook, error) {
+	hook, err := r.Storage.GetWebhookByID(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get webhook: %w", err)
+	}
+
+	return hook, nil
 }
 
-// RemoveProjectMember is the resolver for the removeProjectMember field.
-func (r *mutationResolver) RemoveProjectMember(ctx context.Context, id string) (bool, error) {
-	panic(fmt.Errorf("not implemented: RemoveProjectMember - removeProjectMember"))
+// Experiments is the resolver for the experiments field.
+func (r *queryResolver) Experiments(ctx context.Context, projectID string, status *model.ExperimentStatus) ([]*model.Experiment, error) {
+	experiments, err := r.Storage.GetProjectExperiments(ctx, projectID, status)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get experiments: %w", err)
+	}
+
+	return experiments, nil
 }
 
-// CreateFeatureFlag is the resolver for the createFeatureFlag field.
-func (r *mutationResolver) CreateFeatureFlag(ctx context.Context, input model.CreateFeatureFlagInput) (*model.FeatureFlag, error) {
-	user := userctx.GetUser(ctx)
-	
-	// Create the feature flag
-	flag := &model.FeatureFlag{
-		ID:          uuid.New().String(),
-		Key:         input.Key,
-		Name:        input.Name,
-		Description: input.Description,
-		Project:     &model.Project{ID: input.ProjectID},
-		CreatedBy:   user,
+// Experiment is the resolver for the experiment field.
+func (r *queryResolver) Experiment(ctx context.Context, id string) (*model.Experiment, error) {
+	experiment, err := r.Storage.GetExperimentByID(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get experiment: %w", err)
 	}
-	
-	// Create toggle states for each environment
-	var states []*model.ToggleState
-	for _, env := range input.InitialStates {
-		state := &model.ToggleState{
-			ID:          uuid.New().String(),
-			FeatureFlag: flag,
-			Environment: env.Environment,
-			Enabled:     env.Enabled,
-			UpdatedBy:   user,
-		}
-		states = append(states, state)
+
+	return experiment, nil
+}
+
+// ExperimentLayers is the resolver for the experiment_layers field.
+func (r *queryResolver) ExperimentLayers(ctx context.Context, projectID string) ([]*model.ExperimentLayer, error) {
+	layers, err := r.Storage.GetProjectExperimentLayers(ctx, projectID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get experiment layers: %w", err)
 	}
-	
-	// Use storage interface to create feature flag with states
-	if err := r.Storage.CreateFeatureFlag(ctx, flag, states); err != nil {
-		return nil, fmt.Errorf("failed to create feature flag: %w", err)
+
+	return layers, nil
+}
+
+// Holdouts is the resolver for the holdouts field.
+func (r *queryResolver) Holdouts(ctx context.Context, projectID string) ([]*model.Holdout, error) {
+	holdouts, err := r.Storage.GetProjectHoldouts(ctx, projectID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get holdouts: %w", err)
 	}
-	
-	// Get complete feature flag with all related data
-	flag, err := r.Storage.GetFeatureFlagByID(ctx, flag.ID)
+
+	return holdouts, nil
+}
+
+// ExplainEvaluation is the resolver for the explainEvaluation field.
+func (r *queryResolver) ExplainEvaluation(ctx context.Context, projectID string, flagKey string, environment model.Environment, context map[string]any) (*model.EvaluationExplanation, error) {
+	flag, err := r.Storage.GetFeatureFlagByKey(ctx, projectID, flagKey)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get feature flag: %w", err)
 	}
-	
-	return flag, nil
+
+	evalCtx := evaluation.Context(context)
+	evaluator := &evaluation.Evaluator{Storage: r.Storage}
+	scope := evaluation.Scope{ProjectID: projectID, Environment: environment}
+
+	targets, err := evaluator.Targets(ctx, scope, evalCtx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get individual targets: %w", err)
+	}
+
+	experiments, err := evaluator.Experiments(ctx, scope)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get experiments: %w", err)
+	}
+
+	return evaluation.Explain(flag, environment, evalCtx, targets, experiments), nil
 }
 
-// UpdateFeatureFlag is the resolver for the updateFeatureFlag field.
-func (r *mutationResolver) UpdateFeatureFlag(ctx context.Context, id string, input model.UpdateFeatureFlagInput) (*model.FeatureFlag, error) {
-	panic(fmt.Errorf("not implemented: UpdateFeatureFlag - updateFeatureFlag"))
+// SimulateFeatureFlag is the resolver for the simulateFeatureFlag field.
+func (r *queryResolver) SimulateFeatureFlag(ctx context.Context, input model.SimulateFeatureFlagInput) (*model.FlagSimulation, error) {
+	flag, err := r.Storage.GetFeatureFlagByID(ctx, input.FeatureFlagID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get feature flag: %w", err)
+	}
+	if flag.Status == model.FlagStatusArchived {
+		return nil, invalidInputError("feature flag %s is archived", flag.Key)
+	}
+
+	contexts, err := simulationSample(input)
+	if err != nil {
+		return nil, err
+	}
+
+	evaluator := &evaluation.Evaluator{Storage: r.Storage}
+	experiments, err := evaluator.Experiments(ctx, evaluation.Scope{ProjectID: flag.Project.ID, Environment: input.Environment})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get experiments: %w", err)
+	}
+
+	targets, err := r.flagTargets(ctx, flag.ID, input.Environment)
+	if err != nil {
+		return nil, err
+	}
+
+	current := simulation.Config{Flag: flag, Targets: targets, Experiments: experiments}
+	proposed, err := proposedConfig(current, input.Environment, input.Proposed)
+	if err != nil {
+		return nil, err
+	}
+
+	return simulation.Run(current, proposed, input.Environment, contexts), nil
 }
 
-// DeleteFeatureFlag is the resolver for the deleteFeatureFlag field.
-func (r *mutationResolver) DeleteFeatureFlag(ctx context.Context, id string) (bool, error) {
-	panic(fmt.Errorf("not implemented: DeleteFeatureFlag - deleteFeatureFlag"))
+// Promotions is the resolver for the promotions field.
+func (r *queryResolver) Promotions(ctx context.Context, projectID string, status *model.PromotionStatus) ([]*model.Promotion, error) {
+	promotions, err := r.Storage.GetProjectPromotions(ctx, projectID, status)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get promotions: %w", err)
+	}
+
+	return promotions, nil
 }
 
-// ToggleFeatureFlag is the resolver for the toggleFeatureFlag field.
-func (r *mutationResolver) ToggleFeatureFlag(ctx context.Context, input model.ToggleFeatureFlagInput) (*model.ToggleState, error) {
-	user := userctx.GetUser(ctx)
-	
-	// Get existing toggle state
-	flag, err := r.Storage.GetFeatureFlagByID(ctx, input.FeatureFlagID)
+// CompareEnvironments is the resolver for the compareEnvironments field.
+func (r *queryResolver) CompareEnvironments(ctx context.Context, projectID string, a model.Environment, b model.Environment) (*model.EnvironmentComparison, error) {
+	if a == b {
+		return nil, invalidInputError("cannot compare %s with itself", a)
+	}
+
+	if _, err := r.Storage.GetProjectByID(ctx, projectID); err != nil {
+		return nil, fmt.Errorf("failed to get project: %w", err)
+	}
+
+	flags, err := r.Storage.GetProjectFeatureFlags(ctx, projectID)
 	if err != nil {
-		return nil, fmt.Errorf("failed to find feature flag with ID %s: %w", input.FeatureFlagID, err)
+		return nil, fmt.Errorf("failed to get feature flags: %w", err)
 	}
-	
-	// Find state for the environment
-	var state *model.ToggleState
-	for _, s := range flag.States {
-		if s.Environment == input.Environment {
-			state = s
-			break
+
+	comparison := &model.EnvironmentComparison{A: a, B: b, Drift: []*model.FlagDrift{}}
+	for _, flag := range flags {
+		if flag.Status == model.FlagStatusArchived {
+			continue
+		}
+		if flag.States, err = r.Storage.GetFeatureFlagStates(ctx, flag.ID); err != nil {
+			return nil, fmt.Errorf("failed to get toggle states: %w", err)
+		}
+
+		comparison.Compared++
+		drift, err := r.flagDrift(ctx, flag, a, b)
+		if err != nil {
+			return nil, err
+		}
+		if drift != nil {
+			comparison.Drift = append(comparison.Drift, drift)
 		}
 	}
-	
-	if state == nil {
-		return nil, fmt.Errorf("no toggle state found for environment %s", input.Environment)
+
+	slices.SortFunc(comparison.Drift, func(x, y *model.FlagDrift) int {
+		return strings.Compare(x.FeatureFlag.Key, y.FeatureFlag.Key)
+	})
+	return comparison, nil
+}
+
+// ChangeSets is the resolver for the changeSets field.
+func (r *queryResolver) ChangeSets(ctx context.Context, projectID string, status *model.ChangeSetStatus) ([]*model.ChangeSet, error) {
+	changeSets, err := r.Storage.GetProjectChangeSets(ctx, projectID, status)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get change sets: %w", err)
 	}
-	
-	// Update toggle state
-	state.Enabled = input.Enabled
-	state.UpdatedBy = user
-	
-	// Save the updated state
-	if err := r.Storage.UpdateFeatureFlagState(ctx, state); err != nil {
-		return nil, fmt.Errorf("failed to update toggle state: %w", err)
+
+	return changeSets, nil
+}
+
+// ChangeSet is the resolver for the changeSet field.
+func (r *queryResolver) ChangeSet(ctx context.Context, id string) (*model.ChangeSet, error) {
+	changeSet, err := r.Storage.GetChangeSetByID(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get change set: %w", err)
 	}
-	
-	return state, nil
+
+	return changeSet, nil
 }
 
-// Me is the resolver for the me field.
-func (r *queryResolver) Me(ctx context.Context) (*model.User, error) {
-	user := userctx.GetUser(ctx)
-	
-	// Get complete user data from database
-	dbUser, err := r.Storage.GetUserByID(ctx, user.ID)
+// UpdatedBy is the resolver for the updated_by field.
+func (r *toggleStateResolver) UpdatedBy(ctx context.Context, obj *model.ToggleState) (*model.User, error) {
+	user, err := r.loaders(ctx).Users.Load(ctx, obj.UpdatedBy.ID)
 	if err != nil {
-		// If user not found in db yet, just return the context user
-		return user, nil
+		return nil, fmt.Errorf("failed to get user: %w", err)
 	}
-	
-	return dbUser, nil
+
+	return user, nil
 }
 
-// Projects is the resolver for the projects field.
-func (r *queryResolver) Projects(ctx context.Context) ([]*model.Project, error) {
-	return r.Storage.GetProjects(ctx)
+// Targets is the resolver for the targets field.
+func (r *toggleStateResolver) Targets(ctx context.Context, obj *model.ToggleState, variant *string, first *int, after *string) (*model.IndividualTargetConnection, error) {
+	page, err := pageFor(first, after)
+	if err != nil {
+		return nil, err
+	}
+
+	targets, total, err := r.Storage.GetIndividualTargets(ctx, obj.FeatureFlag.ID, obj.Environment, variant, page)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get individual targets: %w", err)
+	}
+
+	edges := make([]*model.IndividualTargetEdge, 0, len(targets))
+	for i, target := range targets {
+		edges = append(edges, &model.IndividualTargetEdge{Cursor: cursorAt(page.Offset + i), Node: target})
+	}
+
+	return &model.IndividualTargetConnection{
+		Edges:      edges,
+		PageInfo:   pageInfo(page, len(edges), total),
+		TotalCount: total,
+	}, nil
+}
+
+// CreatedBy is the resolver for the created_by field.
+func (r *userResolver) CreatedBy(ctx context.Context, obj *model.User) (*model.User, error) {
+	if obj.CreatedBy == nil {
+		return nil, nil
+	}
+
+	user, err := r.loaders(ctx).Users.Load(ctx, obj.CreatedBy.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user: %w", err)
+	}
+
+	return user, nil
+}
+
+// ProjectMemberships is the resolver for the project_memberships field.
+func (r *userResolver) ProjectMemberships(ctx context.Context, obj *model.User) ([]*model.ProjectUser, error) {
+	memberships, err := r.Storage.GetUserMemberships(ctx, obj.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get project memberships: %w", err)
+	}
+
+	return memberships, nil
 }
 
 // Project is the resolver for the project field.
-func (r *queryResolver) Project(ctx context.Context, id string) (*model.Project, error) {
-	// Use storage to get project by ID
-	project, err := r.Storage.GetProjectByID(ctx, id)
+func (r *webhookResolver) Project(ctx context.Context, obj *model.Webhook) (*model.Project, error) {
+	project, err := r.loaders(ctx).Projects.Load(ctx, obj.Project.ID)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get project: %w", err)
 	}
-	
+
 	return project, nil
 }
 
-// FeatureFlag is the resolver for the feature_flag field.
-func (r *queryResolver) FeatureFlag(ctx context.Context, id string) (*model.FeatureFlag, error) {
-	// Use storage to get feature flag by ID
-	flag, err := r.Storage.GetFeatureFlagByID(ctx, id)
+// CreatedBy is the resolver for the created_by field.
+func (r *webhookResolver) CreatedBy(ctx context.Context, obj *model.Webhook) (*model.User, error) {
+	user, err := r.loaders(ctx).Users.Load(ctx, obj.CreatedBy.ID)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get feature flag: %w", err)
+		return nil, fmt.Errorf("failed to get user: %w", err)
 	}
-	
-	return flag, nil
+
+	return user, nil
 }
 
-// FeatureFlagByKey is the resolver for the feature_flag_by_key field.
-func (r *queryResolver) FeatureFlagByKey(ctx context.Context, key string) (*model.FeatureFlag, error) {
-	// Use storage to get feature flag by key
-	flag, err := r.Storage.GetFeatureFlagByKey(ctx, key)
+// Deliveries is the resolver for the deliveries field.
+func (r *webhookResolver) Deliveries(ctx context.Context, obj *model.Webhook, status *model.WebhookDeliveryStatus, first *int, after *string) (*model.WebhookDeliveryConnection, error) {
+	page, err := pageFor(first, after)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get feature flag by key: %w", err)
+		return nil, err
 	}
-	
-	return flag, nil
+
+	deliveries, total, err := r.Storage.GetWebhookDeliveries(ctx, obj.ID, status, page)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get webhook deliveries: %w", err)
+	}
+
+	edges := make([]*model.WebhookDeliveryEdge, 0, len(deliveries))
+	for i, delivery := range deliveries {
+		edges = append(edges, &model.WebhookDeliveryEdge{Cursor: cursorAt(page.Offset + i), Node: delivery})
+	}
+
+	return &model.WebhookDeliveryConnection{
+		Edges:      edges,
+		PageInfo:   pageInfo(page, len(edges), total),
+		TotalCount: total,
+	}, nil
+}
+
+// Webhook is the resolver for the webhook field.
+func (r *webhookDeliveryResolver) Webhook(ctx context.Context, obj *model.WebhookDelivery) (*model.Webhook, error) {
+	hook, err := r.Storage.GetWebhookByID(ctx, obj.Webhook.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get webhook: %w", err)
+	}
+
+	return hook, nil
+}
+
+// ChangeSet returns generated.ChangeSetResolver implementation.
+func (r *Resolver) ChangeSet() generated.ChangeSetResolver { return &changeSetResolver{r} }
+
+// Experiment returns generated.ExperimentResolver implementation.
+func (r *Resolver) Experiment() generated.ExperimentResolver { return &experimentResolver{r} }
+
+// ExperimentLayer returns generated.ExperimentLayerResolver implementation.
+func (r *Resolver) ExperimentLayer() generated.ExperimentLayerResolver {
+	return &experimentLayerResolver{r}
+}
+
+// FeatureFlag returns generated.FeatureFlagResolver implementation.
+func (r *Resolver) FeatureFlag() generated.FeatureFlagResolver { return &featureFlagResolver{r} }
+
+// FlagChange returns generated.FlagChangeResolver implementation.
+func (r *Resolver) FlagChange() generated.FlagChangeResolver { return &flagChangeResolver{r} }
+
+// FlagEnvironmentState returns generated.FlagEnvironmentStateResolver implementation.
+func (r *Resolver) FlagEnvironmentState() generated.FlagEnvironmentStateResolver {
+	return &flagEnvironmentStateResolver{r}
+}
+
+// FlagStateChange returns generated.FlagStateChangeResolver implementation.
+func (r *Resolver) FlagStateChange() generated.FlagStateChangeResolver {
+	return &flagStateChangeResolver{r}
 }
 
+// Holdout returns generated.HoldoutResolver implementation.
+func (r *Resolver) Holdout() generated.HoldoutResolver { return &holdoutResolver{r} }
+
 // Mutation returns generated.MutationResolver implementation.
 func (r *Resolver) Mutation() generated.MutationResolver { return &mutationResolver{r} }
 
+// Project returns generated.ProjectResolver implementation.
+func (r *Resolver) Project() generated.ProjectResolver { return &projectResolver{r} }
+
+// ProjectUser returns generated.ProjectUserResolver implementation.
+func (r *Resolver) ProjectUser() generated.ProjectUserResolver { return &projectUserResolver{r} }
+
+// Promotion returns generated.PromotionResolver implementation.
+func (r *Resolver) Promotion() generated.PromotionResolver { return &promotionResolver{r} }
+
+// ProtectionChange returns generated.ProtectionChangeResolver implementation.
+func (r *Resolver) ProtectionChange() generated.ProtectionChangeResolver {
+	return &protectionChangeResolver{r}
+}
+
 // Query returns generated.QueryResolver implementation.
 func (r *Resolver) Query() generated.QueryResolver { return &queryResolver{r} }
 
+// ToggleState returns generated.ToggleStateResolver implementation.
+func (r *Resolver) ToggleState() generated.ToggleStateResolver { return &toggleStateResolver{r} }
+
+// User returns generated.UserResolver implementation.
+func (r *Resolver) User() generated.UserResolver { return &userResolver{r} }
+
+// Webhook returns generated.WebhookResolver implementation.
+func (r *Resolver) Webhook() generated.WebhookResolver { return &webhookResolver{r} }
+
+// WebhookDelivery returns generated.WebhookDeliveryResolver implementation.
+func (r *Resolver) WebhookDelivery() generated.WebhookDeliveryResolver {
+	return &webhookDeliveryResolver{r}
+}
+
+type changeSetResolver struct{ *Resolver }
+type experimentResolver struct{ *Resolver }
+type experimentLayerResolver struct{ *Resolver }
+type featureFlagResolver struct{ *Resolver }
+type flagChangeResolver struct{ *Resolver }
+type flagEnvironmentStateResolver struct{ *Resolver }
+type flagStateChangeResolver struct{ *Resolver }
+type holdoutResolver struct{ *Resolver }
 type mutationResolver struct{ *Resolver }
+type projectResolver struct{ *Resolver }
+type projectUserResolver struct{ *Resolver }
+type promotionResolver struct{ *Resolver }
+type protectionChangeResolver struct{ *Resolver }
 type queryResolver struct{ *Resolver }
+type toggleStateResolver struct{ *Resolver }
+type userResolver struct{ *Resolver }
+type webhookResolver struct{ *Resolver }
+type webhookDeliveryResolver struct{ *Resolver }
@@ -0,0 +1,53 @@
+package resolver
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/99designs/gqlgen/graphql"
+	"github.com/shubham-tomar/feature-toggler/auth"
+	"github.com/shubham-tomar/feature-toggler/graphQl/model"
+)
+
+// Mutations that tokens with the TOGGLE scope may call, every other mutation needs ADMIN. These
+// change flag states, the deployment workflows of pipelines; project roles still apply to them.
+var toggleMutations = map[string]bool{
+	"toggleFeatureFlag":       true,
+	"addIndividualTargets":    true,
+	"removeIndividualTargets": true,
+	"promoteFeatureFlag":      true,
+	"promoteProject":          true,
+	"approvePromotion":        true,
+	"rejectPromotion":         true,
+	"createChangeSet":         true,
+	"deleteChangeSet":         true,
+	"applyChangeSet":          true,
+	"approveChangeSet":        true,
+	"rejectChangeSet":         true,
+	"cancelChangeSet":         true,
+	"revertChangeSet":         true,
+}
+
+// ScopeMiddleware rejects root fields the access token of the request is not scoped for
+func ScopeMiddleware(ctx context.Context, next graphql.Resolver) (any, error) {
+	fc := graphql.GetFieldContext(ctx)
+
+	var required model.TokenScope
+	switch fc.Object {
+	case "Query":
+		required = model.TokenScopeRead
+	case "Mutation":
+		required = model.TokenScopeAdmin
+		if toggleMutations[fc.Field.Name] {
+			required = model.TokenScopeToggle
+		}
+	default:
+		return next(ctx)
+	}
+
+	if !auth.HasScope(ctx, required) {
+		return nil, fmt.Errorf("access token is missing the %s scope required for %s", required, fc.Field.Name)
+	}
+
+	return next(ctx)
+}
@@ -0,0 +1,51 @@
+package resolver
+
+import (
+	"context"
+	"testing"
+
+	"github.com/99designs/gqlgen/graphql"
+	"github.com/shubham-tomar/feature-toggler/auth"
+	"github.com/shubham-tomar/feature-toggler/graphQl/generated"
+	"github.com/shubham-tomar/feature-toggler/graphQl/model"
+	"github.com/vektah/gqlparser/v2/ast"
+)
+
+func TestScopeMiddleware(t *testing.T) {
+	tests := []struct {
+		object, field string
+		scope         model.TokenScope
+		allowed       bool
+	}{
+		{"Query", "featureFlags", model.TokenScopeRead, true},
+		{"Mutation", "toggleFeatureFlag", model.TokenScopeRead, false},
+		{"Mutation", "toggleFeatureFlag", model.TokenScopeToggle, true},
+		{"Mutation", "addIndividualTargets", model.TokenScopeToggle, true},
+		{"Mutation", "promoteProject", model.TokenScopeToggle, true},
+		{"Mutation", "applyChangeSet", model.TokenScopeToggle, true},
+		{"Mutation", "createWebhook", model.TokenScopeToggle, false},
+		{"Mutation", "createWebhook", model.TokenScopeAdmin, true},
+	}
+
+	for _, tt := range tests {
+		ctx := auth.WithScopes(context.Background(), []model.TokenScope{tt.scope})
+		ctx = graphql.WithFieldContext(ctx, &graphql.FieldContext{
+			Object: tt.object,
+			Field:  graphql.CollectedField{Field: &ast.Field{Name: tt.field}},
+		})
+
+		_, err := ScopeMiddleware(ctx, func(context.Context) (any, error) { return nil, nil })
+		if allowed := err == nil; allowed != tt.allowed {
+			t.Errorf("%s with %s: allowed %v, want %v (%v)", tt.field, tt.scope, allowed, tt.allowed, err)
+		}
+	}
+}
+
+func TestToggleMutationsExist(t *testing.T) {
+	mutation := generated.NewExecutableSchema(generated.Config{Resolvers: &Resolver{}}).Schema().Mutation
+	for name := range toggleMutations {
+		if mutation.Fields.ForName(name) == nil {
+			t.Errorf("%s is not a mutation", name)
+		}
+	}
+}
@@ -0,0 +1,94 @@
+package resolver
+
+import (
+	"io"
+	"maps"
+	"strings"
+
+	"github.com/shubham-tomar/feature-toggler/evaluation"
+	"github.com/shubham-tomar/feature-toggler/graphQl/model"
+	"github.com/shubham-tomar/feature-toggler/simulation"
+)
+
+// simulationSample parses the pasted or uploaded contexts of a simulation
+func simulationSample(input model.SimulateFeatureFlagInput) ([]evaluation.Context, error) {
+	var sample io.Reader
+	switch {
+	case input.Contexts != nil && input.File != nil:
+		return nil, invalidInputError("paste contexts or upload a file, not both")
+	case input.Contexts != nil:
+		sample = strings.NewReader(*input.Contexts)
+	case input.File != nil:
+		sample = input.File.File
+	default:
+		return nil, invalidInputError("a simulation needs sample contexts")
+	}
+
+	contexts, err := simulation.ParseContexts(input.Format, sample)
+	if err != nil {
+		return nil, invalidInputError("%v", err)
+	}
+	if len(contexts) == 0 {
+		return nil, invalidInputError("the sample holds no contexts")
+	}
+	return contexts, nil
+}
+
+// proposedConfig applies a proposal to copies of the current configuration, which stays untouched
+func proposedConfig(current simulation.Config, env model.Environment, input *model.ProposedFlagConfigInput) (simulation.Config, error) {
+	flag := *current.Flag
+	flag.States = make([]*model.ToggleState, 0, len(current.Flag.States)+1)
+	configured := false
+	for _, state := range current.Flag.States {
+		if state.Environment == env && input.Enabled != nil {
+			proposed := *state
+			proposed.Enabled = *input.Enabled
+			state, configured = &proposed, true
+		}
+		flag.States = append(flag.States, state)
+	}
+	if input.Enabled != nil && !configured {
+		flag.States = append(flag.States, &model.ToggleState{Environment: env, Enabled: *input.Enabled})
+	}
+
+	experiments := current.Experiments
+	if input.StopExperiment != nil && *input.StopExperiment {
+		if current.Experiments.ByFlag[flag.ID] == nil {
+			return simulation.Config{}, invalidInputError("no experiment is running on the flag in this environment")
+		}
+		experiments = &evaluation.Experiments{
+			ByFlag:   maps.Clone(current.Experiments.ByFlag),
+			Holdouts: current.Experiments.Holdouts,
+		}
+		delete(experiments.ByFlag, flag.ID)
+	}
+
+	targets := current.Targets
+	if input.AddTargets != nil || input.RemoveTargets != nil {
+		addTargets, removeTargets, err := targetChanges(&flag, input.AddTargets, input.RemoveTargets)
+		if err != nil {
+			return simulation.Config{}, err
+		}
+
+		variants := maps.Clone(current.Targets[flag.ID])
+		if variants == nil {
+			variants = map[string]string{}
+		}
+		for _, group := range addTargets {
+			for _, key := range group.ContextKeys {
+				variants[key] = group.Variant
+			}
+		}
+		for _, key := range removeTargets {
+			delete(variants, key)
+		}
+
+		targets = maps.Clone(current.Targets)
+		if targets == nil {
+			targets = evaluation.Targets{}
+		}
+		targets[flag.ID] = variants
+	}
+
+	return simulation.Config{Flag: &flag, Targets: targets, Experiments: experiments}, nil
+}
@@ -0,0 +1,124 @@
+package resolver
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/shubham-tomar/feature-toggler/db"
+	"github.com/shubham-tomar/feature-toggler/evaluation"
+	"github.com/shubham-tomar/feature-toggler/graphQl/model"
+)
+
+const (
+	maxTargetKeysPerRequest = 10000
+	maxTargetKeyLength      = 256
+)
+
+// targetKeys trims and deduplicates the context keys of a target change
+func targetKeys(keys []string) ([]string, error) {
+	if len(keys) == 0 {
+		return nil, invalidInputError("at least one context key is required")
+	}
+	if len(keys) > maxTargetKeysPerRequest {
+		return nil, invalidInputError("at most %d context keys can change per request", maxTargetKeysPerRequest)
+	}
+
+	seen := make(map[string]bool, len(keys))
+	unique := make([]string, 0, len(keys))
+	for _, key := range keys {
+		key = strings.TrimSpace(key)
+		if key == "" || len(key) > maxTargetKeyLength {
+			return nil, invalidInputError("context keys must be between 1 and %d characters", maxTargetKeyLength)
+		}
+		if !seen[key] {
+			seen[key] = true
+			unique = append(unique, key)
+		}
+	}
+	return unique, nil
+}
+
+// targetState returns the state of a flag whose targets are about to change
+func (r *Resolver) targetState(ctx context.Context, flagID string, env model.Environment) (*model.ToggleState, error) {
+	flag, err := r.Storage.GetFeatureFlagByID(ctx, flagID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get feature flag: %w", err)
+	}
+
+	if flag.Status == model.FlagStatusArchived {
+		return nil, invalidInputError("feature flag %s is archived, restore it before changing its targets", flag.Key)
+	}
+
+	if err := r.unprotected(ctx, flag.Project.ID, env); err != nil {
+		return nil, err
+	}
+
+	for _, state := range flag.States {
+		if state.Environment == env {
+			return state, nil
+		}
+	}
+	return nil, invalidInputError("feature flag %s is not configured in %s", flag.Key, env)
+}
+
+// flagTargets loads every individual target of a flag in an environment
+func (r *Resolver) flagTargets(ctx context.Context, flagID string, env model.Environment) (evaluation.Targets, error) {
+	targets, _, err := r.Storage.GetIndividualTargets(ctx, flagID, env, nil, db.Page{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get individual targets: %w", err)
+	}
+
+	variants := make(map[string]string, len(targets))
+	for _, target := range targets {
+		variants[target.ContextKey] = target.Variant
+	}
+	return evaluation.Targets{flagID: variants}, nil
+}
+
+// targetChanges validates the individual targets a change adds to and removes from a flag:
+// existing variants, valid keys, and no key added twice or both added and removed
+func targetChanges(flag *model.FeatureFlag, add []*model.TargetGroupInput, remove []string) ([]*model.TargetGroup, []string, error) {
+	groups := []*model.TargetGroup{}
+	added := map[string]bool{}
+	for _, group := range add {
+		if err := targetVariant(group.Variant); err != nil {
+			return nil, nil, err
+		}
+		keys, err := targetKeys(group.ContextKeys)
+		if err != nil {
+			return nil, nil, err
+		}
+		for _, key := range keys {
+			if added[key] {
+				return nil, nil, invalidInputError("context key %q is added to feature flag %s more than once", key, flag.Key)
+			}
+			added[key] = true
+		}
+		groups = append(groups, &model.TargetGroup{Variant: group.Variant, ContextKeys: keys})
+	}
+
+	removed := []string{}
+	if len(remove) > 0 {
+		keys, err := targetKeys(remove)
+		if err != nil {
+			return nil, nil, err
+		}
+		for _, key := range keys {
+			if added[key] {
+				return nil, nil, invalidInputError("context key %q is both added to and removed from feature flag %s", key, flag.Key)
+			}
+		}
+		removed = keys
+	}
+
+	return groups, removed, nil
+}
+
+func targetVariant(variant string) error {
+	if !slices.Contains(flagVariants, variant) {
+		return invalidInputError("variant %q does not exist, flags serve %s", variant, strings.Join(flagVariants, " and "))
+	}
+	return nil
+}
@@ -0,0 +1,118 @@
+package resolver
+
+import (
+	"testing"
+
+	"github.com/shubham-tomar/feature-toggler/graphQl/model"
+)
+
+func TestServiceAccountTokensNeedCreatorOrAdmin(t *testing.T) {
+	r := newTestResolver(t)
+	mutation, query := r.Mutation(), r.Query()
+
+	admin, adminCtx := newTestUser(t, r, "admin")
+	developer, developerCtx := newTestUser(t, r, "developer")
+	_, outsiderCtx := newTestUser(t, r, "outsider")
+
+	project, err := mutation.CreateProject(adminCtx, "checkout")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := mutation.AddProjectMember(adminCtx, model.AddProjectMemberInput{
+		ProjectID: project.ID, UserID: developer.ID, Role: model.RoleDeveloper,
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	account, err := mutation.CreateServiceAccount(developerCtx, "ci")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := mutation.AddProjectMember(adminCtx, model.AddProjectMemberInput{
+		ProjectID: project.ID, UserID: account.ID, Role: model.RoleDeveloper,
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	token, err := mutation.CreateAccessToken(developerCtx, model.CreateAccessTokenInput{
+		Name: "deploy", Scopes: []model.TokenScope{model.TokenScopeToggle}, UserID: &account.ID,
+	})
+	if err != nil {
+		t.Fatalf("the creator of the account could not issue it a token: %v", err)
+	}
+
+	tests := []struct {
+		name    string
+		call    func() error
+		allowed bool
+	}{
+		{"outsider issues a token", func() error {
+			_, err := mutation.CreateAccessToken(outsiderCtx, model.CreateAccessTokenInput{
+				Name: "stolen", Scopes: []model.TokenScope{model.TokenScopeRead}, UserID: &account.ID,
+			})
+			return err
+		}, false},
+		{"outsider lists the tokens", func() error {
+			_, err := query.AccessTokens(outsiderCtx, &account.ID)
+			return err
+		}, false},
+		{"outsider revokes a token", func() error {
+			_, err := mutation.RevokeAccessToken(outsiderCtx, token.AccessToken.ID)
+			return err
+		}, false},
+		{"outsider manages the tokens of a person", func() error {
+			_, err := query.AccessTokens(outsiderCtx, &admin.ID)
+			return err
+		}, false},
+		{"creator issues an ADMIN token", func() error {
+			_, err := mutation.CreateAccessToken(developerCtx, model.CreateAccessTokenInput{
+				Name: "admin", Scopes: []model.TokenScope{model.TokenScopeAdmin}, UserID: &account.ID,
+			})
+			return err
+		}, false},
+		{"developer issues themselves an ADMIN token", func() error {
+			_, err := mutation.CreateAccessToken(developerCtx, model.CreateAccessTokenInput{
+				Name: "admin", Scopes: []model.TokenScope{model.TokenScopeAdmin},
+			})
+			return err
+		}, false},
+		{"project admin lists the tokens", func() error {
+			_, err := query.AccessTokens(adminCtx, &account.ID)
+			return err
+		}, true},
+		{"project admin issues an ADMIN token", func() error {
+			_, err := mutation.CreateAccessToken(adminCtx, model.CreateAccessTokenInput{
+				Name: "admin", Scopes: []model.TokenScope{model.TokenScopeAdmin}, UserID: &account.ID,
+			})
+			return err
+		}, true},
+		{"admin issues themselves an ADMIN token", func() error {
+			_, err := mutation.CreateAccessToken(adminCtx, model.CreateAccessTokenInput{
+				Name: "admin", Scopes: []model.TokenScope{model.TokenScopeAdmin},
+			})
+			return err
+		}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.call()
+			if tt.allowed && err != nil {
+				t.Errorf("got error %v, want none", err)
+			}
+			if !tt.allowed && errorCode(err) != codeForbidden {
+				t.Errorf("got error %v, want a %s error", err, codeForbidden)
+			}
+		})
+	}
+
+	tokens, err := r.Storage.GetUserAccessTokens(adminCtx, account.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, tok := range tokens {
+		if tok.RevokedAt != nil {
+			t.Errorf("token %s was revoked", tok.Name)
+		}
+	}
+}
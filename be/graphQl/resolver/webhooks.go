@@ -0,0 +1,40 @@
+package resolver
+
+import (
+	"net/url"
+	"slices"
+	"strings"
+
+	"github.com/shubham-tomar/feature-toggler/graphQl/model"
+)
+
+const minWebhookSecretLength = 16
+
+func webhookURL(raw string) (string, error) {
+	u, err := url.Parse(strings.TrimSpace(raw))
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return "", invalidInputError("webhook url %q must be an absolute http or https URL", raw)
+	}
+	return u.String(), nil
+}
+
+// webhookEventTypes drops duplicates, an empty list subscribes to nothing and is rejected
+func webhookEventTypes(types []model.WebhookEventType) ([]model.WebhookEventType, error) {
+	var unique []model.WebhookEventType
+	for _, t := range types {
+		if !slices.Contains(unique, t) {
+			unique = append(unique, t)
+		}
+	}
+	if len(unique) == 0 {
+		return nil, invalidInputError("a webhook needs at least one event type")
+	}
+	return unique, nil
+}
+
+func webhookSecret(secret string) error {
+	if len(secret) < minWebhookSecretLength {
+		return invalidInputError("webhook secrets must be at least %d characters", minWebhookSecretLength)
+	}
+	return nil
+}
@@ -0,0 +1,84 @@
+package resolver
+
+import (
+	"testing"
+
+	"github.com/shubham-tomar/feature-toggler/db"
+	"github.com/shubham-tomar/feature-toggler/db/sqlite/sqlitetest"
+	"github.com/shubham-tomar/feature-toggler/graphQl/model"
+)
+
+func TestWebhooksAreManagedByAdmins(t *testing.T) {
+	r := newTestResolver(t)
+	mutation := r.Mutation()
+
+	_, adminCtx := newTestUser(t, r, "admin")
+	developer, developerCtx := newTestUser(t, r, "developer")
+
+	project, err := mutation.CreateProject(adminCtx, "checkout")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := mutation.AddProjectMember(adminCtx, model.AddProjectMemberInput{
+		ProjectID: project.ID, UserID: developer.ID, Role: model.RoleDeveloper,
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	created, err := mutation.CreateWebhook(adminCtx, model.CreateWebhookInput{
+		ProjectID: project.ID, URL: "https://hooks.example.com/flags", EventTypes: model.AllWebhookEventType,
+	})
+	if err != nil {
+		t.Fatalf("an admin could not add a webhook: %v", err)
+	}
+	sqlitetest.Flag(t, r.Storage, project, "new-checkout")
+	if _, err := r.Storage.DispatchWebhookEvents(adminCtx, 10); err != nil {
+		t.Fatal(err)
+	}
+	deliveries, _, err := r.Storage.GetWebhookDeliveries(adminCtx, created.Webhook.ID, nil, db.Page{Limit: 1})
+	if err != nil || len(deliveries) != 1 {
+		t.Fatalf("got deliveries %v, %v, want one", deliveries, err)
+	}
+
+	// The developer could otherwise send the project's events, and their signing secret, anywhere
+	evil := "https://evil.example.com"
+	tests := []struct {
+		name string
+		call func() error
+	}{
+		{"create", func() error {
+			_, err := mutation.CreateWebhook(developerCtx, model.CreateWebhookInput{
+				ProjectID: project.ID, URL: evil, EventTypes: model.AllWebhookEventType,
+			})
+			return err
+		}},
+		{"update", func() error {
+			_, err := mutation.UpdateWebhook(developerCtx, created.Webhook.ID, model.UpdateWebhookInput{URL: &evil})
+			return err
+		}},
+		{"delete", func() error {
+			_, err := mutation.DeleteWebhook(developerCtx, created.Webhook.ID)
+			return err
+		}},
+		{"redeliver", func() error {
+			_, err := mutation.RedeliverWebhookDelivery(developerCtx, deliveries[0].ID)
+			return err
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.call(); errorCode(err) != codeForbidden {
+				t.Errorf("got error %v, want a %s error", err, codeForbidden)
+			}
+		})
+	}
+
+	hooks, err := r.Storage.GetProjectWebhooks(adminCtx, project.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(hooks) != 1 || hooks[0].URL != created.Webhook.URL {
+		t.Errorf("got webhooks %v, want only the admin's", hooks)
+	}
+}
@@ -0,0 +1,199 @@
+package health
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/shubham-tomar/feature-toggler/db"
+	"github.com/shubham-tomar/feature-toggler/graphQl/model"
+)
+
+// Default thresholds for projects that do not set their own
+const (
+	DefaultNewFlagDays     = 7
+	DefaultUnusedAfterDays = 30
+)
+
+const day = 24 * time.Hour
+
+// Thresholds are the ages, in days, that move a flag from one status to the next
+type Thresholds struct {
+	NewFlagDays     int
+	StaleAfterDays  int
+	UnusedAfterDays int
+}
+
+// ThresholdsFor returns the thresholds of a project, filling in the defaults
+func ThresholdsFor(project *model.Project) Thresholds {
+	t := Thresholds{
+		NewFlagDays:     DefaultNewFlagDays,
+		StaleAfterDays:  db.DefaultStaleAfterDays,
+		UnusedAfterDays: DefaultUnusedAfterDays,
+	}
+	if project.NewFlagDays != nil {
+		t.NewFlagDays = *project.NewFlagDays
+	}
+	if project.StaleAfterDays != nil {
+		t.StaleAfterDays = *project.StaleAfterDays
+	}
+	if project.UnusedAfterDays != nil {
+		t.UnusedAfterDays = *project.UnusedAfterDays
+	}
+	return t
+}
+
+// Classify judges a flag whose states are loaded. The first matching status wins:
+// NEW, UNUSED, STALE, ROLLED_OUT and finally ACTIVE.
+func Classify(flag *model.FeatureFlag, lastEvaluated *time.Time, t Thresholds, now time.Time) *model.FlagHealth {
+	h := &model.FlagHealth{
+		LastToggledAt:   lastToggled(flag),
+		LastEvaluatedAt: lastEvaluated,
+	}
+
+	enabled, total := enabledEnvironments(flag)
+	rolledOut := total > 0 && len(enabled) == total
+
+	switch {
+	case now.Sub(flag.CreatedAt) < days(t.NewFlagDays):
+		h.Status = model.FlagHealthStatusNew
+		h.Reason = fmt.Sprintf("created %s ago", ago(now, flag.CreatedAt))
+
+	// A flag that was never evaluated gets as long as an evaluated one before it counts as unused,
+	// so that traffic which started after it was created has a chance to show up
+	case lastEvaluated == nil && now.Sub(flag.CreatedAt) >= days(t.UnusedAfterDays):
+		h.Status = model.FlagHealthStatusUnused
+		h.Reason = fmt.Sprintf("never evaluated in the %s since it was created", ago(now, flag.CreatedAt))
+
+	case lastEvaluated != nil && now.Sub(*lastEvaluated) >= days(t.UnusedAfterDays):
+		h.Status = model.FlagHealthStatusUnused
+		h.Reason = fmt.Sprintf("last evaluated %s ago", ago(now, *lastEvaluated))
+
+	case now.Sub(h.LastToggledAt) >= days(t.StaleAfterDays):
+		h.Status = model.FlagHealthStatusStale
+		h.Reason = fmt.Sprintf("unchanged for %s, %s", ago(now, h.LastToggledAt), describe(enabled, rolledOut))
+
+	case rolledOut:
+		h.Status = model.FlagHealthStatusRolledOut
+		h.Reason = "enabled in every environment"
+
+	default:
+		h.Status = model.FlagHealthStatusActive
+		h.Reason = fmt.Sprintf("toggled %s ago, %s", ago(now, h.LastToggledAt), describe(enabled, rolledOut))
+	}
+
+	return h
+}
+
+// Assessment is the health of one flag of a project
+type Assessment struct {
+	Flag   *model.FeatureFlag
+	Health *model.FlagHealth
+}
+
+// Assess classifies the active flags of a project, least recently toggled first
+func Assess(ctx context.Context, storage db.Storage, project *model.Project, now time.Time) ([]*Assessment, error) {
+	flags, err := storage.GetProjectFeatureFlags(ctx, project.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get feature flags: %w", err)
+	}
+
+	var active []*model.FeatureFlag
+	var ids []string
+	for _, flag := range flags {
+		if flag.Status == model.FlagStatusArchived {
+			continue
+		}
+		active = append(active, flag)
+		ids = append(ids, flag.ID)
+	}
+
+	states, err := storage.GetStatesByFeatureFlagIDs(ctx, ids)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get toggle states: %w", err)
+	}
+	byFlag := map[string][]*model.ToggleState{}
+	for _, state := range states {
+		byFlag[state.FeatureFlag.ID] = append(byFlag[state.FeatureFlag.ID], state)
+	}
+
+	lastEvaluations, err := storage.GetLastEvaluations(ctx, ids)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get last evaluations: %w", err)
+	}
+
+	t := ThresholdsFor(project)
+	assessments := make([]*Assessment, 0, len(active))
+	for _, flag := range active {
+		flag.States = byFlag[flag.ID]
+		if flag.States == nil {
+			flag.States = []*model.ToggleState{}
+		}
+
+		var lastEvaluated *time.Time
+		if at, ok := lastEvaluations[flag.ID]; ok {
+			lastEvaluated = &at
+		}
+
+		assessments = append(assessments, &Assessment{Flag: flag, Health: Classify(flag, lastEvaluated, t, now)})
+	}
+
+	sort.SliceStable(assessments, func(i, j int) bool {
+		return assessments[i].Health.LastToggledAt.Before(assessments[j].Health.LastToggledAt)
+	})
+
+	return assessments, nil
+}
+
+// Expired reports whether a flag is past its planned removal date
+func Expired(flag *model.FeatureFlag, now time.Time) bool {
+	return flag.RemovalDate != nil && !now.Before(*flag.RemovalDate)
+}
+
+func lastToggled(flag *model.FeatureFlag) time.Time {
+	last := flag.CreatedAt
+	for _, state := range flag.States {
+		if state.UpdatedAt.After(last) {
+			last = state.UpdatedAt
+		}
+	}
+	return last
+}
+
+// enabledEnvironments returns the environments a flag is on in, and how many it has a state in
+func enabledEnvironments(flag *model.FeatureFlag) ([]string, int) {
+	var enabled []string
+	for _, state := range flag.States {
+		if state.Enabled {
+			enabled = append(enabled, string(state.Environment))
+		}
+	}
+	sort.Strings(enabled)
+	return enabled, len(flag.States)
+}
+
+func describe(enabled []string, rolledOut bool) string {
+	switch {
+	case rolledOut:
+		return "enabled in every environment"
+	case len(enabled) == 0:
+		return "disabled everywhere"
+	default:
+		return "enabled in " + strings.Join(enabled, ", ")
+	}
+}
+
+func days(n int) time.Duration {
+	return time.Duration(n) * day
+}
+
+// ago rounds a duration to whole days, or hours under a day
+func ago(now, t time.Time) string {
+	d := now.Sub(t)
+	if d < day {
+		return fmt.Sprintf("%d hours", int(d.Hours()))
+	}
+	return fmt.Sprintf("%d days", int(d/day))
+}
@@ -0,0 +1,102 @@
+package health
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/shubham-tomar/feature-toggler/db"
+	"github.com/shubham-tomar/feature-toggler/graphQl/model"
+)
+
+// Reporter periodically logs the flags of every project that are due for cleanup
+type Reporter struct {
+	storage  db.Storage
+	interval time.Duration
+	stop     chan struct{}
+	done     chan struct{}
+}
+
+func NewReporter(storage db.Storage, interval time.Duration) *Reporter {
+	return &Reporter{
+		storage:  storage,
+		interval: interval,
+		stop:     make(chan struct{}),
+		done:     make(chan struct{}),
+	}
+}
+
+// Start reports once every interval until Stop is called
+func (r *Reporter) Start() {
+	go func() {
+		defer close(r.done)
+
+		ticker := time.NewTicker(r.interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ticker.C:
+				if err := r.Report(context.Background()); err != nil {
+					log.Printf("flag health: failed to build report: %v", err)
+				}
+			case <-r.stop:
+				return
+			}
+		}
+	}()
+}
+
+func (r *Reporter) Stop() {
+	close(r.stop)
+	<-r.done
+}
+
+// Report logs a summary line per project with cleanup candidates, followed by one line per candidate
+func (r *Reporter) Report(ctx context.Context) error {
+	projects, _, err := r.storage.GetProjects(ctx, db.Page{})
+	if err != nil {
+		return fmt.Errorf("failed to get projects: %w", err)
+	}
+
+	now := time.Now()
+	for _, project := range projects {
+		assessments, err := Assess(ctx, r.storage, project, now)
+		if err != nil {
+			return fmt.Errorf("failed to assess project %s: %w", project.ID, err)
+		}
+
+		counts := map[model.FlagHealthStatus]int{}
+		expired := 0
+		var candidates []*Assessment
+		for _, a := range assessments {
+			counts[a.Health.Status]++
+			overdue := Expired(a.Flag, now)
+			if overdue {
+				expired++
+			}
+			if overdue || (a.Health.Status != model.FlagHealthStatusNew && a.Health.Status != model.FlagHealthStatusActive) {
+				candidates = append(candidates, a)
+			}
+		}
+
+		if len(candidates) == 0 {
+			continue
+		}
+
+		log.Printf("flag health: project %q has %d stale, %d unused, %d rolled out and %d expired flags out of %d",
+			project.Name, counts[model.FlagHealthStatusStale], counts[model.FlagHealthStatusUnused],
+			counts[model.FlagHealthStatusRolledOut], expired, len(assessments))
+		for _, a := range candidates {
+			line := fmt.Sprintf("%s is %s, %s", a.Flag.Key, strings.ReplaceAll(strings.ToLower(string(a.Health.Status)), "_", " "), a.Health.Reason)
+			if Expired(a.Flag, now) {
+				line += ", past its removal date " + a.Flag.RemovalDate.Format(time.DateOnly)
+			}
+			log.Printf("flag health:   %s", line)
+		}
+	}
+
+	return nil
+}
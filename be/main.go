@@ -2,16 +2,31 @@ package main
 
 import (
 	"context"
+	"crypto/rand"
+	"expvar"
 	"log"
+	"net/http"
+	"strings"
 	"time"
 	"github.com/99designs/gqlgen/graphql/handler"
 	"github.com/99designs/gqlgen/graphql/playground"
 	"github.com/gin-gonic/gin"
+	"github.com/shubham-tomar/feature-toggler/analytics"
+	"github.com/shubham-tomar/feature-toggler/auth"
+	"github.com/shubham-tomar/feature-toggler/changesets"
+	"github.com/shubham-tomar/feature-toggler/db"
+	"github.com/shubham-tomar/feature-toggler/db/cache"
 	"github.com/shubham-tomar/feature-toggler/db/sqlite"
+	"github.com/shubham-tomar/feature-toggler/evaluation"
 	userctx "github.com/shubham-tomar/feature-toggler/graphQl/context"
 	"github.com/shubham-tomar/feature-toggler/graphQl/generated"
+	"github.com/shubham-tomar/feature-toggler/graphQl/loaders"
 	"github.com/shubham-tomar/feature-toggler/graphQl/resolver"
+	"github.com/shubham-tomar/feature-toggler/health"
+	"github.com/shubham-tomar/feature-toggler/metrics"
+	"github.com/shubham-tomar/feature-toggler/ofrep"
 	"github.com/shubham-tomar/feature-toggler/utils"
+	"github.com/shubham-tomar/feature-toggler/webhooks"
 	"github.com/shubham-tomar/feature-toggler/graphQl/model"
 )
 
@@ -38,6 +53,30 @@ func main() {
 		log.Fatalf("Failed to migrate database: %v", err)
 	}
 	
+	// Cache flags, states, projects and users in memory, writes through the cache invalidate them
+	var storage db.Storage = sqliteStorage
+	if utils.GetEnv("CACHE_ENABLED", "true") == "true" {
+		cacheTTL, err := time.ParseDuration(utils.GetEnv("CACHE_TTL", "1m"))
+		if err != nil {
+			log.Fatalf("Invalid CACHE_TTL: %v", err)
+		}
+
+		cached := cache.New(sqliteStorage, cacheTTL)
+		expvar.Publish("storage_cache", expvar.Func(func() any { return cached.Stats() }))
+		storage = cached
+	}
+
+	// Prometheus metrics, storage calls are timed as the rest of the server sees them, cache hits included
+	metricsEnabled := utils.GetEnv("METRICS_ENABLED", "true") == "true"
+	if metricsEnabled {
+		if cached, ok := storage.(*cache.Storage); ok {
+			metrics.RegisterCacheStats(cached)
+		}
+		storage = metrics.NewStorage(storage)
+		metrics.RegisterFlagGauges(storage)
+		r.Use(metrics.Middleware())
+	}
+
 	// Insert mock user if it doesn't exist
 	ctx := context.Background()
 	
@@ -51,28 +90,152 @@ func main() {
 	}
 	
 	// Check if user exists
-	_, err = sqliteStorage.GetUserByID(ctx, mockUser.ID)
+	_, err = storage.GetUserByID(ctx, mockUser.ID)
 	if err != nil {
 		// User doesn't exist, create it
-		if err := sqliteStorage.CreateUser(ctx, mockUser); err != nil {
+		if err := storage.CreateUser(ctx, mockUser); err != nil {
 			log.Printf("Warning: Failed to create mock user: %v", err)
 		} else {
 			log.Printf("Mock user created with ID: %s, Name: %s", mockUser.ID, mockUser.Name)
 		}
 	}
 
+	// Evaluation counts are buffered in memory and added to storage in the background
+	flushInterval, err := time.ParseDuration(utils.GetEnv("ANALYTICS_FLUSH_INTERVAL", "10s"))
+	if err != nil {
+		log.Fatalf("Invalid ANALYTICS_FLUSH_INTERVAL: %v", err)
+	}
+	retention, err := time.ParseDuration(utils.GetEnv("ANALYTICS_RETENTION", "2160h"))
+	if err != nil {
+		log.Fatalf("Invalid ANALYTICS_RETENTION: %v", err)
+	}
+	recorder := analytics.NewRecorder(storage, flushInterval, retention)
+	recorder.Start()
+	defer recorder.Stop()
+
+	// Log the flags due for cleanup, an interval of 0 turns the report off
+	reportInterval, err := time.ParseDuration(utils.GetEnv("HEALTH_REPORT_INTERVAL", "24h"))
+	if err != nil {
+		log.Fatalf("Invalid HEALTH_REPORT_INTERVAL: %v", err)
+	}
+	if reportInterval > 0 {
+		reporter := health.NewReporter(storage, reportInterval)
+		reporter.Start()
+		defer reporter.Stop()
+	}
+
+	// Flag changes are queued in an outbox by storage, the worker delivers them to the project webhooks
+	webhookInterval, err := time.ParseDuration(utils.GetEnv("WEBHOOK_POLL_INTERVAL", "5s"))
+	if err != nil {
+		log.Fatalf("Invalid WEBHOOK_POLL_INTERVAL: %v", err)
+	}
+	webhookWorker := webhooks.NewWorker(storage, webhookInterval)
+	webhookWorker.Start()
+	defer webhookWorker.Stop()
+
+	// Scheduled change sets are applied by the first poll after their time
+	changeSetInterval, err := time.ParseDuration(utils.GetEnv("CHANGE_SET_POLL_INTERVAL", "30s"))
+	if err != nil {
+		log.Fatalf("Invalid CHANGE_SET_POLL_INTERVAL: %v", err)
+	}
+	changeSetScheduler := changesets.NewScheduler(storage, changeSetInterval)
+	changeSetScheduler.Start()
+	defer changeSetScheduler.Stop()
+
+	// Override tokens are signed rather than stored, without a configured key they only last until a restart
+	overrideKey := []byte(utils.GetEnv("OVERRIDE_SIGNING_KEY", ""))
+	if len(overrideKey) == 0 {
+		overrideKey = make([]byte, 32)
+		if _, err := rand.Read(overrideKey); err != nil {
+			log.Fatalf("Failed to generate an override signing key: %v", err)
+		}
+		log.Printf("OVERRIDE_SIGNING_KEY is not set, override tokens stop working when the server restarts")
+	} else if len(overrideKey) < 32 {
+		log.Fatalf("Invalid OVERRIDE_SIGNING_KEY: it must be at least 32 characters")
+	}
+	overrideSigner := auth.NewOverrideSigner(overrideKey)
+
 	srv := handler.NewDefaultServer(generated.NewExecutableSchema(generated.Config{
 		Resolvers: &resolver.Resolver{
-			Storage: sqliteStorage,
+			Storage:   storage,
+			Overrides: overrideSigner,
 		},
 	}))
 
-	r.POST("/query", func(c *gin.Context) {
-		ctx := userctx.WithUser(c.Request.Context())
-		c.Request = c.Request.WithContext(ctx)
+	srv.AroundFields(resolver.ScopeMiddleware)
+	if metricsEnabled {
+		srv.Use(metrics.GraphQL{})
+	}
+
+	// Single sign-on, once configured every GraphQL request needs a session or an access token
+	requireLogin := false
+	if issuerURL := utils.GetEnv("OIDC_ISSUER_URL", ""); issuerURL != "" {
+		groupRoles, err := auth.ParseGroupRoles(utils.GetEnv("OIDC_GROUP_ROLES", ""))
+		if err != nil {
+			log.Fatalf("Invalid OIDC_GROUP_ROLES: %v", err)
+		}
+
+		sessionTTL, err := time.ParseDuration(utils.GetEnv("SESSION_TTL", "24h"))
+		if err != nil {
+			log.Fatalf("Invalid SESSION_TTL: %v", err)
+		}
+
+		sso, err := auth.NewOIDC(ctx, storage, auth.OIDCConfig{
+			IssuerURL:         issuerURL,
+			ClientID:          utils.GetEnv("OIDC_CLIENT_ID", ""),
+			ClientSecret:      utils.GetEnv("OIDC_CLIENT_SECRET", ""),
+			RedirectURL:       utils.GetEnv("OIDC_REDIRECT_URL", "http://localhost:"+port+"/auth/oidc/callback"),
+			Scopes:            strings.Fields(utils.GetEnv("OIDC_SCOPES", "")),
+			GroupsClaim:       utils.GetEnv("OIDC_GROUPS_CLAIM", "groups"),
+			GroupRoles:        groupRoles,
+			SessionTTL:        sessionTTL,
+			PostLoginRedirect: utils.GetEnv("OIDC_POST_LOGIN_REDIRECT", "/"),
+		})
+		if err != nil {
+			log.Fatalf("Failed to set up OIDC login: %v", err)
+		}
+
+		sso.Register(r.Group("/auth/oidc"))
+		requireLogin = true
+	}
+
+	r.POST("/query", auth.Authenticate(storage, requireLogin), loaders.Middleware(storage), func(c *gin.Context) {
 		srv.ServeHTTP(c.Writer, c.Request)
 	})
 
+	// OpenFeature Remote Evaluation Protocol
+	evaluator := &evaluation.Evaluator{Storage: storage}
+	ofrepHandler := &ofrep.Handler{
+		Evaluator: evaluator,
+		Analytics: recorder,
+		Overrides: overrideSigner,
+	}
+	ofrepHandler.Register(r.Group("/ofrep/v1", auth.RequireSdkKey(storage)))
+
+	// Evaluation events of SDKs that evaluate flags themselves
+	analyticsHandler := &analytics.Handler{
+		Evaluator: evaluator,
+		Recorder:  recorder,
+	}
+	analyticsHandler.Register(r.Group("/analytics/v1", auth.RequireSdkKey(storage)))
+
+	// Runtime counters and metrics name projects and internals, so they are served on a separate
+	// listener that only binds to loopback by default; an empty ADMIN_ADDR turns it off
+	if adminAddr := utils.GetEnv("ADMIN_ADDR", "127.0.0.1:9090"); adminAddr != "" {
+		admin := http.NewServeMux()
+		admin.Handle("/debug/vars", expvar.Handler())
+		if metricsEnabled {
+			admin.Handle("/metrics", metrics.Handler())
+		}
+
+		go func() {
+			log.Printf("Admin endpoints on %s", adminAddr)
+			if err := http.ListenAndServe(adminAddr, admin); err != nil {
+				log.Fatalf("Failed to serve admin endpoints: %v", err)
+			}
+		}()
+	}
+
 	r.GET("/", func(c *gin.Context) {
 		playground.Handler("GraphQL playground", "/query").ServeHTTP(c.Writer, c.Request)
 	})
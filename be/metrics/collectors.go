@@ -0,0 +1,82 @@
+package metrics
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/shubham-tomar/feature-toggler/db"
+	"github.com/shubham-tomar/feature-toggler/db/cache"
+)
+
+// How long a scrape may spend counting flags
+const countTimeout = 5 * time.Second
+
+var (
+	flagsDesc = prometheus.NewDesc("feature_flags",
+		"Feature flags across all projects by status.", []string{"status"}, nil)
+	enabledFlagsDesc = prometheus.NewDesc("feature_flags_enabled",
+		"Active feature flags enabled in each environment.", []string{"environment"}, nil)
+)
+
+// flagCollector counts the flags in storage whenever metrics are scraped
+type flagCollector struct {
+	storage db.Storage
+}
+
+// RegisterFlagGauges exposes the number of flags, and of enabled flags per environment
+func RegisterFlagGauges(storage db.Storage) {
+	prometheus.MustRegister(&flagCollector{storage: storage})
+}
+
+func (c *flagCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- flagsDesc
+	ch <- enabledFlagsDesc
+}
+
+func (c *flagCollector) Collect(ch chan<- prometheus.Metric) {
+	ctx, cancel := context.WithTimeout(context.Background(), countTimeout)
+	defer cancel()
+
+	counts, err := c.storage.CountFeatureFlags(ctx)
+	if err != nil {
+		log.Printf("Failed to count feature flags for metrics: %v", err)
+		ch <- prometheus.NewInvalidMetric(flagsDesc, err)
+		return
+	}
+
+	ch <- prometheus.MustNewConstMetric(flagsDesc, prometheus.GaugeValue, float64(counts.Active), "active")
+	ch <- prometheus.MustNewConstMetric(flagsDesc, prometheus.GaugeValue, float64(counts.Archived), "archived")
+	for env, n := range counts.Enabled {
+		ch <- prometheus.MustNewConstMetric(enabledFlagsDesc, prometheus.GaugeValue, float64(n), string(env))
+	}
+}
+
+var (
+	cacheHitsDesc = prometheus.NewDesc("storage_cache_hits_total",
+		"Storage lookups answered from the cache by kind of record.", []string{"kind"}, nil)
+	cacheMissesDesc = prometheus.NewDesc("storage_cache_misses_total",
+		"Storage lookups passed on to the database by kind of record.", []string{"kind"}, nil)
+)
+
+type cacheCollector struct {
+	cache *cache.Storage
+}
+
+// RegisterCacheStats exposes the hit and miss counts of the storage cache
+func RegisterCacheStats(c *cache.Storage) {
+	prometheus.MustRegister(&cacheCollector{cache: c})
+}
+
+func (c *cacheCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- cacheHitsDesc
+	ch <- cacheMissesDesc
+}
+
+func (c *cacheCollector) Collect(ch chan<- prometheus.Metric) {
+	for kind, st := range c.cache.Stats() {
+		ch <- prometheus.MustNewConstMetric(cacheHitsDesc, prometheus.CounterValue, float64(st.Hits), kind)
+		ch <- prometheus.MustNewConstMetric(cacheMissesDesc, prometheus.CounterValue, float64(st.Misses), kind)
+	}
+}
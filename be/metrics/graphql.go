@@ -0,0 +1,64 @@
+package metrics
+
+import (
+	"context"
+	"time"
+
+	"github.com/99designs/gqlgen/graphql"
+	"github.com/vektah/gqlparser/v2/ast"
+)
+
+// GraphQL is a gqlgen extension recording every operation by its root field
+type GraphQL struct{}
+
+var (
+	_ graphql.HandlerExtension    = GraphQL{}
+	_ graphql.ResponseInterceptor = GraphQL{}
+)
+
+func (GraphQL) ExtensionName() string {
+	return "Metrics"
+}
+
+func (GraphQL) Validate(graphql.ExecutableSchema) error {
+	return nil
+}
+
+// InterceptResponse times the operation, labelled by its first root field: operation names
+// are chosen by clients and would let them create any number of series
+func (GraphQL) InterceptResponse(ctx context.Context, next graphql.ResponseHandler) *graphql.Response {
+	if !graphql.HasOperationContext(ctx) {
+		return next(ctx)
+	}
+
+	oc := graphql.GetOperationContext(ctx)
+	resp := next(ctx)
+
+	name, kind := rootField(oc), "unknown"
+	if oc.Operation != nil {
+		kind = string(oc.Operation.Operation)
+	}
+
+	status := "ok"
+	if resp == nil || len(resp.Errors) > 0 {
+		status = "error"
+	}
+
+	graphqlOperations.WithLabelValues(name, kind, status).Inc()
+	graphqlDuration.WithLabelValues(name, kind).Observe(time.Since(oc.Stats.OperationStart).Seconds())
+	return resp
+}
+
+// rootField names the operation after the schema field it selects first, fields that were not
+// validated against the schema and selections through fragments are counted as "other"
+func rootField(oc *graphql.OperationContext) string {
+	if oc.Operation == nil {
+		return "unknown"
+	}
+	for _, sel := range oc.Operation.SelectionSet {
+		if field, ok := sel.(*ast.Field); ok && field.Definition != nil {
+			return field.Definition.Name
+		}
+	}
+	return "other"
+}
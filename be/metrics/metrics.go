@@ -0,0 +1,66 @@
+package metrics
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+var (
+	httpDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "http_request_duration_seconds",
+		Help:    "Latency of HTTP requests by route and status code.",
+		Buckets: prometheus.DefBuckets,
+	}, []string{"method", "route", "status"})
+
+	graphqlOperations = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "graphql_operations_total",
+		Help: "GraphQL operations by root field, type and outcome.",
+	}, []string{"operation", "type", "status"})
+
+	graphqlDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "graphql_operation_duration_seconds",
+		Help:    "Latency of GraphQL operations by root field and type.",
+		Buckets: prometheus.DefBuckets,
+	}, []string{"operation", "type"})
+
+	storageDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "storage_call_duration_seconds",
+		Help:    "Latency of storage calls by method and outcome.",
+		Buckets: []float64{.0001, .0005, .001, .0025, .005, .01, .025, .05, .1, .25, .5, 1},
+	}, []string{"method", "status"})
+
+	flagToggles = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "feature_flag_toggles_total",
+		Help: "Toggle state changes by project, environment and new state.",
+	}, []string{"project", "environment", "enabled"})
+)
+
+func init() {
+	prometheus.MustRegister(httpDuration, graphqlOperations, graphqlDuration, storageDuration, flagToggles)
+}
+
+// Handler serves the registered metrics in the Prometheus text format
+func Handler() http.Handler {
+	return promhttp.Handler()
+}
+
+// Middleware records the latency and status of every request under its route pattern,
+// requests that match no route share one label so random paths cannot blow up the series
+func Middleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		start := time.Now()
+		c.Next()
+
+		route := c.FullPath()
+		if route == "" {
+			route = "unmatched"
+		}
+		httpDuration.WithLabelValues(c.Request.Method, route, strconv.Itoa(c.Writer.Status())).
+			Observe(time.Since(start).Seconds())
+	}
+}
@@ -0,0 +1,663 @@
+package metrics
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"time"
+
+	"github.com/shubham-tomar/feature-toggler/db"
+	"github.com/shubham-tomar/feature-toggler/graphQl/model"
+)
+
+// Storage times every call to the storage it wraps
+type Storage struct {
+	next db.Storage
+}
+
+var _ db.Storage = (*Storage)(nil)
+
+// NewStorage wraps a storage so its calls show up in the storage metrics
+func NewStorage(next db.Storage) *Storage {
+	return &Storage{next: next}
+}
+
+// observe records a call, a missing record is an answer rather than a failure
+func (s *Storage) observe(method string, start time.Time, err *error) {
+	status := "ok"
+	if *err != nil && !errors.Is(*err, db.ErrNotFound) {
+		status = "error"
+	}
+	storageDuration.WithLabelValues(method, status).Observe(time.Since(start).Seconds())
+}
+
+// countToggle counts a flag turned on or off, writes that leave the state as it was are not toggles
+func countToggle(projectID string, env model.Environment, previous *bool, enabled bool) {
+	// A state that was not configured serves off
+	if (previous == nil && !enabled) || (previous != nil && *previous == enabled) {
+		return
+	}
+	flagToggles.WithLabelValues(projectID, string(env), strconv.FormatBool(enabled)).Inc()
+}
+
+func (s *Storage) Connect() (err error) {
+	defer s.observe("Connect", time.Now(), &err)
+	return s.next.Connect()
+}
+
+func (s *Storage) Close() (err error) {
+	defer s.observe("Close", time.Now(), &err)
+	return s.next.Close()
+}
+
+func (s *Storage) Ping(ctx context.Context) (err error) {
+	defer s.observe("Ping", time.Now(), &err)
+	return s.next.Ping(ctx)
+}
+
+func (s *Storage) CreateUser(ctx context.Context, user *model.User) (err error) {
+	defer s.observe("CreateUser", time.Now(), &err)
+	return s.next.CreateUser(ctx, user)
+}
+
+func (s *Storage) GetUserByID(ctx context.Context, id string) (v *model.User, err error) {
+	defer s.observe("GetUserByID", time.Now(), &err)
+	return s.next.GetUserByID(ctx, id)
+}
+
+func (s *Storage) GetUserByEmail(ctx context.Context, email string) (v *model.User, err error) {
+	defer s.observe("GetUserByEmail", time.Now(), &err)
+	return s.next.GetUserByEmail(ctx, email)
+}
+
+func (s *Storage) UpdateUser(ctx context.Context, user *model.User) (err error) {
+	defer s.observe("UpdateUser", time.Now(), &err)
+	return s.next.UpdateUser(ctx, user)
+}
+
+func (s *Storage) GetServiceAccounts(ctx context.Context) (v []*model.User, err error) {
+	defer s.observe("GetServiceAccounts", time.Now(), &err)
+	return s.next.GetServiceAccounts(ctx)
+}
+
+func (s *Storage) CreateProject(ctx context.Context, user *model.User, name string) (v *model.Project, err error) {
+	defer s.observe("CreateProject", time.Now(), &err)
+	return s.next.CreateProject(ctx, user, name)
+}
+
+func (s *Storage) GetProjectByID(ctx context.Context, id string) (v *model.Project, err error) {
+	defer s.observe("GetProjectByID", time.Now(), &err)
+	return s.next.GetProjectByID(ctx, id)
+}
+
+func (s *Storage) GetUserProjects(ctx context.Context, user *model.User) (v []*model.Project, err error) {
+	defer s.observe("GetUserProjects", time.Now(), &err)
+	return s.next.GetUserProjects(ctx, user)
+}
+
+func (s *Storage) GetProjects(ctx context.Context, page db.Page) (v []*model.Project, n int, err error) {
+	defer s.observe("GetProjects", time.Now(), &err)
+	return s.next.GetProjects(ctx, page)
+}
+
+func (s *Storage) UpdateProject(ctx context.Context, project *model.Project) (err error) {
+	defer s.observe("UpdateProject", time.Now(), &err)
+	return s.next.UpdateProject(ctx, project)
+}
+
+func (s *Storage) UpdateProjectProtection(ctx context.Context, projectID string, change *model.ProtectionChange) (err error) {
+	defer s.observe("UpdateProjectProtection", time.Now(), &err)
+	return s.next.UpdateProjectProtection(ctx, projectID, change)
+}
+
+func (s *Storage) GetProtectionChanges(ctx context.Context, projectID string) (v []*model.ProtectionChange, err error) {
+	defer s.observe("GetProtectionChanges", time.Now(), &err)
+	return s.next.GetProtectionChanges(ctx, projectID)
+}
+
+func (s *Storage) DeleteProject(ctx context.Context, id string) (err error) {
+	defer s.observe("DeleteProject", time.Now(), &err)
+	return s.next.DeleteProject(ctx, id)
+}
+
+func (s *Storage) AddProjectMember(ctx context.Context, membership *model.ProjectUser) (err error) {
+	defer s.observe("AddProjectMember", time.Now(), &err)
+	return s.next.AddProjectMember(ctx, membership)
+}
+
+func (s *Storage) UpdateProjectMemberRole(ctx context.Context, membershipID string, role model.Role) (err error) {
+	defer s.observe("UpdateProjectMemberRole", time.Now(), &err)
+	return s.next.UpdateProjectMemberRole(ctx, membershipID, role)
+}
+
+func (s *Storage) RemoveProjectMember(ctx context.Context, membershipID string) (err error) {
+	defer s.observe("RemoveProjectMember", time.Now(), &err)
+	return s.next.RemoveProjectMember(ctx, membershipID)
+}
+
+func (s *Storage) GetProjectMember(ctx context.Context, membershipID string) (v *model.ProjectUser, err error) {
+	defer s.observe("GetProjectMember", time.Now(), &err)
+	return s.next.GetProjectMember(ctx, membershipID)
+}
+
+func (s *Storage) GetProjectMembers(ctx context.Context, projectID string) (v []*model.ProjectUser, err error) {
+	defer s.observe("GetProjectMembers", time.Now(), &err)
+	return s.next.GetProjectMembers(ctx, projectID)
+}
+
+func (s *Storage) GetUserMemberships(ctx context.Context, userID string) (v []*model.ProjectUser, err error) {
+	defer s.observe("GetUserMemberships", time.Now(), &err)
+	return s.next.GetUserMemberships(ctx, userID)
+}
+
+func (s *Storage) CreateInvitation(ctx context.Context, invitation *model.ProjectInvitation) (err error) {
+	defer s.observe("CreateInvitation", time.Now(), &err)
+	return s.next.CreateInvitation(ctx, invitation)
+}
+
+func (s *Storage) GetInvitationByID(ctx context.Context, id string) (v *model.ProjectInvitation, err error) {
+	defer s.observe("GetInvitationByID", time.Now(), &err)
+	return s.next.GetInvitationByID(ctx, id)
+}
+
+func (s *Storage) GetProjectInvitations(ctx context.Context, projectID string) (v []*model.ProjectInvitation, err error) {
+	defer s.observe("GetProjectInvitations", time.Now(), &err)
+	return s.next.GetProjectInvitations(ctx, projectID)
+}
+
+func (s *Storage) GetInvitationsByEmail(ctx context.Context, email string) (v []*model.ProjectInvitation, err error) {
+	defer s.observe("GetInvitationsByEmail", time.Now(), &err)
+	return s.next.GetInvitationsByEmail(ctx, email)
+}
+
+func (s *Storage) AcceptInvitation(ctx context.Context, invitationID string, user *model.User) (v *model.ProjectUser, err error) {
+	defer s.observe("AcceptInvitation", time.Now(), &err)
+	return s.next.AcceptInvitation(ctx, invitationID, user)
+}
+
+func (s *Storage) UpdateInvitationStatus(ctx context.Context, invitationID string, status model.InvitationStatus) (err error) {
+	defer s.observe("UpdateInvitationStatus", time.Now(), &err)
+	return s.next.UpdateInvitationStatus(ctx, invitationID, status)
+}
+
+func (s *Storage) CreateFeatureFlag(ctx context.Context, flag *model.FeatureFlag, initialStates []*model.ToggleState) (err error) {
+	defer s.observe("CreateFeatureFlag", time.Now(), &err)
+	return s.next.CreateFeatureFlag(ctx, flag, initialStates)
+}
+
+func (s *Storage) GetFeatureFlagByID(ctx context.Context, id string) (v *model.FeatureFlag, err error) {
+	defer s.observe("GetFeatureFlagByID", time.Now(), &err)
+	return s.next.GetFeatureFlagByID(ctx, id)
+}
+
+func (s *Storage) GetFeatureFlagByKey(ctx context.Context, projectID, key string) (v *model.FeatureFlag, err error) {
+	defer s.observe("GetFeatureFlagByKey", time.Now(), &err)
+	return s.next.GetFeatureFlagByKey(ctx, projectID, key)
+}
+
+func (s *Storage) GetProjectFeatureFlags(ctx context.Context, projectID string) (v []*model.FeatureFlag, err error) {
+	defer s.observe("GetProjectFeatureFlags", time.Now(), &err)
+	return s.next.GetProjectFeatureFlags(ctx, projectID)
+}
+
+func (s *Storage) QueryFeatureFlags(ctx context.Context, projectID string, filter model.FeatureFlagFilter, order model.FeatureFlagOrder, page db.Page) (v []*model.FeatureFlag, n int, err error) {
+	defer s.observe("QueryFeatureFlags", time.Now(), &err)
+	return s.next.QueryFeatureFlags(ctx, projectID, filter, order, page)
+}
+
+func (s *Storage) UpdateFeatureFlag(ctx context.Context, flag *model.FeatureFlag) (err error) {
+	defer s.observe("UpdateFeatureFlag", time.Now(), &err)
+	return s.next.UpdateFeatureFlag(ctx, flag)
+}
+
+func (s *Storage) ArchiveFeatureFlag(ctx context.Context, id string) (err error) {
+	defer s.observe("ArchiveFeatureFlag", time.Now(), &err)
+	return s.next.ArchiveFeatureFlag(ctx, id)
+}
+
+func (s *Storage) RestoreFeatureFlag(ctx context.Context, id string) (err error) {
+	defer s.observe("RestoreFeatureFlag", time.Now(), &err)
+	return s.next.RestoreFeatureFlag(ctx, id)
+}
+
+func (s *Storage) DeleteFeatureFlag(ctx context.Context, id string) (err error) {
+	defer s.observe("DeleteFeatureFlag", time.Now(), &err)
+	return s.next.DeleteFeatureFlag(ctx, id)
+}
+
+func (s *Storage) CountFeatureFlags(ctx context.Context) (v *db.FlagCounts, err error) {
+	defer s.observe("CountFeatureFlags", time.Now(), &err)
+	return s.next.CountFeatureFlags(ctx)
+}
+
+func (s *Storage) GetFeatureFlagStates(ctx context.Context, flagID string) (v []*model.ToggleState, err error) {
+	defer s.observe("GetFeatureFlagStates", time.Now(), &err)
+	return s.next.GetFeatureFlagStates(ctx, flagID)
+}
+
+// UpdateFeatureFlagState also counts the toggle, if the state was turned on or off. The state it
+// replaces is read first, so a concurrent toggle of the same state may be counted differently.
+func (s *Storage) UpdateFeatureFlagState(ctx context.Context, state *model.ToggleState) (err error) {
+	defer s.observe("UpdateFeatureFlagState", time.Now(), &err)
+
+	var flag *model.FeatureFlag
+	if state.FeatureFlag != nil {
+		flag, _ = s.next.GetFeatureFlagByID(ctx, state.FeatureFlag.ID)
+	}
+	if err := s.next.UpdateFeatureFlagState(ctx, state); err != nil {
+		return err
+	}
+
+	if flag != nil {
+		var previous *bool
+		for _, current := range flag.States {
+			if current.Environment == state.Environment {
+				enabled := current.Enabled
+				previous = &enabled
+			}
+		}
+		countToggle(flag.Project.ID, state.Environment, previous, state.Enabled)
+	}
+	return nil
+}
+
+func (s *Storage) AddIndividualTargets(ctx context.Context, state *model.ToggleState, variant string, contextKeys []string) (n int, err error) {
+	defer s.observe("AddIndividualTargets", time.Now(), &err)
+	return s.next.AddIndividualTargets(ctx, state, variant, contextKeys)
+}
+
+func (s *Storage) RemoveIndividualTargets(ctx context.Context, state *model.ToggleState, contextKeys []string) (n int, err error) {
+	defer s.observe("RemoveIndividualTargets", time.Now(), &err)
+	return s.next.RemoveIndividualTargets(ctx, state, contextKeys)
+}
+
+func (s *Storage) GetIndividualTargets(ctx context.Context, flagID string, environment model.Environment, variant *string, page db.Page) (v []*model.IndividualTarget, n int, err error) {
+	defer s.observe("GetIndividualTargets", time.Now(), &err)
+	return s.next.GetIndividualTargets(ctx, flagID, environment, variant, page)
+}
+
+func (s *Storage) GetContextTargets(ctx context.Context, projectID string, environment model.Environment, contextKey string) (v map[string]string, err error) {
+	defer s.observe("GetContextTargets", time.Now(), &err)
+	return s.next.GetContextTargets(ctx, projectID, environment, contextKey)
+}
+
+func (s *Storage) CreatePromotion(ctx context.Context, promotion *model.Promotion) (err error) {
+	defer s.observe("CreatePromotion", time.Now(), &err)
+	return s.next.CreatePromotion(ctx, promotion)
+}
+
+func (s *Storage) GetPromotionByID(ctx context.Context, id string) (v *model.Promotion, err error) {
+	defer s.observe("GetPromotionByID", time.Now(), &err)
+	return s.next.GetPromotionByID(ctx, id)
+}
+
+func (s *Storage) GetProjectPromotions(ctx context.Context, projectID string, status *model.PromotionStatus) (v []*model.Promotion, err error) {
+	defer s.observe("GetProjectPromotions", time.Now(), &err)
+	return s.next.GetProjectPromotions(ctx, projectID, status)
+}
+
+func (s *Storage) ApplyPromotion(ctx context.Context, promotion *model.Promotion) (err error) {
+	defer s.observe("ApplyPromotion", time.Now(), &err)
+	if err := s.next.ApplyPromotion(ctx, promotion); err != nil {
+		return err
+	}
+
+	for _, change := range promotion.Changes {
+		countToggle(promotion.Project.ID, promotion.To, change.EnabledBefore, change.EnabledAfter)
+	}
+	return nil
+}
+
+func (s *Storage) RejectPromotion(ctx context.Context, promotion *model.Promotion) (err error) {
+	defer s.observe("RejectPromotion", time.Now(), &err)
+	return s.next.RejectPromotion(ctx, promotion)
+}
+
+func (s *Storage) CreateChangeSet(ctx context.Context, changeSet *model.ChangeSet) (err error) {
+	defer s.observe("CreateChangeSet", time.Now(), &err)
+	return s.next.CreateChangeSet(ctx, changeSet)
+}
+
+func (s *Storage) GetChangeSetByID(ctx context.Context, id string) (v *model.ChangeSet, err error) {
+	defer s.observe("GetChangeSetByID", time.Now(), &err)
+	return s.next.GetChangeSetByID(ctx, id)
+}
+
+func (s *Storage) GetProjectChangeSets(ctx context.Context, projectID string, status *model.ChangeSetStatus) (v []*model.ChangeSet, err error) {
+	defer s.observe("GetProjectChangeSets", time.Now(), &err)
+	return s.next.GetProjectChangeSets(ctx, projectID, status)
+}
+
+func (s *Storage) GetDueChangeSets(ctx context.Context, now time.Time) (v []*model.ChangeSet, err error) {
+	defer s.observe("GetDueChangeSets", time.Now(), &err)
+	return s.next.GetDueChangeSets(ctx, now)
+}
+
+func (s *Storage) UpdateChangeSetStatus(ctx context.Context, changeSet *model.ChangeSet, expected []model.ChangeSetStatus) (err error) {
+	defer s.observe("UpdateChangeSetStatus", time.Now(), &err)
+	return s.next.UpdateChangeSetStatus(ctx, changeSet, expected)
+}
+
+func (s *Storage) ApplyChangeSet(ctx context.Context, changeSet *model.ChangeSet, expected []model.ChangeSetStatus) (err error) {
+	defer s.observe("ApplyChangeSet", time.Now(), &err)
+	if err := s.next.ApplyChangeSet(ctx, changeSet, expected); err != nil {
+		return err
+	}
+
+	for _, change := range changeSet.Changes {
+		if change.Enabled != nil {
+			countToggle(changeSet.Project.ID, change.Environment, change.PreviousEnabled, *change.Enabled)
+		}
+	}
+	return nil
+}
+
+func (s *Storage) RevertChangeSet(ctx context.Context, changeSet *model.ChangeSet) (err error) {
+	defer s.observe("RevertChangeSet", time.Now(), &err)
+	if err := s.next.RevertChangeSet(ctx, changeSet); err != nil {
+		return err
+	}
+
+	// Each change goes back from the state it applied to the one it replaced
+	for _, change := range changeSet.Changes {
+		if change.Enabled != nil && change.PreviousEnabled != nil {
+			countToggle(changeSet.Project.ID, change.Environment, change.Enabled, *change.PreviousEnabled)
+		}
+	}
+	return nil
+}
+
+func (s *Storage) DeleteChangeSet(ctx context.Context, id string) (err error) {
+	defer s.observe("DeleteChangeSet", time.Now(), &err)
+	return s.next.DeleteChangeSet(ctx, id)
+}
+
+func (s *Storage) RecordEvaluations(ctx context.Context, counts []*db.EvaluationCount, impressions []*db.Impression) (err error) {
+	defer s.observe("RecordEvaluations", time.Now(), &err)
+	return s.next.RecordEvaluations(ctx, counts, impressions)
+}
+
+func (s *Storage) GetEvaluationCounts(ctx context.Context, flagID string, environment *model.Environment, since time.Time) (v []*db.EvaluationCount, err error) {
+	defer s.observe("GetEvaluationCounts", time.Now(), &err)
+	return s.next.GetEvaluationCounts(ctx, flagID, environment, since)
+}
+
+func (s *Storage) GetLastEvaluations(ctx context.Context, flagIDs []string) (v map[string]time.Time, err error) {
+	defer s.observe("GetLastEvaluations", time.Now(), &err)
+	return s.next.GetLastEvaluations(ctx, flagIDs)
+}
+
+func (s *Storage) CountImpressions(ctx context.Context, flagID string, environment *model.Environment, since time.Time) (v int, err error) {
+	defer s.observe("CountImpressions", time.Now(), &err)
+	return s.next.CountImpressions(ctx, flagID, environment, since)
+}
+
+func (s *Storage) DeleteEvaluationsBefore(ctx context.Context, before time.Time) (err error) {
+	defer s.observe("DeleteEvaluationsBefore", time.Now(), &err)
+	return s.next.DeleteEvaluationsBefore(ctx, before)
+}
+
+func (s *Storage) CreateWebhook(ctx context.Context, hook *model.Webhook, secret string) (err error) {
+	defer s.observe("CreateWebhook", time.Now(), &err)
+	return s.next.CreateWebhook(ctx, hook, secret)
+}
+
+func (s *Storage) GetWebhookByID(ctx context.Context, id string) (v *model.Webhook, err error) {
+	defer s.observe("GetWebhookByID", time.Now(), &err)
+	return s.next.GetWebhookByID(ctx, id)
+}
+
+func (s *Storage) GetProjectWebhooks(ctx context.Context, projectID string) (v []*model.Webhook, err error) {
+	defer s.observe("GetProjectWebhooks", time.Now(), &err)
+	return s.next.GetProjectWebhooks(ctx, projectID)
+}
+
+func (s *Storage) UpdateWebhook(ctx context.Context, hook *model.Webhook, secret *string) (err error) {
+	defer s.observe("UpdateWebhook", time.Now(), &err)
+	return s.next.UpdateWebhook(ctx, hook, secret)
+}
+
+func (s *Storage) DeleteWebhook(ctx context.Context, id string) (err error) {
+	defer s.observe("DeleteWebhook", time.Now(), &err)
+	return s.next.DeleteWebhook(ctx, id)
+}
+
+func (s *Storage) GetWebhookDeliveries(ctx context.Context, webhookID string, status *model.WebhookDeliveryStatus, page db.Page) (v []*model.WebhookDelivery, n int, err error) {
+	defer s.observe("GetWebhookDeliveries", time.Now(), &err)
+	return s.next.GetWebhookDeliveries(ctx, webhookID, status, page)
+}
+
+func (s *Storage) GetWebhookDelivery(ctx context.Context, id string) (v *model.WebhookDelivery, err error) {
+	defer s.observe("GetWebhookDelivery", time.Now(), &err)
+	return s.next.GetWebhookDelivery(ctx, id)
+}
+
+func (s *Storage) RedeliverWebhookDelivery(ctx context.Context, id string) (v *model.WebhookDelivery, err error) {
+	defer s.observe("RedeliverWebhookDelivery", time.Now(), &err)
+	return s.next.RedeliverWebhookDelivery(ctx, id)
+}
+
+func (s *Storage) DispatchWebhookEvents(ctx context.Context, limit int) (n int, err error) {
+	defer s.observe("DispatchWebhookEvents", time.Now(), &err)
+	return s.next.DispatchWebhookEvents(ctx, limit)
+}
+
+func (s *Storage) ClaimWebhookDeliveries(ctx context.Context, now time.Time, lease time.Duration, limit int, skipWebhookIDs []string) (v []*db.PendingDelivery, err error) {
+	defer s.observe("ClaimWebhookDeliveries", time.Now(), &err)
+	return s.next.ClaimWebhookDeliveries(ctx, now, lease, limit, skipWebhookIDs)
+}
+
+func (s *Storage) CompleteWebhookDelivery(ctx context.Context, delivery *model.WebhookDelivery) (err error) {
+	defer s.observe("CompleteWebhookDelivery", time.Now(), &err)
+	return s.next.CompleteWebhookDelivery(ctx, delivery)
+}
+
+func (s *Storage) CreateExperiment(ctx context.Context, experiment *model.Experiment) (err error) {
+	defer s.observe("CreateExperiment", time.Now(), &err)
+	return s.next.CreateExperiment(ctx, experiment)
+}
+
+func (s *Storage) GetExperimentByID(ctx context.Context, id string) (v *model.Experiment, err error) {
+	defer s.observe("GetExperimentByID", time.Now(), &err)
+	return s.next.GetExperimentByID(ctx, id)
+}
+
+func (s *Storage) GetProjectExperiments(ctx context.Context, projectID string, status *model.ExperimentStatus) (v []*model.Experiment, err error) {
+	defer s.observe("GetProjectExperiments", time.Now(), &err)
+	return s.next.GetProjectExperiments(ctx, projectID, status)
+}
+
+func (s *Storage) GetRunningExperiments(ctx context.Context, projectID string, environment model.Environment) (v []*model.Experiment, err error) {
+	defer s.observe("GetRunningExperiments", time.Now(), &err)
+	return s.next.GetRunningExperiments(ctx, projectID, environment)
+}
+
+func (s *Storage) UpdateExperiment(ctx context.Context, experiment *model.Experiment) (err error) {
+	defer s.observe("UpdateExperiment", time.Now(), &err)
+	return s.next.UpdateExperiment(ctx, experiment)
+}
+
+func (s *Storage) StartExperiment(ctx context.Context, id string, layerOffset *int) (err error) {
+	defer s.observe("StartExperiment", time.Now(), &err)
+	return s.next.StartExperiment(ctx, id, layerOffset)
+}
+
+func (s *Storage) StopExperiment(ctx context.Context, id string) (err error) {
+	defer s.observe("StopExperiment", time.Now(), &err)
+	return s.next.StopExperiment(ctx, id)
+}
+
+func (s *Storage) DeleteExperiment(ctx context.Context, id string) (err error) {
+	defer s.observe("DeleteExperiment", time.Now(), &err)
+	return s.next.DeleteExperiment(ctx, id)
+}
+
+func (s *Storage) CreateExperimentLayer(ctx context.Context, layer *model.ExperimentLayer) (err error) {
+	defer s.observe("CreateExperimentLayer", time.Now(), &err)
+	return s.next.CreateExperimentLayer(ctx, layer)
+}
+
+func (s *Storage) GetExperimentLayerByID(ctx context.Context, id string) (v *model.ExperimentLayer, err error) {
+	defer s.observe("GetExperimentLayerByID", time.Now(), &err)
+	return s.next.GetExperimentLayerByID(ctx, id)
+}
+
+func (s *Storage) GetProjectExperimentLayers(ctx context.Context, projectID string) (v []*model.ExperimentLayer, err error) {
+	defer s.observe("GetProjectExperimentLayers", time.Now(), &err)
+	return s.next.GetProjectExperimentLayers(ctx, projectID)
+}
+
+func (s *Storage) GetLayerExperiments(ctx context.Context, layerID string) (v []*model.Experiment, err error) {
+	defer s.observe("GetLayerExperiments", time.Now(), &err)
+	return s.next.GetLayerExperiments(ctx, layerID)
+}
+
+func (s *Storage) UpdateExperimentLayer(ctx context.Context, layer *model.ExperimentLayer) (err error) {
+	defer s.observe("UpdateExperimentLayer", time.Now(), &err)
+	return s.next.UpdateExperimentLayer(ctx, layer)
+}
+
+func (s *Storage) DeleteExperimentLayer(ctx context.Context, id string) (err error) {
+	defer s.observe("DeleteExperimentLayer", time.Now(), &err)
+	return s.next.DeleteExperimentLayer(ctx, id)
+}
+
+func (s *Storage) CreateHoldout(ctx context.Context, holdout *model.Holdout) (err error) {
+	defer s.observe("CreateHoldout", time.Now(), &err)
+	return s.next.CreateHoldout(ctx, holdout)
+}
+
+func (s *Storage) GetHoldoutByID(ctx context.Context, id string) (v *model.Holdout, err error) {
+	defer s.observe("GetHoldoutByID", time.Now(), &err)
+	return s.next.GetHoldoutByID(ctx, id)
+}
+
+func (s *Storage) GetProjectHoldouts(ctx context.Context, projectID string) (v []*model.Holdout, err error) {
+	defer s.observe("GetProjectHoldouts", time.Now(), &err)
+	return s.next.GetProjectHoldouts(ctx, projectID)
+}
+
+func (s *Storage) UpdateHoldout(ctx context.Context, holdout *model.Holdout) (err error) {
+	defer s.observe("UpdateHoldout", time.Now(), &err)
+	return s.next.UpdateHoldout(ctx, holdout)
+}
+
+func (s *Storage) DeleteHoldout(ctx context.Context, id string) (err error) {
+	defer s.observe("DeleteHoldout", time.Now(), &err)
+	return s.next.DeleteHoldout(ctx, id)
+}
+
+func (s *Storage) RecordExposures(ctx context.Context, exposures []*db.Exposure) (err error) {
+	defer s.observe("RecordExposures", time.Now(), &err)
+	return s.next.RecordExposures(ctx, exposures)
+}
+
+func (s *Storage) RecordTrackEvents(ctx context.Context, events []*db.TrackEvent) (err error) {
+	defer s.observe("RecordTrackEvents", time.Now(), &err)
+	return s.next.RecordTrackEvents(ctx, events)
+}
+
+func (s *Storage) CountExposures(ctx context.Context, experimentID string) (v map[string]int, err error) {
+	defer s.observe("CountExposures", time.Now(), &err)
+	return s.next.CountExposures(ctx, experimentID)
+}
+
+func (s *Storage) CountConversions(ctx context.Context, experiment *model.Experiment, eventKey string) (v map[string]int, err error) {
+	defer s.observe("CountConversions", time.Now(), &err)
+	return s.next.CountConversions(ctx, experiment, eventKey)
+}
+
+func (s *Storage) GetUsersByIDs(ctx context.Context, ids []string) (v []*model.User, err error) {
+	defer s.observe("GetUsersByIDs", time.Now(), &err)
+	return s.next.GetUsersByIDs(ctx, ids)
+}
+
+func (s *Storage) GetProjectsByIDs(ctx context.Context, ids []string) (v []*model.Project, err error) {
+	defer s.observe("GetProjectsByIDs", time.Now(), &err)
+	return s.next.GetProjectsByIDs(ctx, ids)
+}
+
+func (s *Storage) GetMembersByProjectIDs(ctx context.Context, projectIDs []string) (v []*model.ProjectUser, err error) {
+	defer s.observe("GetMembersByProjectIDs", time.Now(), &err)
+	return s.next.GetMembersByProjectIDs(ctx, projectIDs)
+}
+
+func (s *Storage) GetStatesByFeatureFlagIDs(ctx context.Context, flagIDs []string) (v []*model.ToggleState, err error) {
+	defer s.observe("GetStatesByFeatureFlagIDs", time.Now(), &err)
+	return s.next.GetStatesByFeatureFlagIDs(ctx, flagIDs)
+}
+
+func (s *Storage) CreateSdkKey(ctx context.Context, key *model.SdkKey, keyHash string) (err error) {
+	defer s.observe("CreateSdkKey", time.Now(), &err)
+	return s.next.CreateSdkKey(ctx, key, keyHash)
+}
+
+func (s *Storage) GetSdkKeyByID(ctx context.Context, id string) (v *model.SdkKey, err error) {
+	defer s.observe("GetSdkKeyByID", time.Now(), &err)
+	return s.next.GetSdkKeyByID(ctx, id)
+}
+
+func (s *Storage) GetSdkKeyByHash(ctx context.Context, keyHash string) (v *model.SdkKey, err error) {
+	defer s.observe("GetSdkKeyByHash", time.Now(), &err)
+	return s.next.GetSdkKeyByHash(ctx, keyHash)
+}
+
+func (s *Storage) GetProjectSdkKeys(ctx context.Context, projectID string, environment *model.Environment) (v []*model.SdkKey, err error) {
+	defer s.observe("GetProjectSdkKeys", time.Now(), &err)
+	return s.next.GetProjectSdkKeys(ctx, projectID, environment)
+}
+
+func (s *Storage) RotateSdkKey(ctx context.Context, oldID string, oldExpiresAt time.Time, key *model.SdkKey, keyHash string) (err error) {
+	defer s.observe("RotateSdkKey", time.Now(), &err)
+	return s.next.RotateSdkKey(ctx, oldID, oldExpiresAt, key, keyHash)
+}
+
+func (s *Storage) RevokeSdkKey(ctx context.Context, id string) (err error) {
+	defer s.observe("RevokeSdkKey", time.Now(), &err)
+	return s.next.RevokeSdkKey(ctx, id)
+}
+
+func (s *Storage) CreateAccessToken(ctx context.Context, token *model.AccessToken, tokenHash string) (err error) {
+	defer s.observe("CreateAccessToken", time.Now(), &err)
+	return s.next.CreateAccessToken(ctx, token, tokenHash)
+}
+
+func (s *Storage) GetAccessTokenByID(ctx context.Context, id string) (v *model.AccessToken, err error) {
+	defer s.observe("GetAccessTokenByID", time.Now(), &err)
+	return s.next.GetAccessTokenByID(ctx, id)
+}
+
+func (s *Storage) GetAccessTokenByHash(ctx context.Context, tokenHash string) (v *model.AccessToken, err error) {
+	defer s.observe("GetAccessTokenByHash", time.Now(), &err)
+	return s.next.GetAccessTokenByHash(ctx, tokenHash)
+}
+
+func (s *Storage) GetUserAccessTokens(ctx context.Context, userID string) (v []*model.AccessToken, err error) {
+	defer s.observe("GetUserAccessTokens", time.Now(), &err)
+	return s.next.GetUserAccessTokens(ctx, userID)
+}
+
+func (s *Storage) TouchAccessToken(ctx context.Context, id string, usedAt time.Time) (err error) {
+	defer s.observe("TouchAccessToken", time.Now(), &err)
+	return s.next.TouchAccessToken(ctx, id, usedAt)
+}
+
+func (s *Storage) RevokeAccessToken(ctx context.Context, id string) (err error) {
+	defer s.observe("RevokeAccessToken", time.Now(), &err)
+	return s.next.RevokeAccessToken(ctx, id)
+}
+
+func (s *Storage) CreateSession(ctx context.Context, userID string, tokenHash string, expiresAt time.Time) (err error) {
+	defer s.observe("CreateSession", time.Now(), &err)
+	return s.next.CreateSession(ctx, userID, tokenHash, expiresAt)
+}
+
+func (s *Storage) GetSessionUser(ctx context.Context, tokenHash string) (v *model.User, err error) {
+	defer s.observe("GetSessionUser", time.Now(), &err)
+	return s.next.GetSessionUser(ctx, tokenHash)
+}
+
+func (s *Storage) DeleteSession(ctx context.Context, tokenHash string) (err error) {
+	defer s.observe("DeleteSession", time.Now(), &err)
+	return s.next.DeleteSession(ctx, tokenHash)
+}
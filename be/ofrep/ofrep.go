@@ -0,0 +1,266 @@
+package ofrep
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/shubham-tomar/feature-toggler/analytics"
+	"github.com/shubham-tomar/feature-toggler/auth"
+	"github.com/shubham-tomar/feature-toggler/evaluation"
+	"github.com/shubham-tomar/feature-toggler/overrides"
+)
+
+// ErrorCode is an OpenFeature error code returned in failed evaluations. Contexts without
+// a targetingKey get the fallthrough value, so TARGETING_KEY_MISSING never occurs.
+type ErrorCode string
+
+const (
+	ErrorParse          ErrorCode = "PARSE_ERROR"
+	ErrorInvalidContext ErrorCode = "INVALID_CONTEXT"
+	ErrorFlagNotFound   ErrorCode = "FLAG_NOT_FOUND"
+	ErrorTypeMismatch   ErrorCode = "TYPE_MISMATCH"
+	ErrorGeneral        ErrorCode = "GENERAL"
+)
+
+// Every flag is boolean, single evaluations resolving one as any type but boolean or object fail with TYPE_MISMATCH
+const flagType = "boolean"
+
+type evaluationRequest struct {
+	Context evaluation.Context `json:"context"`
+	// Type is the OpenFeature type the caller resolves the flag as: boolean, string, integer,
+	// float or object. It is an extension of OFREP, requests without it are not type checked.
+	Type string `json:"type,omitempty"`
+}
+
+type evaluationSuccess struct {
+	Key      string         `json:"key"`
+	Value    any            `json:"value"`
+	Reason   string         `json:"reason"`
+	Variant  string         `json:"variant,omitempty"`
+	Metadata map[string]any `json:"metadata,omitempty"`
+}
+
+type evaluationFailure struct {
+	Key          string    `json:"key,omitempty"`
+	ErrorCode    ErrorCode `json:"errorCode"`
+	ErrorDetails string    `json:"errorDetails,omitempty"`
+}
+
+type bulkEvaluationResponse struct {
+	Flags []evaluationSuccess `json:"flags"`
+}
+
+// Handler serves the OpenFeature Remote Evaluation Protocol endpoints
+type Handler struct {
+	Evaluator *evaluation.Evaluator
+	// Analytics counts evaluations. Only single evaluations expose contexts to experiments,
+	// bulk ones fill client caches with every flag whether the app uses it or not.
+	Analytics *analytics.Recorder
+	// Overrides verifies override tokens, requests carrying none are evaluated as usual
+	Overrides *auth.OverrideSigner
+}
+
+// Register mounts the OFREP routes on the given router group, usually "/ofrep/v1".
+// The group must authenticate requests with auth.RequireSdkKey, the key selects the project and environment.
+func (h *Handler) Register(r gin.IRouter) {
+	r.POST("/evaluate/flags/:key", h.evaluateFlag)
+	r.POST("/evaluate/flags", h.evaluateFlags)
+}
+
+func (h *Handler) evaluateFlag(c *gin.Context) {
+	key := c.Param("key")
+	scope := evaluation.ScopeForKey(auth.SdkKey(c))
+
+	req, code, err := parseRequest(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, evaluationFailure{Key: key, ErrorCode: code, ErrorDetails: err.Error()})
+		return
+	}
+	evalCtx := req.Context
+
+	result, err := h.Evaluator.Evaluate(c.Request.Context(), scope, key, evalCtx)
+	if errors.Is(err, evaluation.ErrFlagNotFound) {
+		c.JSON(http.StatusNotFound, evaluationFailure{Key: key, ErrorCode: ErrorFlagNotFound, ErrorDetails: fmt.Sprintf("flag %q was not found", key)})
+		return
+	}
+	if err != nil {
+		log.Printf("ofrep: failed to evaluate flag %s: %v", key, err)
+		c.JSON(http.StatusInternalServerError, evaluationFailure{Key: key, ErrorCode: ErrorGeneral, ErrorDetails: "failed to evaluate flag"})
+		return
+	}
+
+	// The caller falls back to its default value, so this is not counted as an evaluation
+	if req.Type != "" && req.Type != flagType && req.Type != "object" {
+		c.JSON(http.StatusBadRequest, evaluationFailure{Key: key, ErrorCode: ErrorTypeMismatch, ErrorDetails: fmt.Sprintf("flag %q is a %s flag, not %s", key, flagType, req.Type)})
+		return
+	}
+
+	// Overridden values are previews, they are neither evaluations nor exposures
+	if forced := h.overrides(c, scope); forced[key] != "" {
+		forced.Apply(result)
+		c.JSON(http.StatusOK, success(result))
+		return
+	}
+
+	contextKey, _ := evalCtx.TargetingKey()
+	h.Analytics.Record(analytics.Event{
+		FlagID:      result.FlagID,
+		Environment: scope.Environment,
+		Variant:     result.Variant,
+		Timestamp:   time.Now(),
+		ContextKey:  contextKey,
+		// Only contexts with a targeting key are enrolled, so an exposure always has a context
+		ExperimentID: result.ExperimentID,
+	})
+
+	c.JSON(http.StatusOK, success(result))
+}
+
+func (h *Handler) evaluateFlags(c *gin.Context) {
+	scope := evaluation.ScopeForKey(auth.SdkKey(c))
+
+	// Bulk responses are cached by clients and read with any type, they are not type checked
+	req, code, err := parseRequest(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, evaluationFailure{ErrorCode: code, ErrorDetails: err.Error()})
+		return
+	}
+	evalCtx := req.Context
+
+	results, err := h.Evaluator.EvaluateAll(c.Request.Context(), scope, evalCtx)
+	if err != nil {
+		log.Printf("ofrep: failed to evaluate flags of project %s: %v", scope.ProjectID, err)
+		c.JSON(http.StatusInternalServerError, evaluationFailure{ErrorCode: ErrorGeneral, ErrorDetails: "failed to evaluate flags"})
+		return
+	}
+
+	// Web and mobile providers only evaluate in bulk, counting these is what marks their flags as used
+	forced := h.overrides(c, scope)
+	contextKey, _ := evalCtx.TargetingKey()
+	now := time.Now()
+	events := make([]analytics.Event, 0, len(results))
+	response := bulkEvaluationResponse{Flags: make([]evaluationSuccess, 0, len(results))}
+	for _, result := range results {
+		if forced[result.FlagKey] != "" {
+			forced.Apply(result)
+		} else {
+			events = append(events, analytics.Event{
+				FlagID:      result.FlagID,
+				Environment: scope.Environment,
+				Variant:     result.Variant,
+				Timestamp:   now,
+				ContextKey:  contextKey,
+			})
+		}
+		response.Flags = append(response.Flags, success(result))
+	}
+	h.Analytics.Record(events...)
+
+	body, err := json.Marshal(response)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, evaluationFailure{ErrorCode: ErrorGeneral, ErrorDetails: "failed to encode response"})
+		return
+	}
+
+	// The ETag covers the evaluated values, so it changes whenever any flag or the context does
+	sum := sha256.Sum256(body)
+	etag := `"` + hex.EncodeToString(sum[:16]) + `"`
+	c.Header("ETag", etag)
+
+	if etagMatches(c.GetHeader("If-None-Match"), etag) {
+		c.Status(http.StatusNotModified)
+		return
+	}
+
+	c.Data(http.StatusOK, "application/json", body)
+}
+
+// overrides returns the variants forced by the override token of the request. A token that does not
+// apply is ignored and the reason is sent back in a response header, so a broken preview is visible.
+func (h *Handler) overrides(c *gin.Context, scope evaluation.Scope) evaluation.Overrides {
+	secret := overrides.FromRequest(c.Request)
+	if secret == "" || h.Overrides == nil {
+		return nil
+	}
+
+	token, err := h.Overrides.Verify(secret, time.Now())
+	if err != nil {
+		c.Header(overrides.RejectedHeader, err.Error())
+		return nil
+	}
+
+	if token.ProjectID != scope.ProjectID || token.Environment != scope.Environment {
+		c.Header(overrides.RejectedHeader, "override token is for another project or environment")
+		return nil
+	}
+
+	// Checked on every request, so protecting an environment also disables the tokens already issued
+	project, err := h.Evaluator.Storage.GetProjectByID(c.Request.Context(), scope.ProjectID)
+	if err != nil {
+		log.Printf("ofrep: failed to get project %s: %v", scope.ProjectID, err)
+		c.Header(overrides.RejectedHeader, "failed to check the override token")
+		return nil
+	}
+	if slices.Contains(project.ProtectedEnvironments, scope.Environment) {
+		c.Header(overrides.RejectedHeader, fmt.Sprintf("overrides are disabled in %s", scope.Environment))
+		return nil
+	}
+
+	return evaluation.Overrides(token.Flags)
+}
+
+func parseRequest(c *gin.Context) (evaluationRequest, ErrorCode, error) {
+	var req evaluationRequest
+
+	body, err := c.GetRawData()
+	if err != nil {
+		return req, ErrorGeneral, fmt.Errorf("failed to read request body: %w", err)
+	}
+
+	if len(body) > 0 {
+		if err := json.Unmarshal(body, &req); err != nil {
+			return req, ErrorParse, fmt.Errorf("invalid request body: %w", err)
+		}
+	}
+
+	if req.Context == nil {
+		req.Context = evaluation.Context{}
+	}
+
+	if key, ok := req.Context["targetingKey"]; ok {
+		if _, isString := key.(string); !isString {
+			return req, ErrorInvalidContext, errors.New("targetingKey must be a string")
+		}
+	}
+
+	return req, "", nil
+}
+
+func success(result *evaluation.Result) evaluationSuccess {
+	return evaluationSuccess{
+		Key:      result.FlagKey,
+		Value:    result.Value,
+		Reason:   string(result.Reason),
+		Variant:  result.Variant,
+		Metadata: result.Metadata,
+	}
+}
+
+func etagMatches(ifNoneMatch, etag string) bool {
+	for _, candidate := range strings.Split(ifNoneMatch, ",") {
+		candidate = strings.TrimPrefix(strings.TrimSpace(candidate), "W/")
+		if candidate == etag || candidate == "*" {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,68 @@
+// Package overrides carries flag override tokens from incoming requests to flag evaluations.
+// A token is minted with the createOverrideToken mutation and forces the variant of a few flags,
+// so a preview deployment or a tester sees a change before it is rolled out.
+package overrides
+
+import (
+	"context"
+	"net/http"
+	"strings"
+)
+
+const (
+	// Header carries a token on evaluation requests
+	Header = "X-Flag-Overrides"
+	// Cookie carries a token in browsers, it is set by Middleware from the query parameter
+	Cookie = "ft_overrides"
+	// Param turns a link into a preview link, e.g. https://preview.example.com/?ft_overrides=ft_ovr_...
+	Param = "ft_overrides"
+	// RejectedHeader is set on evaluation responses when a token was ignored, with the reason
+	RejectedHeader = "X-Flag-Overrides-Rejected"
+)
+
+type contextKey struct{}
+
+// WithToken returns a context whose flag evaluations send the token
+func WithToken(ctx context.Context, token string) context.Context {
+	return context.WithValue(ctx, contextKey{}, token)
+}
+
+// TokenFrom returns the token stored on the context, or an empty string
+func TokenFrom(ctx context.Context) string {
+	token, _ := ctx.Value(contextKey{}).(string)
+	return token
+}
+
+// FromRequest returns the token sent in the header, or else in the cookie
+func FromRequest(r *http.Request) string {
+	if token := strings.TrimSpace(r.Header.Get(Header)); token != "" {
+		return token
+	}
+	if cookie, err := r.Cookie(Cookie); err == nil {
+		return cookie.Value
+	}
+	return ""
+}
+
+// Middleware stores the token of each request on its context, so that evaluations made with the
+// request context apply it. Opening a link with the query parameter keeps the token in a session
+// cookie for the following requests; an empty parameter clears it.
+func Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		token := FromRequest(r)
+
+		if values := r.URL.Query(); values.Has(Param) {
+			token = strings.TrimSpace(values.Get(Param))
+			cookie := &http.Cookie{Name: Cookie, Value: token, Path: "/", HttpOnly: true, SameSite: http.SameSiteLaxMode}
+			if token == "" {
+				cookie.MaxAge = -1
+			}
+			http.SetCookie(w, cookie)
+		}
+
+		if token != "" {
+			r = r.WithContext(WithToken(r.Context(), token))
+		}
+		next.ServeHTTP(w, r)
+	})
+}
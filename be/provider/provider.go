@@ -0,0 +1,408 @@
+package provider
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/open-feature/go-sdk/openfeature"
+	"github.com/shubham-tomar/feature-toggler/overrides"
+)
+
+// Name is reported in the provider metadata
+const Name = "feature-toggler"
+
+// Options configures a Provider
+type Options struct {
+	// BaseURL of the feature-toggler server, e.g. "http://localhost:8080"
+	BaseURL string
+	// SDKKey selects the project and environment, client keys only see client-side flags
+	SDKKey string
+	// PollInterval controls how often flags are checked for changes, zero disables change events
+	PollInterval time.Duration
+	HTTPClient   *http.Client
+}
+
+// Provider resolves flags from a feature-toggler server through its OFREP endpoints
+type Provider struct {
+	opts   Options
+	client *http.Client
+	events chan openfeature.Event
+
+	mu       sync.Mutex
+	etag     string
+	snapshot map[string]string
+	stale    bool
+	cancel   context.CancelFunc
+	done     chan struct{}
+}
+
+type evaluationRequest struct {
+	Context map[string]any `json:"context"`
+	// Type lets the server answer TYPE_MISMATCH, bulk evaluations leave it out
+	Type string `json:"type,omitempty"`
+}
+
+type evaluationSuccess struct {
+	Key      string         `json:"key"`
+	Value    any            `json:"value"`
+	Reason   string         `json:"reason"`
+	Variant  string         `json:"variant"`
+	Metadata map[string]any `json:"metadata"`
+}
+
+type evaluationFailure struct {
+	ErrorCode    string `json:"errorCode"`
+	ErrorDetails string `json:"errorDetails"`
+}
+
+type bulkEvaluationResponse struct {
+	Flags []evaluationSuccess `json:"flags"`
+}
+
+// New creates a provider for the project and environment of the SDK key
+func New(opts Options) *Provider {
+	client := opts.HTTPClient
+	if client == nil {
+		client = &http.Client{Timeout: 10 * time.Second}
+	}
+
+	return &Provider{
+		opts:   opts,
+		client: client,
+		events: make(chan openfeature.Event, 5),
+	}
+}
+
+// Metadata returns the provider metadata
+func (p *Provider) Metadata() openfeature.Metadata {
+	return openfeature.Metadata{Name: Name}
+}
+
+// Hooks returns the provider hooks
+func (p *Provider) Hooks() []openfeature.Hook {
+	return nil
+}
+
+// EventChannel returns the channel the provider emits its events on
+func (p *Provider) EventChannel() <-chan openfeature.Event {
+	return p.events
+}
+
+// Init loads the flags once so that the provider is only ready when the server is reachable,
+// then starts polling for changes
+func (p *Provider) Init(evaluationContext openfeature.EvaluationContext) error {
+	globalCtx := evaluationContext.Attributes()
+	if key := evaluationContext.TargetingKey(); key != "" {
+		globalCtx[openfeature.TargetingKey] = key
+	}
+
+	if _, err := p.poll(context.Background(), globalCtx); err != nil {
+		return fmt.Errorf("failed to load flags: %w", err)
+	}
+
+	if p.opts.PollInterval <= 0 {
+		return nil
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	p.mu.Lock()
+	p.cancel, p.done = cancel, done
+	p.mu.Unlock()
+
+	go p.watch(ctx, globalCtx, done)
+	return nil
+}
+
+// Shutdown stops polling for changes
+func (p *Provider) Shutdown() {
+	p.mu.Lock()
+	cancel, done := p.cancel, p.done
+	p.cancel, p.done = nil, nil
+	p.mu.Unlock()
+
+	if cancel != nil {
+		cancel()
+		<-done
+	}
+}
+
+// BooleanEvaluation resolves a boolean flag
+func (p *Provider) BooleanEvaluation(ctx context.Context, flag string, defaultValue bool, flatCtx openfeature.FlattenedContext) openfeature.BoolResolutionDetail {
+	return resolveAs(p.resolve(ctx, flag, "boolean", flatCtx), defaultValue, func(v any) (bool, bool) {
+		b, ok := v.(bool)
+		return b, ok
+	})
+}
+
+// StringEvaluation resolves a string flag
+func (p *Provider) StringEvaluation(ctx context.Context, flag string, defaultValue string, flatCtx openfeature.FlattenedContext) openfeature.StringResolutionDetail {
+	return resolveAs(p.resolve(ctx, flag, "string", flatCtx), defaultValue, func(v any) (string, bool) {
+		s, ok := v.(string)
+		return s, ok
+	})
+}
+
+// FloatEvaluation resolves a number flag as a float
+func (p *Provider) FloatEvaluation(ctx context.Context, flag string, defaultValue float64, flatCtx openfeature.FlattenedContext) openfeature.FloatResolutionDetail {
+	return resolveAs(p.resolve(ctx, flag, "float", flatCtx), defaultValue, func(v any) (float64, bool) {
+		f, ok := v.(float64)
+		return f, ok
+	})
+}
+
+// IntEvaluation resolves a number flag as an integer, rejecting values with a fractional part
+func (p *Provider) IntEvaluation(ctx context.Context, flag string, defaultValue int64, flatCtx openfeature.FlattenedContext) openfeature.IntResolutionDetail {
+	return resolveAs(p.resolve(ctx, flag, "integer", flatCtx), defaultValue, func(v any) (int64, bool) {
+		f, ok := v.(float64)
+		if !ok || f != math.Trunc(f) {
+			return 0, false
+		}
+		return int64(f), true
+	})
+}
+
+// ObjectEvaluation resolves a flag of any type
+func (p *Provider) ObjectEvaluation(ctx context.Context, flag string, defaultValue any, flatCtx openfeature.FlattenedContext) openfeature.InterfaceResolutionDetail {
+	return resolveAs(p.resolve(ctx, flag, "object", flatCtx), defaultValue, func(v any) (any, bool) {
+		return v, true
+	})
+}
+
+type resolution struct {
+	value  any
+	detail openfeature.ProviderResolutionDetail
+}
+
+func resolveAs[T any](res resolution, defaultValue T, convert func(any) (T, bool)) openfeature.GenericResolutionDetail[T] {
+	if res.detail.Error() != nil {
+		return openfeature.GenericResolutionDetail[T]{Value: defaultValue, ProviderResolutionDetail: res.detail}
+	}
+
+	value, ok := convert(res.value)
+	if !ok {
+		return openfeature.GenericResolutionDetail[T]{
+			Value: defaultValue,
+			ProviderResolutionDetail: openfeature.ProviderResolutionDetail{
+				ResolutionError: openfeature.NewTypeMismatchResolutionError(fmt.Sprintf("flag value %v has type %T", res.value, res.value)),
+				Reason:          openfeature.ErrorReason,
+			},
+		}
+	}
+
+	return openfeature.GenericResolutionDetail[T]{Value: value, ProviderResolutionDetail: res.detail}
+}
+
+// resolve evaluates a single flag as the given OpenFeature type. The server rejects types its
+// flags do not have, resolveAs still checks the value for servers that ignore the type.
+func (p *Provider) resolve(ctx context.Context, flag, valueType string, flatCtx openfeature.FlattenedContext) resolution {
+	resp, err := p.post(ctx, "/ofrep/v1/evaluate/flags/"+url.PathEscape(flag), evaluationRequest{Context: serverContext(flatCtx), Type: valueType}, "")
+	if err != nil {
+		return failed(openfeature.NewGeneralResolutionError(err.Error()))
+	}
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+		var success evaluationSuccess
+		if err := json.NewDecoder(resp.Body).Decode(&success); err != nil {
+			return failed(openfeature.NewParseErrorResolutionError(fmt.Sprintf("invalid evaluation response: %v", err)))
+		}
+		return resolution{
+			value: success.Value,
+			detail: openfeature.ProviderResolutionDetail{
+				Reason:       openfeature.Reason(success.Reason),
+				Variant:      success.Variant,
+				FlagMetadata: success.Metadata,
+			},
+		}
+	case http.StatusBadRequest, http.StatusNotFound:
+		var failure evaluationFailure
+		if err := json.NewDecoder(resp.Body).Decode(&failure); err != nil {
+			return failed(openfeature.NewGeneralResolutionError(fmt.Sprintf("evaluation failed with status %d", resp.StatusCode)))
+		}
+		return failed(resolutionError(failure))
+	case http.StatusUnauthorized, http.StatusForbidden:
+		return failed(openfeature.NewGeneralResolutionError("sdk key was rejected"))
+	default:
+		return failed(openfeature.NewGeneralResolutionError(fmt.Sprintf("evaluation failed with status %d", resp.StatusCode)))
+	}
+}
+
+func failed(err openfeature.ResolutionError) resolution {
+	return resolution{
+		detail: openfeature.ProviderResolutionDetail{
+			ResolutionError: err,
+			Reason:          openfeature.ErrorReason,
+		},
+	}
+}
+
+func resolutionError(failure evaluationFailure) openfeature.ResolutionError {
+	switch openfeature.ErrorCode(failure.ErrorCode) {
+	case openfeature.FlagNotFoundCode:
+		return openfeature.NewFlagNotFoundResolutionError(failure.ErrorDetails)
+	case openfeature.ParseErrorCode:
+		return openfeature.NewParseErrorResolutionError(failure.ErrorDetails)
+	case openfeature.TypeMismatchCode:
+		return openfeature.NewTypeMismatchResolutionError(failure.ErrorDetails)
+	case openfeature.TargetingKeyMissingCode:
+		return openfeature.NewTargetingKeyMissingResolutionError(failure.ErrorDetails)
+	case openfeature.InvalidContextCode:
+		return openfeature.NewInvalidContextResolutionError(failure.ErrorDetails)
+	default:
+		return openfeature.NewGeneralResolutionError(failure.ErrorDetails)
+	}
+}
+
+func (p *Provider) post(ctx context.Context, path string, request evaluationRequest, etag string) (*http.Response, error) {
+	body, err := json.Marshal(request)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode evaluation context: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, strings.TrimSuffix(p.opts.BaseURL, "/")+path, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+p.opts.SDKKey)
+	if etag != "" {
+		req.Header.Set("If-None-Match", etag)
+	}
+	// Polling runs in the background and never carries a request's overrides
+	if token := overrides.TokenFrom(ctx); token != "" {
+		req.Header.Set(overrides.Header, token)
+	}
+
+	return p.client.Do(req)
+}
+
+// serverContext converts an OpenFeature context to the JSON object the server evaluates against
+func serverContext(flatCtx map[string]any) map[string]any {
+	result := make(map[string]any, len(flatCtx))
+	for key, value := range flatCtx {
+		switch v := value.(type) {
+		case time.Time:
+			result[key] = v.UTC().Format(time.RFC3339)
+		default:
+			result[key] = v
+		}
+	}
+	return result
+}
+
+func (p *Provider) watch(ctx context.Context, globalCtx map[string]any, done chan struct{}) {
+	defer close(done)
+
+	ticker := time.NewTicker(p.opts.PollInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		changed, err := p.poll(ctx, globalCtx)
+		if errors.Is(err, context.Canceled) {
+			return
+		}
+
+		p.mu.Lock()
+		wasStale := p.stale
+		p.stale = err != nil
+		p.mu.Unlock()
+
+		switch {
+		case err != nil && !wasStale:
+			p.emit(openfeature.ProviderStale, err.Error(), nil)
+		case err == nil && wasStale:
+			p.emit(openfeature.ProviderReady, "flags reloaded", nil)
+		}
+
+		if len(changed) > 0 {
+			p.emit(openfeature.ProviderConfigChange, "flags changed", changed)
+		}
+	}
+}
+
+// poll fetches all flags unless the server reports them unchanged and returns the keys that changed
+func (p *Provider) poll(ctx context.Context, globalCtx map[string]any) ([]string, error) {
+	p.mu.Lock()
+	etag := p.etag
+	p.mu.Unlock()
+
+	resp, err := p.post(ctx, "/ofrep/v1/evaluate/flags", evaluationRequest{Context: serverContext(globalCtx)}, etag)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotModified {
+		return nil, nil
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("bulk evaluation failed with status %d", resp.StatusCode)
+	}
+
+	var bulk bulkEvaluationResponse
+	if err := json.NewDecoder(resp.Body).Decode(&bulk); err != nil {
+		return nil, fmt.Errorf("invalid bulk evaluation response: %w", err)
+	}
+
+	snapshot := make(map[string]string, len(bulk.Flags))
+	for _, flag := range bulk.Flags {
+		encoded, _ := json.Marshal(flag)
+		snapshot[flag.Key] = string(encoded)
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	var changed []string
+	if p.snapshot != nil {
+		for key, encoded := range snapshot {
+			if p.snapshot[key] != encoded {
+				changed = append(changed, key)
+			}
+		}
+		for key := range p.snapshot {
+			if _, ok := snapshot[key]; !ok {
+				changed = append(changed, key)
+			}
+		}
+	}
+
+	p.snapshot = snapshot
+	p.etag = resp.Header.Get("ETag")
+	return changed, nil
+}
+
+func (p *Provider) emit(eventType openfeature.EventType, message string, changed []string) {
+	event := openfeature.Event{
+		ProviderName: Name,
+		EventType:    eventType,
+		ProviderEventDetails: openfeature.ProviderEventDetails{
+			Message:     message,
+			FlagChanges: changed,
+		},
+	}
+
+	// Never block polling on a consumer that stopped reading events
+	select {
+	case p.events <- event:
+	default:
+	}
+}
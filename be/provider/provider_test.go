@@ -0,0 +1,144 @@
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/open-feature/go-sdk/openfeature"
+)
+
+// fakeServer serves the bulk OFREP endpoint with an ETag that changes with every update of the flags
+type fakeServer struct {
+	mu          sync.Mutex
+	flags       map[string]bool
+	version     int
+	failing     bool
+	ifNoneMatch []string
+}
+
+func (s *fakeServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.ifNoneMatch = append(s.ifNoneMatch, r.Header.Get("If-None-Match"))
+	if s.failing {
+		w.WriteHeader(http.StatusServiceUnavailable)
+		return
+	}
+
+	etag := fmt.Sprintf(`"v%d"`, s.version)
+	w.Header().Set("ETag", etag)
+	if r.Header.Get("If-None-Match") == etag {
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+
+	var bulk bulkEvaluationResponse
+	for key, value := range s.flags {
+		bulk.Flags = append(bulk.Flags, evaluationSuccess{Key: key, Value: value, Reason: "STATIC"})
+	}
+	json.NewEncoder(w).Encode(bulk)
+}
+
+func (s *fakeServer) update(f func(s *fakeServer)) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	f(s)
+	s.version++
+}
+
+func newFakeServer(t *testing.T, flags map[string]bool) (*fakeServer, *Provider) {
+	t.Helper()
+
+	fake := &fakeServer{flags: flags}
+	server := httptest.NewServer(fake)
+	t.Cleanup(server.Close)
+
+	return fake, New(Options{BaseURL: server.URL, SDKKey: "ft_srv_test"})
+}
+
+func TestPollSendsETag(t *testing.T) {
+	fake, p := newFakeServer(t, map[string]bool{"checkout": true})
+	ctx := context.Background()
+
+	for range 2 {
+		if changed, err := p.poll(ctx, nil); err != nil || changed != nil {
+			t.Fatalf("poll() = %v, %v, want no changes", changed, err)
+		}
+	}
+
+	if want := []string{"", `"v0"`}; !slices.Equal(fake.ifNoneMatch, want) {
+		t.Errorf("If-None-Match headers = %q, want %q", fake.ifNoneMatch, want)
+	}
+	if p.snapshot["checkout"] == "" {
+		t.Error("a 304 response dropped the flags loaded before")
+	}
+}
+
+func TestPollReportsChangedFlags(t *testing.T) {
+	fake, p := newFakeServer(t, map[string]bool{"checkout": true, "search": false, "legacy": true})
+	ctx := context.Background()
+
+	if _, err := p.poll(ctx, nil); err != nil {
+		t.Fatal(err)
+	}
+
+	fake.update(func(s *fakeServer) {
+		s.flags = map[string]bool{"checkout": false, "search": false, "banner": true}
+	})
+
+	changed, err := p.poll(ctx, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	slices.Sort(changed)
+	if want := []string{"banner", "checkout", "legacy"}; !slices.Equal(changed, want) {
+		t.Errorf("poll() = %v, want %v", changed, want)
+	}
+}
+
+func TestWatchEmitsStaleAndReady(t *testing.T) {
+	fake, p := newFakeServer(t, map[string]bool{"checkout": true})
+	p.opts.PollInterval = 5 * time.Millisecond
+
+	if err := p.Init(openfeature.NewTargetlessEvaluationContext(nil)); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(p.Shutdown)
+
+	next := func() openfeature.Event {
+		t.Helper()
+		select {
+		case event := <-p.EventChannel():
+			return event
+		case <-time.After(2 * time.Second):
+			t.Fatal("no event was emitted")
+			return openfeature.Event{}
+		}
+	}
+
+	fake.update(func(s *fakeServer) { s.failing = true })
+	if event := next(); event.EventType != openfeature.ProviderStale {
+		t.Errorf("got %s while the server fails, want %s", event.EventType, openfeature.ProviderStale)
+	}
+
+	fake.update(func(s *fakeServer) {
+		s.failing = false
+		s.flags["checkout"] = false
+	})
+	if event := next(); event.EventType != openfeature.ProviderReady {
+		t.Errorf("got %s once the server is back, want %s", event.EventType, openfeature.ProviderReady)
+	}
+	event := next()
+	if event.EventType != openfeature.ProviderConfigChange || !slices.Equal(event.FlagChanges, []string{"checkout"}) {
+		t.Errorf("got %s for %v, want %s for [checkout]", event.EventType, event.FlagChanges, openfeature.ProviderConfigChange)
+	}
+}
@@ -0,0 +1,157 @@
+// Package simulation evaluates a flag for a sample of contexts under its current and a proposed
+// configuration, so the effect of a change can be seen before it is made.
+package simulation
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+
+	"github.com/shubham-tomar/feature-toggler/evaluation"
+	"github.com/shubham-tomar/feature-toggler/graphQl/model"
+)
+
+// MaxContexts limits the size of a sample, every context is evaluated twice within the request
+const MaxContexts = 10000
+
+// ErrTooManyContexts is returned when a sample holds more than MaxContexts contexts
+var ErrTooManyContexts = fmt.Errorf("a sample can hold at most %d contexts", MaxContexts)
+
+// Config is the configuration of a flag in one environment
+type Config struct {
+	Flag        *model.FeatureFlag // With its states loaded
+	Targets     evaluation.Targets
+	Experiments *evaluation.Experiments
+}
+
+// ParseContexts reads a sample of evaluation contexts. JSON samples are an array of objects,
+// CSV samples a header row of attribute names followed by one context per row; empty cells
+// leave the attribute out.
+func ParseContexts(format model.ContextFormat, r io.Reader) ([]evaluation.Context, error) {
+	switch format {
+	case model.ContextFormatJSON:
+		return parseJSON(r)
+	case model.ContextFormatCSV:
+		return parseCSV(r)
+	}
+	return nil, fmt.Errorf("unsupported format %s", format)
+}
+
+func parseJSON(r io.Reader) ([]evaluation.Context, error) {
+	decoder := json.NewDecoder(r)
+	if token, err := decoder.Token(); err != nil || token != json.Delim('[') {
+		return nil, errors.New("a JSON sample must be an array of context objects")
+	}
+
+	var contexts []evaluation.Context
+	for decoder.More() {
+		if len(contexts) == MaxContexts {
+			return nil, ErrTooManyContexts
+		}
+
+		var evalCtx evaluation.Context
+		if err := decoder.Decode(&evalCtx); err != nil {
+			return nil, fmt.Errorf("context %d is not a JSON object: %w", len(contexts)+1, err)
+		}
+		if evalCtx == nil {
+			evalCtx = evaluation.Context{}
+		}
+		contexts = append(contexts, evalCtx)
+	}
+
+	if _, err := decoder.Token(); err != nil {
+		return nil, fmt.Errorf("invalid JSON sample: %w", err)
+	}
+	return contexts, nil
+}
+
+func parseCSV(r io.Reader) ([]evaluation.Context, error) {
+	reader := csv.NewReader(r)
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err == io.EOF {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("invalid CSV header: %w", err)
+	}
+
+	var contexts []evaluation.Context
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			return contexts, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("invalid CSV sample: %w", err)
+		}
+		if len(contexts) == MaxContexts {
+			return nil, ErrTooManyContexts
+		}
+
+		evalCtx := evaluation.Context{}
+		for i, value := range record {
+			if value != "" {
+				evalCtx[header[i]] = value
+			}
+		}
+		contexts = append(contexts, evalCtx)
+	}
+}
+
+// Run evaluates every context of a sample under both configurations
+func Run(current, proposed Config, env model.Environment, contexts []evaluation.Context) *model.FlagSimulation {
+	simulation := &model.FlagSimulation{
+		Contexts: len(contexts),
+		Changes:  []*model.SimulatedChange{},
+	}
+
+	currentCounts, proposedCounts := map[string]int{}, map[string]int{}
+	for i, evalCtx := range contexts {
+		before := evaluation.Evaluate(current.Flag, env, evalCtx, current.Targets, current.Experiments)
+		after := evaluation.Evaluate(proposed.Flag, env, evalCtx, proposed.Targets, proposed.Experiments)
+		currentCounts[before.Variant]++
+		proposedCounts[after.Variant]++
+
+		if before.Variant != after.Variant {
+			simulation.Changes = append(simulation.Changes, &model.SimulatedChange{
+				Row:      i + 1,
+				Context:  evalCtx,
+				Current:  simulatedValue(before),
+				Proposed: simulatedValue(after),
+			})
+		}
+	}
+
+	simulation.Current = variantCounts(currentCounts)
+	simulation.Proposed = variantCounts(proposedCounts)
+	return simulation
+}
+
+func simulatedValue(result *evaluation.Result) *model.SimulatedValue {
+	return &model.SimulatedValue{
+		Value:   result.Value,
+		Variant: result.Variant,
+		Reason:  string(result.Reason),
+	}
+}
+
+// variantCounts lists the counts most served variant first, ties by name so that results are stable
+func variantCounts(counts map[string]int) []*model.VariantCount {
+	variants := make([]*model.VariantCount, 0, len(counts))
+	for variant, count := range counts {
+		variants = append(variants, &model.VariantCount{Variant: variant, Count: count})
+	}
+
+	sort.Slice(variants, func(i, j int) bool {
+		if variants[i].Count != variants[j].Count {
+			return variants[i].Count > variants[j].Count
+		}
+		return variants[i].Variant < variants[j].Variant
+	})
+	return variants
+}
@@ -21,7 +21,7 @@ func GetEnv(key, fallback string) string {
 	for scanner.Scan() {
 		line := scanner.Text()
 		if strings.Contains(line, "=") {
-			kv := strings.Split(line, "=")
+			kv := strings.SplitN(line, "=", 2)
 			if kv[0] == key {
 				return kv[1]
 			}
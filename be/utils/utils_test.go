@@ -0,0 +1,29 @@
+package utils
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestGetEnv(t *testing.T) {
+	path := filepath.Join(t.TempDir(), ".env")
+	if err := os.WriteFile(path, []byte("PORT=8080\nSIGNING_KEY=c2VjcmV0IGtleQ==\nEMPTY=\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("ENV_FILE", path)
+
+	tests := []struct {
+		key, want string
+	}{
+		{"PORT", "8080"},
+		{"SIGNING_KEY", "c2VjcmV0IGtleQ=="}, // Base64 padding is part of the value
+		{"EMPTY", ""},
+		{"MISSING", "fallback"},
+	}
+	for _, tt := range tests {
+		if got := GetEnv(tt.key, "fallback"); got != tt.want {
+			t.Errorf("GetEnv(%q) = %q, want %q", tt.key, got, tt.want)
+		}
+	}
+}
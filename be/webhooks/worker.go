@@ -0,0 +1,204 @@
+package webhooks
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/shubham-tomar/feature-toggler/db"
+	"github.com/shubham-tomar/feature-toggler/graphQl/model"
+)
+
+const (
+	// MaxAttempts is how often a delivery is tried before it is marked DEAD
+	MaxAttempts = 8
+
+	baseBackoff = 10 * time.Second
+	maxBackoff  = time.Hour
+
+	requestTimeout = 10 * time.Second
+	// A claimed delivery is not picked up again for this long, well past the request timeout.
+	// Deliveries are claimed one at a time, so the lease only has to cover a single request.
+	claimLease = time.Minute
+	batchSize  = 100
+	// Only the start of a response is kept in the delivery log
+	maxErrorLength = 512
+)
+
+// Backoff returns the wait before the next attempt after the given number of failed ones:
+// 10s, 20s, 40s and so on, capped at an hour
+func Backoff(attempts int) time.Duration {
+	d := baseBackoff
+	for i := 1; i < attempts && d < maxBackoff; i++ {
+		d *= 2
+	}
+	return min(d, maxBackoff)
+}
+
+// Worker moves events from the outbox to deliveries and sends the deliveries that are due.
+// Deliveries are sent at least once, receivers deduplicate on the X-Webhook-Id header.
+type Worker struct {
+	storage  db.Storage
+	interval time.Duration
+	client   *http.Client
+	// ctx is cancelled by Stop, so that a run in progress gives up on its request
+	ctx    context.Context
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+func NewWorker(storage db.Storage, interval time.Duration) *Worker {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &Worker{
+		storage:  storage,
+		interval: interval,
+		client: &http.Client{
+			Timeout: requestTimeout,
+			// A redirect is reported as a failure rather than followed, the URL should be fixed instead
+			CheckRedirect: func(*http.Request, []*http.Request) error { return http.ErrUseLastResponse },
+		},
+		ctx:    ctx,
+		cancel: cancel,
+		done:   make(chan struct{}),
+	}
+}
+
+// Start polls once every interval until Stop is called
+func (w *Worker) Start() {
+	go func() {
+		defer close(w.done)
+
+		ticker := time.NewTicker(w.interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ticker.C:
+				if err := w.Run(w.ctx); err != nil && !errors.Is(err, context.Canceled) {
+					log.Printf("webhooks: %v", err)
+				}
+			case <-w.ctx.Done():
+				return
+			}
+		}
+	}()
+}
+
+// Stop cancels the request in flight and waits for the worker to exit, the deliveries left
+// are sent by the next worker
+func (w *Worker) Stop() {
+	w.cancel()
+	<-w.done
+}
+
+// Run dispatches all queued events and sends the deliveries that are due. A hook that fails
+// is not tried again until the next run, so that it does not hold up the deliveries to others.
+func (w *Worker) Run(ctx context.Context) error {
+	for {
+		n, err := w.storage.DispatchWebhookEvents(ctx, batchSize)
+		if err != nil {
+			return fmt.Errorf("failed to dispatch events: %w", err)
+		}
+		if n < batchSize {
+			break
+		}
+	}
+
+	var failing []string
+	for {
+		claimed, err := w.storage.ClaimWebhookDeliveries(ctx, time.Now(), claimLease, 1, failing)
+		if err != nil {
+			return fmt.Errorf("failed to claim deliveries: %w", err)
+		}
+		if len(claimed) == 0 {
+			return nil
+		}
+
+		p := claimed[0]
+		w.attempt(ctx, p)
+
+		// Cancelled mid-request, the attempt does not count and the delivery is retried once its lease runs out
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		if err := w.storage.CompleteWebhookDelivery(ctx, p.Delivery); err != nil {
+			return fmt.Errorf("failed to save delivery %s: %w", p.Delivery.ID, err)
+		}
+		if p.Delivery.Status != model.WebhookDeliveryStatusSucceeded {
+			failing = append(failing, p.Delivery.Webhook.ID)
+		}
+	}
+}
+
+// attempt sends a delivery once and records the outcome on it
+func (w *Worker) attempt(ctx context.Context, p *db.PendingDelivery) {
+	d := p.Delivery
+	now := time.Now()
+	d.Attempts++
+	d.LastAttemptAt = &now
+
+	status, err := w.send(ctx, p, now)
+	d.ResponseStatus = status
+	if err == nil {
+		d.Status = model.WebhookDeliveryStatusSucceeded
+		d.DeliveredAt = &now
+		d.NextAttemptAt = nil
+		d.LastError = nil
+		return
+	}
+
+	message := err.Error()
+	d.LastError = &message
+	if d.Attempts >= MaxAttempts {
+		d.Status = model.WebhookDeliveryStatusDead
+		d.NextAttemptAt = nil
+		log.Printf("webhooks: giving up on delivery %s to %s after %d attempts: %v", d.ID, p.URL, d.Attempts, err)
+		return
+	}
+
+	next := now.Add(Backoff(d.Attempts))
+	d.NextAttemptAt = &next
+}
+
+// send posts the payload, any response other than 2xx is an error
+func (w *Worker) send(ctx context.Context, p *db.PendingDelivery, now time.Time) (*int, error) {
+	body := []byte(p.Delivery.Payload)
+	timestamp := now.Unix()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, p.URL, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "feature-toggler-webhooks")
+	req.Header.Set(HeaderID, p.Delivery.EventID)
+	req.Header.Set(HeaderDelivery, p.Delivery.ID)
+	req.Header.Set(HeaderEvent, string(p.Delivery.EventType))
+	req.Header.Set(HeaderTimestamp, strconv.FormatInt(timestamp, 10))
+	req.Header.Set(HeaderSignature, Sign(p.Secret, timestamp, body))
+
+	resp, err := w.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	status := resp.StatusCode
+	if status >= 200 && status < 300 {
+		io.Copy(io.Discard, io.LimitReader(resp.Body, maxErrorLength))
+		return &status, nil
+	}
+
+	snippet, _ := io.ReadAll(io.LimitReader(resp.Body, maxErrorLength))
+	if len(bytes.TrimSpace(snippet)) == 0 {
+		return &status, fmt.Errorf("unexpected status %d", status)
+	}
+	return &status, fmt.Errorf("unexpected status %d: %s", status, bytes.TrimSpace(snippet))
+}
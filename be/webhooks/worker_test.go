@@ -0,0 +1,124 @@
+package webhooks
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/shubham-tomar/feature-toggler/db"
+	"github.com/shubham-tomar/feature-toggler/db/sqlite"
+	"github.com/shubham-tomar/feature-toggler/db/sqlite/sqlitetest"
+	"github.com/shubham-tomar/feature-toggler/graphQl/model"
+)
+
+// receiver counts the requests it gets and answers them with the handler
+type receiver struct {
+	requests atomic.Int32
+	hook     *model.Webhook
+}
+
+// newReceivers subscribes a receiver per handler to a project, then creates flags in it,
+// so that every receiver has one delivery per flag
+func newReceivers(t *testing.T, flags int, handlers ...http.HandlerFunc) (*sqlite.SQLiteStorage, []*receiver) {
+	t.Helper()
+
+	storage := sqlitetest.New(t)
+	user := sqlitetest.User(t, storage, "admin")
+	project := sqlitetest.Project(t, storage, user, "checkout")
+
+	var receivers []*receiver
+	for _, handler := range handlers {
+		r := &receiver{}
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+			r.requests.Add(1)
+			handler(w, req)
+		}))
+		t.Cleanup(server.Close)
+
+		r.hook = &model.Webhook{Project: project, URL: server.URL, EventTypes: model.AllWebhookEventType, Active: true, CreatedBy: user}
+		if err := storage.CreateWebhook(context.Background(), r.hook, "secret"); err != nil {
+			t.Fatal(err)
+		}
+		receivers = append(receivers, r)
+	}
+
+	for i := range flags {
+		sqlitetest.Flag(t, storage, project, string(rune('a'+i)))
+	}
+
+	return storage, receivers
+}
+
+func deliveries(t *testing.T, storage db.Storage, hook *model.Webhook, status model.WebhookDeliveryStatus) int {
+	t.Helper()
+
+	_, total, err := storage.GetWebhookDeliveries(context.Background(), hook.ID, &status, db.Page{Limit: 100})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return total
+}
+
+func TestRunSkipsFailingHooks(t *testing.T) {
+	storage, receivers := newReceivers(t, 3,
+		func(w http.ResponseWriter, _ *http.Request) { w.WriteHeader(http.StatusInternalServerError) },
+		func(w http.ResponseWriter, _ *http.Request) {},
+	)
+	failing, healthy := receivers[0], receivers[1]
+
+	if err := NewWorker(storage, time.Hour).Run(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	if n := failing.requests.Load(); n != 1 {
+		t.Errorf("the failing hook got %d requests, want 1 per run", n)
+	}
+	if n := deliveries(t, storage, healthy.hook, model.WebhookDeliveryStatusSucceeded); n != 3 {
+		t.Errorf("%d deliveries to the healthy hook succeeded, want 3", n)
+	}
+	if n := deliveries(t, storage, failing.hook, model.WebhookDeliveryStatusPending); n != 3 {
+		t.Errorf("%d deliveries to the failing hook are pending, want 3", n)
+	}
+}
+
+func TestStopCancelsRequests(t *testing.T) {
+	release := make(chan struct{})
+	defer close(release)
+
+	storage, receivers := newReceivers(t, 1, func(w http.ResponseWriter, req *http.Request) {
+		select {
+		case <-release:
+		case <-req.Context().Done():
+		}
+	})
+
+	w := NewWorker(storage, time.Millisecond)
+	w.Start()
+	for receivers[0].requests.Load() == 0 {
+		time.Sleep(time.Millisecond)
+	}
+
+	stopped := make(chan struct{})
+	go func() {
+		w.Stop()
+		close(stopped)
+	}()
+
+	select {
+	case <-stopped:
+	case <-time.After(time.Second):
+		t.Fatal("Stop waited for the request to time out")
+	}
+
+	// The cancelled attempt does not count, the delivery is sent again once its lease runs out
+	pending, _, err := storage.GetWebhookDeliveries(context.Background(), receivers[0].hook.ID, nil, db.Page{Limit: 10})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(pending) != 1 || pending[0].Status != model.WebhookDeliveryStatusPending || pending[0].Attempts != 0 {
+		t.Errorf("got deliveries %+v, want one pending without attempts", pending)
+	}
+}